@@ -0,0 +1,91 @@
+// Package actions implements the provider's Terraform actions, e.g.
+// trueform_vm_power, for day-2 operations on TrueNAS that change nothing
+// Terraform keeps in state: running a cron job now, scrubbing a pool or
+// restarting a service. They need Terraform 1.14 or later.
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+// timeoutAttribute is the schema of the timeout of actions that wait for a job
+var timeoutAttribute = schema.StringAttribute{
+	Description: "How long to wait for the TrueNAS job to finish, as a duration such as \"30m\" or \"2h\". Defaults to the provider's job_timeout.",
+	Optional:    true,
+}
+
+// configureClient returns the API client the provider configured, or nil
+// before the provider is configured
+func configureClient(req action.ConfigureRequest, resp *action.ConfigureResponse) *client.Client {
+	if req.ProviderData == nil {
+		return nil
+	}
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type", fmt.Sprintf("Expected *client.Client, got: %T.", req.ProviderData))
+		return nil
+	}
+	return c
+}
+
+// parseTimeout parses the timeout attribute. Null, unknown and invalid
+// values give zero, which makes WaitForJob use the provider's job_timeout.
+func parseTimeout(value types.String, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() || value.IsUnknown() {
+		return 0
+	}
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil || timeout <= 0 {
+		diags.AddAttributeError(path.Root("timeout"), "Invalid Timeout",
+			fmt.Sprintf("%q is not a positive duration such as \"30m\" or \"2h\".", value.ValueString()))
+		return 0
+	}
+	return timeout
+}
+
+// reportJobProgress returns a callback that sends TrueNAS job progress to
+// Terraform, which shows it while the action runs, and to the log
+func reportJobProgress(ctx context.Context, resp *action.InvokeResponse, operation string) client.JobProgressFunc {
+	return func(p client.JobProgress) {
+		message := fmt.Sprintf("%s: %.0f%%", operation, p.Percent)
+		if p.Description != "" {
+			message += " " + p.Description
+		}
+		tflog.Info(ctx, message, map[string]interface{}{
+			"job_id":      p.JobID,
+			"method":      p.Method,
+			"percent":     p.Percent,
+			"description": p.Description,
+		})
+		if resp.SendProgress != nil {
+			resp.SendProgress(action.InvokeProgressEvent{Message: message})
+		}
+	}
+}
+
+// callJob calls a method and, if it answers with a job ID, waits for the
+// job. Whether some methods, such as vm.poweroff, run as jobs differs
+// between TrueNAS releases; those that don't answer null.
+func callJob(ctx context.Context, c *client.Client, method string, params []interface{}, timeout time.Duration, onProgress client.JobProgressFunc) error {
+	var result interface{}
+	if err := c.Call(ctx, method, params, &result); err != nil {
+		return err
+	}
+	jobID, ok := result.(float64)
+	if !ok {
+		return nil
+	}
+	_, err := c.WaitForJob(ctx, int64(jobID), timeout, onProgress)
+	return err
+}
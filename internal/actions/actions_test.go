@@ -0,0 +1,273 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func newClient(t *testing.T) (*testserver.Server, *client.Client) {
+	t.Helper()
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	c := client.NewClient(&client.Config{
+		Host:    srv.Host(),
+		APIKey:  testserver.APIKey,
+		Timeout: 5 * time.Second,
+	})
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return srv, c
+}
+
+// invoke validates and invokes an action with a configuration of strings,
+// ints and bools, and returns its diagnostics and progress messages
+func invoke(t *testing.T, a action.Action, c *client.Client, values map[string]interface{}) (diag.Diagnostics, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	var configureResp action.ConfigureResponse
+	a.(action.ActionWithConfigure).Configure(ctx, action.ConfigureRequest{ProviderData: c}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure() diagnostics = %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attrs := map[string]tftypes.Value{}
+	for name, typ := range objectType.AttributeTypes {
+		attrs[name] = tftypes.NewValue(typ, values[name])
+	}
+	for name := range values {
+		if _, ok := objectType.AttributeTypes[name]; !ok {
+			t.Fatalf("the schema has no attribute %q", name)
+		}
+	}
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attrs)}
+
+	if v, ok := a.(action.ActionWithValidateConfig); ok {
+		var validateResp action.ValidateConfigResponse
+		v.ValidateConfig(ctx, action.ValidateConfigRequest{Config: config}, &validateResp)
+		if validateResp.Diagnostics.HasError() {
+			return validateResp.Diagnostics, nil
+		}
+	}
+
+	var progress []string
+	resp := action.InvokeResponse{
+		SendProgress: func(event action.InvokeProgressEvent) { progress = append(progress, event.Message) },
+	}
+	a.Invoke(ctx, action.InvokeRequest{Config: config}, &resp)
+	return resp.Diagnostics, progress
+}
+
+// slowJob returns a handler that starts a job at percent and finishes it
+// once the client has looked it up, so the client sees it in progress
+func slowJob(srv *testserver.Server, method string, percent float64, description string) testserver.Handler {
+	return func(params []json.RawMessage) (interface{}, error) {
+		lookups := srv.CallCount("core.get_jobs")
+		id, finish := srv.StartJob(method, params)
+		srv.SetJobProgress(id, percent, description)
+		go func() {
+			for srv.CallCount("core.get_jobs") == lookups {
+				time.Sleep(10 * time.Millisecond)
+			}
+			finish(nil, nil)
+		}()
+		return id, nil
+	}
+}
+
+func TestVMPowerAction(t *testing.T) {
+	srv, c := newClient(t)
+	srv.Seed("vm", map[string]interface{}{"id": 1, "name": "web", "memory": 1024})
+
+	tests := []struct {
+		power string
+		state string
+	}{
+		{power: "start", state: "RUNNING"},
+		{power: "restart", state: "RUNNING"},
+		{power: "stop", state: "STOPPED"},
+		{power: "poweroff", state: "STOPPED"},
+	}
+	for _, tt := range tests {
+		diags, _ := invoke(t, NewVMPowerAction(), c, map[string]interface{}{"vm_id": 1, "power": tt.power})
+		if diags.HasError() {
+			t.Fatalf("%s: diagnostics = %v", tt.power, diags)
+		}
+		vm, _ := srv.Record("vm", 1)
+		if status, _ := vm["status"].(map[string]interface{}); status["state"] != tt.state {
+			t.Errorf("%s: VM status = %v, want %s", tt.power, vm["status"], tt.state)
+		}
+	}
+
+	diags, _ := invoke(t, NewVMPowerAction(), c, map[string]interface{}{"vm_id": 1, "power": "reboot"})
+	if !diags.HasError() || srv.CallCount("vm.reboot") != 0 {
+		t.Errorf("power = reboot: diagnostics = %v, want a validation error", diags)
+	}
+	diags, _ = invoke(t, NewVMPowerAction(), c, map[string]interface{}{"vm_id": 1, "power": "start", "force": true})
+	if !diags.HasError() {
+		t.Error("force with start: want a validation error")
+	}
+	diags, _ = invoke(t, NewVMPowerAction(), c, map[string]interface{}{"vm_id": 2, "power": "start"})
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "Could not start VM 2") {
+		t.Errorf("missing VM: diagnostics = %v", diags)
+	}
+}
+
+func TestPoolScrubAction(t *testing.T) {
+	srv, c := newClient(t)
+	srv.Seed("pool", map[string]interface{}{"id": 1, "name": "tank"})
+
+	diags, _ := invoke(t, NewPoolScrubAction(), c, map[string]interface{}{"pool_id": 1, "timeout": "1m"})
+	if diags.HasError() {
+		t.Fatalf("diagnostics = %v", diags)
+	}
+	if srv.CallCount("pool.scrub") != 1 {
+		t.Errorf("pool.scrub called %d times, want 1", srv.CallCount("pool.scrub"))
+	}
+
+	srv.Handle("pool.scrub", slowJob(srv, "pool.scrub", 42, "Scrubbing"))
+	diags, progress := invoke(t, NewPoolScrubAction(), c, map[string]interface{}{"pool_id": 1})
+	if diags.HasError() {
+		t.Fatalf("diagnostics = %v", diags)
+	}
+	if len(progress) != 1 || progress[0] != "Scrubbing pool: 42% Scrubbing" {
+		t.Errorf("progress = %q, want the scrub's progress", progress)
+	}
+
+	// Without wait the job isn't followed
+	diags, progress = invoke(t, NewPoolScrubAction(), c, map[string]interface{}{"pool_id": 1, "wait": false})
+	if diags.HasError() || len(progress) != 0 {
+		t.Errorf("wait = false: diagnostics = %v, progress = %q", diags, progress)
+	}
+
+	// A scrub outliving the timeout is left running
+	srv.Handle("pool.scrub", func(params []json.RawMessage) (interface{}, error) {
+		id, _ := srv.StartJob("pool.scrub", params)
+		srv.SetJobProgress(id, 10, "Scrubbing")
+		return id, nil
+	})
+	diags, _ = invoke(t, NewPoolScrubAction(), c, map[string]interface{}{"pool_id": 1, "timeout": "100ms"})
+	if diags.HasError() || diags.WarningsCount() != 1 || diags[0].Summary() != "Pool Scrub Still Running" {
+		t.Errorf("timeout: diagnostics = %v, want a warning", diags)
+	}
+	if n := srv.CallCount("core.job_abort"); n != 0 {
+		t.Errorf("core.job_abort called %d times, want 0", n)
+	}
+
+	diags, _ = invoke(t, NewPoolScrubAction(), c, map[string]interface{}{"pool_id": 1, "timeout": "soon"})
+	if !diags.HasError() || diags[0].Summary() != "Invalid Timeout" {
+		t.Errorf("invalid timeout: diagnostics = %v", diags)
+	}
+}
+
+func TestCronjobRunAction(t *testing.T) {
+	srv, c := newClient(t)
+	srv.Seed("cronjob", map[string]interface{}{"id": 3, "user": "root", "command": "/usr/local/bin/backup", "enabled": false})
+
+	var params []json.RawMessage
+	srv.Handle("cronjob.run", func(p []json.RawMessage) (interface{}, error) {
+		params = p
+		return srv.RunJob("cronjob.run", p, func() (interface{}, error) { return nil, nil }), nil
+	})
+
+	diags, _ := invoke(t, NewCronjobRunAction(), c, map[string]interface{}{"cronjob_id": 3, "skip_disabled": true})
+	if diags.HasError() {
+		t.Fatalf("diagnostics = %v", diags)
+	}
+	if len(params) != 2 || string(params[0]) != "3" || string(params[1]) != "true" {
+		t.Errorf("cronjob.run params = %s, want [3 true]", params)
+	}
+
+	srv.Handle("cronjob.run", func(p []json.RawMessage) (interface{}, error) {
+		return srv.RunJob("cronjob.run", p, func() (interface{}, error) {
+			return nil, &testserver.Error{Code: testserver.CodeCallError, Message: "/usr/local/bin/backup: exit status 1"}
+		}), nil
+	})
+	diags, _ = invoke(t, NewCronjobRunAction(), c, map[string]interface{}{"cronjob_id": 3})
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "exit status 1") {
+		t.Errorf("failed job: diagnostics = %v", diags)
+	}
+}
+
+func TestServiceRestartAction(t *testing.T) {
+	srv, c := newClient(t)
+
+	diags, _ := invoke(t, NewServiceRestartAction(), c, map[string]interface{}{"service": "nfs"})
+	if diags.HasError() {
+		t.Fatalf("diagnostics = %v", diags)
+	}
+	if rec, _ := srv.Record("service", "nfs"); rec["state"] != "RUNNING" || srv.CallCount("service.restart") != 1 {
+		t.Errorf("nfs = %v after %d restarts", rec, srv.CallCount("service.restart"))
+	}
+
+	diags, _ = invoke(t, NewServiceRestartAction(), c, map[string]interface{}{"service": "nfs", "reload": true})
+	if diags.HasError() || srv.CallCount("service.reload") != 1 {
+		t.Errorf("reload: diagnostics = %v, %d reloads", diags, srv.CallCount("service.reload"))
+	}
+
+	srv.Handle("service.restart", func(p []json.RawMessage) (interface{}, error) { return false, nil })
+	diags, _ = invoke(t, NewServiceRestartAction(), c, map[string]interface{}{"service": "nfs"})
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "not running") {
+		t.Errorf("service down: diagnostics = %v", diags)
+	}
+
+	// Newer releases run a job
+	srv.Handle("service.restart", slowJob(srv, "service.restart", 0, "Restarting nfs"))
+	diags, progress := invoke(t, NewServiceRestartAction(), c, map[string]interface{}{"service": "nfs"})
+	if diags.HasError() || len(progress) == 0 {
+		t.Errorf("job: diagnostics = %v, progress = %q", diags, progress)
+	}
+}
+
+func TestSnapshotRollbackAction(t *testing.T) {
+	srv, c := newClient(t)
+	srv.Seed("zfs.snapshot", map[string]interface{}{"id": "tank/apps@daily", "name": "tank/apps@daily", "dataset": "tank/apps"})
+
+	var params []json.RawMessage
+	srv.Handle("zfs.snapshot.rollback", func(p []json.RawMessage) (interface{}, error) {
+		params = p
+		return nil, nil
+	})
+	diags, _ := invoke(t, NewSnapshotRollbackAction(), c, map[string]interface{}{"snapshot_id": "tank/apps@daily", "recursive": true})
+	if diags.HasError() {
+		t.Fatalf("diagnostics = %v", diags)
+	}
+	var options map[string]bool
+	if len(params) != 2 || json.Unmarshal(params[1], &options) != nil || !options["recursive"] || options["force"] {
+		t.Errorf("zfs.snapshot.rollback params = %s", params)
+	}
+}
+
+func TestAppRedeployAction(t *testing.T) {
+	srv, c := newClient(t)
+	srv.Seed("app", map[string]interface{}{"id": "nextcloud", "name": "nextcloud", "state": "STOPPED"})
+
+	diags, _ := invoke(t, NewAppRedeployAction(), c, map[string]interface{}{"app_name": "nextcloud"})
+	if diags.HasError() {
+		t.Fatalf("diagnostics = %v", diags)
+	}
+	if app, _ := srv.Record("app", "nextcloud"); app["state"] != "RUNNING" {
+		t.Errorf("app state = %v, want RUNNING", app["state"])
+	}
+
+	diags, _ = invoke(t, NewAppRedeployAction(), c, map[string]interface{}{"app_name": "missing"})
+	if !diags.HasError() {
+		t.Error("missing app: want an error")
+	}
+}
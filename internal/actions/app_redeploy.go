@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+var (
+	_ action.Action                   = &AppRedeployAction{}
+	_ action.ActionWithConfigure      = &AppRedeployAction{}
+	_ action.ActionWithValidateConfig = &AppRedeployAction{}
+)
+
+func NewAppRedeployAction() action.Action {
+	return &AppRedeployAction{}
+}
+
+// AppRedeployAction redeploys an installed app
+type AppRedeployAction struct {
+	client *client.Client
+}
+
+// AppRedeployActionModel describes the action configuration
+type AppRedeployActionModel struct {
+	AppName types.String `tfsdk:"app_name"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+func (a *AppRedeployAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_redeploy"
+}
+
+func (a *AppRedeployAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Redeploys an app on TrueNAS, recreating its containers from its current configuration, e.g. to pull a newer image for the same tag.",
+		Attributes: map[string]schema.Attribute{
+			"app_name": schema.StringAttribute{
+				Description: "The name of the app, e.g. the id of a trueform_app.",
+				Required:    true,
+			},
+			"timeout": timeoutAttribute,
+		},
+	}
+}
+
+func (a *AppRedeployAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureClient(req, resp)
+}
+
+func (a *AppRedeployAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config AppRedeployActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	parseTimeout(config.Timeout, &resp.Diagnostics)
+}
+
+func (a *AppRedeployAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config AppRedeployActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout := parseTimeout(config.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Redeploying app", map[string]interface{}{"name": config.AppName.ValueString()})
+
+	err := callJob(ctx, a.client, "app.redeploy", []interface{}{config.AppName.ValueString()}, timeout, reportJobProgress(ctx, resp, "Redeploying app"))
+	if err != nil {
+		resp.Diagnostics.AddError("Error Redeploying App", fmt.Sprintf("Could not redeploy app %s: %s", config.AppName.ValueString(), err))
+	}
+}
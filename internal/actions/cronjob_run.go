@@ -0,0 +1,88 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+var (
+	_ action.Action                   = &CronjobRunAction{}
+	_ action.ActionWithConfigure      = &CronjobRunAction{}
+	_ action.ActionWithValidateConfig = &CronjobRunAction{}
+)
+
+func NewCronjobRunAction() action.Action {
+	return &CronjobRunAction{}
+}
+
+// CronjobRunAction runs a cron job now, outside its schedule
+type CronjobRunAction struct {
+	client *client.Client
+}
+
+// CronjobRunActionModel describes the action configuration
+type CronjobRunActionModel struct {
+	CronjobID    types.Int64  `tfsdk:"cronjob_id"`
+	SkipDisabled types.Bool   `tfsdk:"skip_disabled"`
+	Timeout      types.String `tfsdk:"timeout"`
+}
+
+func (a *CronjobRunAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cronjob_run"
+}
+
+func (a *CronjobRunAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a cron job on TrueNAS now and waits for its command to finish.",
+		Attributes: map[string]schema.Attribute{
+			"cronjob_id": schema.Int64Attribute{
+				Description: "The ID of the cron job, e.g. the id of a trueform_cronjob.",
+				Required:    true,
+			},
+			"skip_disabled": schema.BoolAttribute{
+				Description: "Do nothing if the cron job is disabled. Defaults to false, which runs it anyway.",
+				Optional:    true,
+			},
+			"timeout": timeoutAttribute,
+		},
+	}
+}
+
+func (a *CronjobRunAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureClient(req, resp)
+}
+
+func (a *CronjobRunAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config CronjobRunActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	parseTimeout(config.Timeout, &resp.Diagnostics)
+}
+
+func (a *CronjobRunAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config CronjobRunActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout := parseTimeout(config.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Running cron job", map[string]interface{}{"id": config.CronjobID.ValueInt64()})
+
+	params := []interface{}{config.CronjobID.ValueInt64(), config.SkipDisabled.ValueBool()}
+	if err := callJob(ctx, a.client, "cronjob.run", params, timeout, reportJobProgress(ctx, resp, "Running cron job")); err != nil {
+		resp.Diagnostics.AddError("Error Running Cron Job", fmt.Sprintf("Could not run cron job %d: %s", config.CronjobID.ValueInt64(), err))
+	}
+}
@@ -0,0 +1,104 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+var (
+	_ action.Action                   = &PoolScrubAction{}
+	_ action.ActionWithConfigure      = &PoolScrubAction{}
+	_ action.ActionWithValidateConfig = &PoolScrubAction{}
+)
+
+func NewPoolScrubAction() action.Action {
+	return &PoolScrubAction{}
+}
+
+// PoolScrubAction scrubs a pool
+type PoolScrubAction struct {
+	client *client.Client
+}
+
+// PoolScrubActionModel describes the action configuration
+type PoolScrubActionModel struct {
+	PoolID  types.Int64  `tfsdk:"pool_id"`
+	Wait    types.Bool   `tfsdk:"wait"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+func (a *PoolScrubAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pool_scrub"
+}
+
+func (a *PoolScrubAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Starts a scrub of a ZFS pool on TrueNAS, which reads and verifies all of its data.",
+		Attributes: map[string]schema.Attribute{
+			"pool_id": schema.Int64Attribute{
+				Description: "The ID of the pool, e.g. the id of a trueform_pool.",
+				Required:    true,
+			},
+			"wait": schema.BoolAttribute{
+				Description: "Whether to wait for the scrub to finish, reporting its progress. Scrubs of large pools take hours, so set timeout too. If the timeout passes first, the action stops waiting with a warning and the scrub continues. Defaults to true.",
+				Optional:    true,
+			},
+			"timeout": timeoutAttribute,
+		},
+	}
+}
+
+func (a *PoolScrubAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureClient(req, resp)
+}
+
+func (a *PoolScrubAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config PoolScrubActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	parseTimeout(config.Timeout, &resp.Diagnostics)
+}
+
+func (a *PoolScrubAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config PoolScrubActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout := parseTimeout(config.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Starting pool scrub", map[string]interface{}{"id": config.PoolID.ValueInt64()})
+
+	// The job runs until the scrub finishes
+	var jobID float64
+	if err := a.client.Call(ctx, "pool.scrub", []interface{}{config.PoolID.ValueInt64(), "START"}, &jobID); err != nil {
+		resp.Diagnostics.AddError("Error Scrubbing Pool", fmt.Sprintf("Could not start a scrub of pool %d: %s", config.PoolID.ValueInt64(), err))
+		return
+	}
+	if !config.Wait.IsNull() && !config.Wait.ValueBool() {
+		return
+	}
+	// Giving up on the wait leaves the scrub running; aborting the job would
+	// stop it
+	_, err := a.client.FollowJob(ctx, int64(jobID), timeout, reportJobProgress(ctx, resp, "Scrubbing pool"))
+	var timeoutErr *client.TimeoutError
+	switch {
+	case errors.As(err, &timeoutErr):
+		resp.Diagnostics.AddWarning("Pool Scrub Still Running", fmt.Sprintf("Stopped waiting for the scrub of pool %d: %s. The scrub continues on TrueNAS.", config.PoolID.ValueInt64(), err))
+	case err != nil:
+		resp.Diagnostics.AddError("Error Scrubbing Pool", fmt.Sprintf("Scrub of pool %d failed: %s", config.PoolID.ValueInt64(), err))
+	}
+}
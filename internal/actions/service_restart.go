@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+var (
+	_ action.Action              = &ServiceRestartAction{}
+	_ action.ActionWithConfigure = &ServiceRestartAction{}
+)
+
+func NewServiceRestartAction() action.Action {
+	return &ServiceRestartAction{}
+}
+
+// ServiceRestartAction restarts or reloads a system service
+type ServiceRestartAction struct {
+	client *client.Client
+}
+
+// ServiceRestartActionModel describes the action configuration
+type ServiceRestartActionModel struct {
+	Service types.String `tfsdk:"service"`
+	Reload  types.Bool   `tfsdk:"reload"`
+}
+
+func (a *ServiceRestartAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_restart"
+}
+
+func (a *ServiceRestartAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Restarts a system service on TrueNAS, e.g. after changing files it reads only at startup. A stopped service is started.",
+		Attributes: map[string]schema.Attribute{
+			"service": schema.StringAttribute{
+				Description: "The name of the service as TrueNAS calls it, e.g. nfs, cifs (SMB), iscsitarget, ssh or ups.",
+				Required:    true,
+			},
+			"reload": schema.BoolAttribute{
+				Description: "Reload the service's configuration instead of restarting it, for services that support it, which keeps clients connected. Defaults to false.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (a *ServiceRestartAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureClient(req, resp)
+}
+
+func (a *ServiceRestartAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config ServiceRestartActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	service := config.Service.ValueString()
+	method, operation := "service.restart", "Restarting service "+service
+	if config.Reload.ValueBool() {
+		method, operation = "service.reload", "Reloading service "+service
+	}
+
+	tflog.Debug(ctx, operation)
+
+	// Older releases answer whether the service is running afterwards,
+	// newer ones run a job
+	var result interface{}
+	err := a.client.Call(ctx, method, []interface{}{service, map[string]interface{}{"silent": false}}, &result)
+	if err == nil {
+		switch result := result.(type) {
+		case bool:
+			if !result {
+				err = fmt.Errorf("the service is not running afterwards, see the TrueNAS logs")
+			}
+		case float64:
+			_, err = a.client.WaitForJob(ctx, int64(result), 0, reportJobProgress(ctx, resp, operation))
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error Restarting Service", fmt.Sprintf("Could not restart service %s: %s", service, err))
+	}
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+var (
+	_ action.Action              = &SnapshotRollbackAction{}
+	_ action.ActionWithConfigure = &SnapshotRollbackAction{}
+)
+
+func NewSnapshotRollbackAction() action.Action {
+	return &SnapshotRollbackAction{}
+}
+
+// SnapshotRollbackAction rolls a dataset back to a snapshot
+type SnapshotRollbackAction struct {
+	client *client.Client
+}
+
+// SnapshotRollbackActionModel describes the action configuration
+type SnapshotRollbackActionModel struct {
+	SnapshotID      types.String `tfsdk:"snapshot_id"`
+	Recursive       types.Bool   `tfsdk:"recursive"`
+	RecursiveClones types.Bool   `tfsdk:"recursive_clones"`
+	Force           types.Bool   `tfsdk:"force"`
+}
+
+func (a *SnapshotRollbackAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot_rollback"
+}
+
+func (a *SnapshotRollbackAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Rolls a dataset or zvol on TrueNAS back to a snapshot, discarding every change made since. This cannot be undone.",
+		Attributes: map[string]schema.Attribute{
+			"snapshot_id": schema.StringAttribute{
+				Description: "The ID of the snapshot, of the form pool/dataset@name, e.g. the id of a trueform_snapshot.",
+				Required:    true,
+			},
+			"recursive": schema.BoolAttribute{
+				Description: "Destroy snapshots of the dataset newer than this one, which ZFS otherwise refuses to roll back past. Defaults to false.",
+				Optional:    true,
+			},
+			"recursive_clones": schema.BoolAttribute{
+				Description: "Like recursive, and also destroy clones of those snapshots. Defaults to false.",
+				Optional:    true,
+			},
+			"force": schema.BoolAttribute{
+				Description: "Unmount the dataset if it is busy. Defaults to false.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (a *SnapshotRollbackAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureClient(req, resp)
+}
+
+func (a *SnapshotRollbackAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config SnapshotRollbackActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Rolling back to snapshot", map[string]interface{}{"id": config.SnapshotID.ValueString()})
+
+	options := map[string]interface{}{
+		"recursive":        config.Recursive.ValueBool(),
+		"recursive_clones": config.RecursiveClones.ValueBool(),
+		"force":            config.Force.ValueBool(),
+	}
+	err := a.client.Call(ctx, "zfs.snapshot.rollback", []interface{}{config.SnapshotID.ValueString(), options}, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Rolling Back Snapshot", fmt.Sprintf("Could not roll back to snapshot %s: %s", config.SnapshotID.ValueString(), err))
+	}
+}
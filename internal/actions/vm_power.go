@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+// vmPowerActions are the values of power and what they are called in
+// progress messages
+var vmPowerActions = map[string]string{
+	"start":    "Starting VM",
+	"stop":     "Stopping VM",
+	"restart":  "Restarting VM",
+	"poweroff": "Powering off VM",
+}
+
+var (
+	_ action.Action                   = &VMPowerAction{}
+	_ action.ActionWithConfigure      = &VMPowerAction{}
+	_ action.ActionWithValidateConfig = &VMPowerAction{}
+)
+
+func NewVMPowerAction() action.Action {
+	return &VMPowerAction{}
+}
+
+// VMPowerAction starts, stops, restarts or powers off a VM
+type VMPowerAction struct {
+	client *client.Client
+}
+
+// VMPowerActionModel describes the action configuration
+type VMPowerActionModel struct {
+	VMID    types.Int64  `tfsdk:"vm_id"`
+	Power   types.String `tfsdk:"power"`
+	Force   types.Bool   `tfsdk:"force"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+func (a *VMPowerAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_power"
+}
+
+func (a *VMPowerAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Starts, stops, restarts or powers off a virtual machine on TrueNAS.",
+		Attributes: map[string]schema.Attribute{
+			"vm_id": schema.Int64Attribute{
+				Description: "The ID of the VM, e.g. the id of a trueform_vm.",
+				Required:    true,
+			},
+			"power": schema.StringAttribute{
+				Description: "What to do: start, stop (an ACPI shutdown), restart or poweroff (pulling the plug).",
+				Required:    true,
+			},
+			"force": schema.BoolAttribute{
+				Description: "With stop, power the VM off if it hasn't shut down when its shutdown timeout runs out. Defaults to false.",
+				Optional:    true,
+			},
+			"timeout": timeoutAttribute,
+		},
+	}
+}
+
+func (a *VMPowerAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureClient(req, resp)
+}
+
+func (a *VMPowerAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config VMPowerActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Power.IsNull() && !config.Power.IsUnknown() {
+		if _, ok := vmPowerActions[config.Power.ValueString()]; !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("power"), "Invalid VM Power Action",
+				fmt.Sprintf("%q is not one of start, stop, restart or poweroff.", config.Power.ValueString()))
+		}
+	}
+	if config.Force.ValueBool() && !config.Power.IsUnknown() && config.Power.ValueString() != "stop" {
+		resp.Diagnostics.AddAttributeError(path.Root("force"), "Invalid VM Power Action",
+			"force only applies when power is stop.")
+	}
+	parseTimeout(config.Timeout, &resp.Diagnostics)
+}
+
+func (a *VMPowerAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config VMPowerActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout := parseTimeout(config.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	power := config.Power.ValueString()
+	operation := vmPowerActions[power]
+	params := []interface{}{config.VMID.ValueInt64()}
+	if power == "stop" {
+		params = append(params, map[string]interface{}{"force_after_timeout": config.Force.ValueBool()})
+	}
+
+	tflog.Debug(ctx, operation, map[string]interface{}{"id": config.VMID.ValueInt64()})
+
+	if err := callJob(ctx, a.client, "vm."+power, params, timeout, reportJobProgress(ctx, resp, operation)); err != nil {
+		resp.Diagnostics.AddError("Error Changing VM Power State",
+			fmt.Sprintf("Could not %s VM %d: %s", power, config.VMID.ValueInt64(), err))
+	}
+}
@@ -0,0 +1,13 @@
+// Package api holds typed models of the TrueNAS middleware API.
+//
+// The models are generated from snapshots of core.get_methods, one per
+// TrueNAS release, in schema/. Each snapshot is trimmed to the methods and
+// fields the provider uses. To add a field or a release, refresh the
+// snapshot from `midclt call core.get_methods` on that release and run
+// go generate.
+//
+// A field that some release may omit is a pointer, so readers can tell a
+// missing value from a zero one instead of asserting on a map.
+package api
+
+//go:generate go run ./gen
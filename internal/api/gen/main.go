@@ -0,0 +1,526 @@
+// Command gen generates models_gen.go from the method schema snapshots in
+// internal/api/schema. It is run by go generate in internal/api.
+//
+// Each snapshot is the output of core.get_methods for one TrueNAS release.
+// The models table below picks the schemas the provider decodes or sends;
+// every struct is the union of its schema across all snapshots, so a field
+// that only one release returns is still decoded, and a field is only
+// required if every release requires it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// result selects a method's return value rather than one of its parameters
+const result = -1
+
+// model is a generated type and the schema it comes from
+type model struct {
+	name   string
+	method string
+	// param is the index into the method's accepts, or result
+	param int
+}
+
+var models = []model{
+	{"AppEntry", "app.query", result},
+	{"AppCreate", "app.create", 0},
+	{"AppUpdate", "app.update", 1},
+	{"CertificateEntry", "certificate.query", result},
+	{"CertificateCreate", "certificate.create", 0},
+	{"CertificateUpdate", "certificate.update", 1},
+	{"CronjobEntry", "cronjob.query", result},
+	{"CronjobCreate", "cronjob.create", 0},
+	{"CronjobUpdate", "cronjob.update", 1},
+	{"DatasetEntry", "pool.dataset.query", result},
+	{"DatasetCreate", "pool.dataset.create", 0},
+	{"DatasetUpdate", "pool.dataset.update", 1},
+	{"DockerConfig", "docker.config", result},
+	{"DockerStatus", "docker.status", result},
+	{"DockerUpdate", "docker.update", 0},
+	{"ISCSIExtentEntry", "iscsi.extent.query", result},
+	{"ISCSIExtentCreate", "iscsi.extent.create", 0},
+	{"ISCSIExtentUpdate", "iscsi.extent.update", 1},
+	{"ISCSIInitiatorEntry", "iscsi.initiator.query", result},
+	{"ISCSIInitiatorCreate", "iscsi.initiator.create", 0},
+	{"ISCSIInitiatorUpdate", "iscsi.initiator.update", 1},
+	{"ISCSIPortalEntry", "iscsi.portal.query", result},
+	{"ISCSIPortalCreate", "iscsi.portal.create", 0},
+	{"ISCSIPortalUpdate", "iscsi.portal.update", 1},
+	{"ISCSITargetEntry", "iscsi.target.query", result},
+	{"ISCSITargetCreate", "iscsi.target.create", 0},
+	{"ISCSITargetUpdate", "iscsi.target.update", 1},
+	{"ISCSITargetExtentEntry", "iscsi.targetextent.query", result},
+	{"ISCSITargetExtentCreate", "iscsi.targetextent.create", 0},
+	{"ISCSITargetExtentUpdate", "iscsi.targetextent.update", 1},
+	{"NFSConfig", "nfs.config", result},
+	{"NFSUpdate", "nfs.update", 0},
+	{"NFSShareEntry", "sharing.nfs.query", result},
+	{"NFSShareCreate", "sharing.nfs.create", 0},
+	{"NFSShareUpdate", "sharing.nfs.update", 1},
+	{"PoolEntry", "pool.query", result},
+	{"PoolCreate", "pool.create", 0},
+	{"SMBShareEntry", "sharing.smb.query", result},
+	{"SMBShareCreate", "sharing.smb.create", 0},
+	{"SMBShareUpdate", "sharing.smb.update", 1},
+	{"ServiceEntry", "service.query", result},
+	{"SnapshotEntry", "zfs.snapshot.query", result},
+	{"SnapshotCreate", "zfs.snapshot.create", 0},
+	{"SnapshotUpdate", "zfs.snapshot.update", 1},
+	{"StaticRouteEntry", "staticroute.query", result},
+	{"StaticRouteCreate", "staticroute.create", 0},
+	{"StaticRouteUpdate", "staticroute.update", 1},
+	{"UserEntry", "user.query", result},
+	{"UserCreate", "user.create", 0},
+	{"UserUpdate", "user.update", 1},
+	{"VMEntry", "vm.query", result},
+	{"VMCreate", "vm.create", 0},
+	{"VMUpdate", "vm.update", 1},
+	{"VMDeviceEntry", "vm.device.query", result},
+	{"VMDeviceCreate", "vm.device.create", 0},
+	{"VMDeviceUpdate", "vm.device.update", 1},
+}
+
+// initialisms are written in upper case in Go names
+var initialisms = map[string]bool{
+	"acl": true, "api": true, "cpu": true, "csr": true, "dns": true,
+	"ec": true, "gid": true, "guid": true, "id": true, "ip": true,
+	"iscsi": true, "lun": true, "mac": true, "msr": true, "naa": true,
+	"nfs": true, "nic": true, "ovmf": true, "rpm": true, "san": true,
+	"smb": true, "ssh": true, "tls": true, "tpc": true, "udp": true,
+	"uid": true, "url": true, "uuid": true, "vm": true,
+}
+
+// unbounded are the integer fields, as Model.field, whose values can exceed
+// int64. They are decoded as json.Number. Certificate serial numbers are up
+// to 20 bytes long.
+var unbounded = map[string]bool{
+	"CertificateEntry.serial": true,
+}
+
+// schema is the subset of JSON Schema that core.get_methods uses
+type schema struct {
+	Type                 string             `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AnyOf                []*schema          `json:"anyOf"`
+	Ref                  string             `json:"$ref"`
+	Defs                 map[string]*schema `json:"$defs"`
+}
+
+// method is one entry of core.get_methods
+type method struct {
+	Description string    `json:"description"`
+	Job         bool      `json:"job"`
+	Accepts     []*schema `json:"accepts"`
+	Returns     []*schema `json:"returns"`
+}
+
+type kind int
+
+const (
+	kindAny kind = iota
+	kindScalar
+	kindStruct
+	kindSlice
+	kindMap
+)
+
+// goType is a field type. Scalars and structs are named by name.
+type goType struct {
+	kind     kind
+	name     string
+	elem     *goType
+	nullable bool
+}
+
+type field struct {
+	json string
+	typ  *goType
+	// required counts the snapshots that require the field
+	required int
+}
+
+type structType struct {
+	name    string
+	doc     string
+	methods map[string]bool
+	// seen counts the snapshots the struct appears in
+	seen   int
+	fields map[string]*field
+}
+
+type generator struct {
+	structs map[string]*structType
+	// visited is the structs walked in the current snapshot
+	visited map[string]bool
+}
+
+// walk is the state for converting one schema
+type walk struct {
+	method string
+	defs   map[string]*schema
+	// rename maps a definition to the model name that replaces it
+	rename map[string]string
+}
+
+func main() {
+	src, err := generate("schema")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("models_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate reads every snapshot in dir and returns the formatted source of
+// the models
+func generate(dir string) ([]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no schema snapshots in %s", dir)
+	}
+	sort.Strings(paths)
+
+	g := &generator{structs: map[string]*structType{}}
+	found := map[string]bool{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var methods map[string]*method
+		if err := json.Unmarshal(data, &methods); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		g.visited = map[string]bool{}
+		for _, m := range models {
+			s, err := modelSchema(methods, m)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if s == nil {
+				continue
+			}
+			found[m.name] = true
+			if err := g.addModel(m, s); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", path, m.method, err)
+			}
+		}
+	}
+	for _, m := range models {
+		if !found[m.name] {
+			return nil, fmt.Errorf("%s isn't in any snapshot", m.method)
+		}
+	}
+	return g.render()
+}
+
+// modelSchema finds the schema of a model in a snapshot. It returns nil if
+// the snapshot doesn't have the method.
+func modelSchema(methods map[string]*method, m model) (*schema, error) {
+	meth, ok := methods[m.method]
+	if !ok {
+		return nil, nil
+	}
+	if m.param == result {
+		if len(meth.Returns) != 1 {
+			return nil, fmt.Errorf("%s returns %d values", m.method, len(meth.Returns))
+		}
+		s := meth.Returns[0]
+		// Query methods return a list, a single entry or a count depending
+		// on the options; the model is the entry
+		for _, alt := range s.AnyOf {
+			if alt.Ref != "" {
+				return &schema{Ref: alt.Ref, Defs: s.Defs}, nil
+			}
+		}
+		return s, nil
+	}
+	if m.param >= len(meth.Accepts) {
+		return nil, fmt.Errorf("%s has no parameter %d", m.method, m.param)
+	}
+	return meth.Accepts[m.param], nil
+}
+
+func (g *generator) addModel(m model, s *schema) error {
+	w := &walk{method: m.method, defs: s.Defs, rename: map[string]string{}}
+	root := s
+	if s.Ref != "" {
+		def, err := w.resolve(s.Ref)
+		if err != nil {
+			return err
+		}
+		w.rename[refName(s.Ref)] = m.name
+		root = def
+	}
+	if root.Type != "object" || root.Properties == nil {
+		return fmt.Errorf("%s isn't an object", m.name)
+	}
+	if _, err := g.object(w, m.name, root); err != nil {
+		return err
+	}
+	st := g.structs[m.name]
+	switch {
+	case m.param == result && strings.HasSuffix(m.method, ".query"):
+		st.doc = fmt.Sprintf("%s is an entry in the result of %s.", m.name, m.method)
+	case m.param == result:
+		st.doc = fmt.Sprintf("%s is the result of %s.", m.name, m.method)
+	default:
+		st.doc = fmt.Sprintf("%s is parameter %d of %s.", m.name, m.param+1, m.method)
+	}
+	return nil
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/$defs/")
+}
+
+func (w *walk) resolve(ref string) (*schema, error) {
+	def, ok := w.defs[refName(ref)]
+	if !ok {
+		return nil, fmt.Errorf("unresolved reference %s", ref)
+	}
+	return def, nil
+}
+
+// convert returns the Go type of a schema. name is the struct name to use if
+// the schema is an inline object.
+func (g *generator) convert(w *walk, name string, s *schema) (*goType, error) {
+	if s.Ref != "" {
+		def, err := w.resolve(s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		defName := refName(s.Ref)
+		if renamed, ok := w.rename[defName]; ok {
+			defName = renamed
+		}
+		return g.convert(w, defName, &schema{
+			Type: def.Type, Properties: def.Properties, Required: def.Required,
+			AdditionalProperties: def.AdditionalProperties, Items: def.Items, AnyOf: def.AnyOf,
+		})
+	}
+
+	if len(s.AnyOf) > 0 {
+		var alts []*schema
+		nullable := false
+		for _, alt := range s.AnyOf {
+			if alt.Type == "null" {
+				nullable = true
+				continue
+			}
+			alts = append(alts, alt)
+		}
+		if len(alts) != 1 {
+			return &goType{kind: kindAny}, nil
+		}
+		t, err := g.convert(w, name, alts[0])
+		if err != nil {
+			return nil, err
+		}
+		t.nullable = t.nullable || nullable
+		return t, nil
+	}
+
+	switch s.Type {
+	case "string":
+		return &goType{kind: kindScalar, name: "string"}, nil
+	case "integer":
+		return &goType{kind: kindScalar, name: "int64"}, nil
+	case "number":
+		return &goType{kind: kindScalar, name: "float64"}, nil
+	case "boolean":
+		return &goType{kind: kindScalar, name: "bool"}, nil
+	case "array":
+		if s.Items == nil {
+			return &goType{kind: kindSlice, elem: &goType{kind: kindAny}}, nil
+		}
+		elem, err := g.convert(w, name, s.Items)
+		if err != nil {
+			return nil, err
+		}
+		elem.nullable = false
+		return &goType{kind: kindSlice, elem: elem}, nil
+	case "object":
+		if s.Properties != nil {
+			return g.object(w, name, s)
+		}
+		var values schema
+		if len(s.AdditionalProperties) > 0 && s.AdditionalProperties[0] == '{' {
+			if err := json.Unmarshal(s.AdditionalProperties, &values); err != nil {
+				return nil, err
+			}
+			elem, err := g.convert(w, name, &values)
+			if err != nil {
+				return nil, err
+			}
+			elem.nullable = false
+			return &goType{kind: kindMap, elem: elem}, nil
+		}
+		return &goType{kind: kindMap, elem: &goType{kind: kindAny}}, nil
+	}
+	return &goType{kind: kindAny}, nil
+}
+
+// object merges an object schema into the struct called name
+func (g *generator) object(w *walk, name string, s *schema) (*goType, error) {
+	t := &goType{kind: kindStruct, name: name}
+	st, ok := g.structs[name]
+	if !ok {
+		st = &structType{name: name, methods: map[string]bool{}, fields: map[string]*field{}}
+		g.structs[name] = st
+	}
+	st.methods[w.method] = true
+	if g.visited[name] {
+		return t, nil
+	}
+	g.visited[name] = true
+	st.seen++
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	for jsonName, prop := range s.Properties {
+		ft, err := g.convert(w, name+goName(jsonName), prop)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", name, jsonName, err)
+		}
+		if unbounded[name+"."+jsonName] && ft.kind == kindScalar && ft.name == "int64" {
+			ft.name = "json.Number"
+		}
+		f, ok := st.fields[jsonName]
+		if !ok {
+			f = &field{json: jsonName}
+			st.fields[jsonName] = f
+		}
+		f.typ = merge(f.typ, ft)
+		if required[jsonName] {
+			f.required++
+		}
+	}
+	return t, nil
+}
+
+// merge combines the types a field has in different snapshots. Types that
+// disagree become interface{}.
+func merge(a, b *goType) *goType {
+	if a == nil {
+		return b
+	}
+	if a.kind != b.kind || a.name != b.name {
+		return &goType{kind: kindAny}
+	}
+	t := &goType{kind: a.kind, name: a.name, nullable: a.nullable || b.nullable}
+	if a.elem != nil {
+		t.elem = merge(a.elem, b.elem)
+	}
+	return t
+}
+
+// goName converts a JSON name such as "encryption_root" to a Go name
+func goName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if initialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// expr returns the Go expression for a type. Optional and nullable scalars
+// and structs are pointers, so a missing value can be told from a zero one.
+func (t *goType) expr(optional bool) string {
+	pointer := ""
+	if optional || t.nullable {
+		pointer = "*"
+	}
+	switch t.kind {
+	case kindScalar, kindStruct:
+		return pointer + t.name
+	case kindSlice:
+		return "[]" + t.elem.expr(false)
+	case kindMap:
+		return "map[string]" + t.elem.expr(false)
+	}
+	return "interface{}"
+}
+
+func (g *generator) render() ([]byte, error) {
+	var body bytes.Buffer
+
+	names := make([]string, 0, len(g.structs))
+	for name := range g.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		st := g.structs[name]
+		doc := st.doc
+		if doc == "" {
+			methods := make([]string, 0, len(st.methods))
+			for m := range st.methods {
+				methods = append(methods, m)
+			}
+			sort.Strings(methods)
+			doc = fmt.Sprintf("%s appears in the schema of %s.", name, strings.Join(methods, ", "))
+		}
+		fmt.Fprintf(&body, "\n// %s\ntype %s struct {\n", doc, name)
+
+		jsonNames := make([]string, 0, len(st.fields))
+		for j := range st.fields {
+			jsonNames = append(jsonNames, j)
+		}
+		sort.Strings(jsonNames)
+		goNames := map[string]string{}
+		for _, j := range jsonNames {
+			f := st.fields[j]
+			fieldName := goName(j)
+			if other, ok := goNames[fieldName]; ok {
+				return nil, fmt.Errorf("%s: %q and %q are both %s", name, other, j, fieldName)
+			}
+			goNames[fieldName] = j
+
+			optional := f.required < st.seen
+			tag := j
+			if optional {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&body, "\t%s %s `json:%q`\n", fieldName, f.typ.expr(optional), tag)
+		}
+		body.WriteString("}\n")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/api/gen from schema/*.json. DO NOT EDIT.\n\n")
+	buf.WriteString("package api\n")
+	if bytes.Contains(body.Bytes(), []byte("json.Number")) {
+		buf.WriteString("\nimport \"encoding/json\"\n")
+	}
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestModelsUpToDate(t *testing.T) {
+	want, err := generate(filepath.Join("..", "schema"))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join("..", "models_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("models_gen.go is out of date; run go generate ./internal/api")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"name":                           "Name",
+		"encryption_root":                "EncryptionRoot",
+		"id":                             "ID",
+		"bsdgrp_gid":                     "BsdgrpGID",
+		"access_based_share_enumeration": "AccessBasedShareEnumeration",
+		"CSR":                            "CSR",
+		"v4_v3owner":                     "V4V3owner",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	str := &goType{kind: kindScalar, name: "string"}
+	nullStr := &goType{kind: kindScalar, name: "string", nullable: true}
+	integer := &goType{kind: kindScalar, name: "int64"}
+
+	if got := merge(str, nullStr); got.kind != kindScalar || !got.nullable {
+		t.Errorf("merge(string, null string) = %+v, want nullable string", got)
+	}
+	if got := merge(str, integer); got.kind != kindAny {
+		t.Errorf("merge(string, integer) = %+v, want interface{}", got)
+	}
+	a := &goType{kind: kindSlice, elem: str}
+	b := &goType{kind: kindSlice, elem: integer}
+	if got := merge(a, b); got.kind != kindSlice || got.elem.kind != kindAny {
+		t.Errorf("merge([]string, []int64) = %+v, want []interface{}", got)
+	}
+}
+
+func TestUnboundedIntegers(t *testing.T) {
+	src, err := generate(filepath.Join("..", "schema"))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if !bytes.Contains(src, []byte("import \"encoding/json\"")) {
+		t.Error("models_gen.go doesn't import encoding/json")
+	}
+	for key := range unbounded {
+		model, jsonName, _ := strings.Cut(key, ".")
+		start := bytes.Index(src, []byte("type "+model+" struct"))
+		if start < 0 {
+			t.Fatalf("%s isn't generated", model)
+		}
+		end := start + bytes.Index(src[start:], []byte("\n}\n"))
+		field := regexp.MustCompile(`\*json\.Number +` + "`json:\"" + jsonName + `[,"]`)
+		if !field.Match(src[start:end]) {
+			t.Errorf("%s isn't a json.Number", key)
+		}
+	}
+}
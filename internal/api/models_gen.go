@@ -0,0 +1,1119 @@
+// Code generated by internal/api/gen from schema/*.json. DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+
+// AppCreate is parameter 1 of app.create.
+type AppCreate struct {
+	AppName                   string                 `json:"app_name"`
+	CatalogApp                *string                `json:"catalog_app,omitempty"`
+	CustomApp                 *bool                  `json:"custom_app,omitempty"`
+	CustomComposeConfig       map[string]interface{} `json:"custom_compose_config,omitempty"`
+	CustomComposeConfigString *string                `json:"custom_compose_config_string,omitempty"`
+	Train                     *string                `json:"train,omitempty"`
+	Values                    map[string]interface{} `json:"values,omitempty"`
+	Version                   *string                `json:"version,omitempty"`
+}
+
+// AppEntry is an entry in the result of app.query.
+type AppEntry struct {
+	ActiveWorkloads       map[string]interface{} `json:"active_workloads,omitempty"`
+	CustomApp             *bool                  `json:"custom_app,omitempty"`
+	HumanVersion          *string                `json:"human_version,omitempty"`
+	ID                    string                 `json:"id"`
+	ImageUpdatesAvailable *bool                  `json:"image_updates_available,omitempty"`
+	LatestVersion         *string                `json:"latest_version,omitempty"`
+	Metadata              *AppMetadata           `json:"metadata,omitempty"`
+	Migrated              *bool                  `json:"migrated,omitempty"`
+	Name                  string                 `json:"name"`
+	Notes                 *string                `json:"notes,omitempty"`
+	Portals               map[string]interface{} `json:"portals,omitempty"`
+	State                 *string                `json:"state,omitempty"`
+	UpgradeAvailable      *bool                  `json:"upgrade_available,omitempty"`
+	Version               *string                `json:"version,omitempty"`
+}
+
+// AppMetadata appears in the schema of app.query.
+type AppMetadata struct {
+	AppVersion  *string `json:"app_version,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Icon        *string `json:"icon,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	Train       *string `json:"train,omitempty"`
+	Version     *string `json:"version,omitempty"`
+}
+
+// AppUpdate is parameter 2 of app.update.
+type AppUpdate struct {
+	CustomComposeConfig       map[string]interface{} `json:"custom_compose_config,omitempty"`
+	CustomComposeConfigString *string                `json:"custom_compose_config_string,omitempty"`
+	Values                    map[string]interface{} `json:"values,omitempty"`
+}
+
+// CertificateCreate is parameter 1 of certificate.create.
+type CertificateCreate struct {
+	CSR                *string                `json:"CSR,omitempty"`
+	AddToTrustedStore  *bool                  `json:"add_to_trusted_store,omitempty"`
+	CertExtensions     map[string]interface{} `json:"cert_extensions,omitempty"`
+	Certificate        *string                `json:"certificate,omitempty"`
+	City               *string                `json:"city,omitempty"`
+	Common             *string                `json:"common,omitempty"`
+	Country            *string                `json:"country,omitempty"`
+	CreateType         string                 `json:"create_type"`
+	CSRID              *int64                 `json:"csr_id,omitempty"`
+	DigestAlgorithm    *string                `json:"digest_algorithm,omitempty"`
+	ECCurve            *string                `json:"ec_curve,omitempty"`
+	Email              *string                `json:"email,omitempty"`
+	KeyLength          *int64                 `json:"key_length,omitempty"`
+	KeyType            *string                `json:"key_type,omitempty"`
+	Lifetime           *int64                 `json:"lifetime,omitempty"`
+	Name               string                 `json:"name"`
+	Organization       *string                `json:"organization,omitempty"`
+	OrganizationalUnit *string                `json:"organizational_unit,omitempty"`
+	Passphrase         *string                `json:"passphrase,omitempty"`
+	Privatekey         *string                `json:"privatekey,omitempty"`
+	SAN                []string               `json:"san,omitempty"`
+	State              *string                `json:"state,omitempty"`
+}
+
+// CertificateEntry is an entry in the result of certificate.query.
+type CertificateEntry struct {
+	CSR                *string                `json:"CSR,omitempty"`
+	DN                 *string                `json:"DN,omitempty"`
+	AddToTrustedStore  *bool                  `json:"add_to_trusted_store,omitempty"`
+	CertType           *string                `json:"cert_type,omitempty"`
+	Certificate        *string                `json:"certificate,omitempty"`
+	CertificatePath    *string                `json:"certificate_path,omitempty"`
+	Chain              *bool                  `json:"chain,omitempty"`
+	ChainList          []string               `json:"chain_list,omitempty"`
+	City               *string                `json:"city,omitempty"`
+	Common             *string                `json:"common,omitempty"`
+	Country            *string                `json:"country,omitempty"`
+	CSRPath            *string                `json:"csr_path,omitempty"`
+	DigestAlgorithm    *string                `json:"digest_algorithm,omitempty"`
+	Email              *string                `json:"email,omitempty"`
+	Expired            *bool                  `json:"expired,omitempty"`
+	Extensions         map[string]interface{} `json:"extensions,omitempty"`
+	Fingerprint        *string                `json:"fingerprint,omitempty"`
+	From               *string                `json:"from,omitempty"`
+	ID                 int64                  `json:"id"`
+	KeyLength          *int64                 `json:"key_length,omitempty"`
+	KeyType            *string                `json:"key_type,omitempty"`
+	Lifetime           *int64                 `json:"lifetime,omitempty"`
+	Name               string                 `json:"name"`
+	Organization       *string                `json:"organization,omitempty"`
+	OrganizationalUnit *string                `json:"organizational_unit,omitempty"`
+	PrivatekeyPath     *string                `json:"privatekey_path,omitempty"`
+	Revoked            *bool                  `json:"revoked,omitempty"`
+	RootPath           *string                `json:"root_path,omitempty"`
+	SAN                []string               `json:"san,omitempty"`
+	Serial             *json.Number           `json:"serial,omitempty"`
+	Signedby           interface{}            `json:"signedby,omitempty"`
+	State              *string                `json:"state,omitempty"`
+	SubjectNameHash    *int64                 `json:"subject_name_hash,omitempty"`
+	Type               *int64                 `json:"type,omitempty"`
+	Until              *string                `json:"until,omitempty"`
+}
+
+// CertificateUpdate is parameter 2 of certificate.update.
+type CertificateUpdate struct {
+	AddToTrustedStore *bool   `json:"add_to_trusted_store,omitempty"`
+	Name              *string `json:"name,omitempty"`
+	Revoked           *bool   `json:"revoked,omitempty"`
+}
+
+// CronjobCreate is parameter 1 of cronjob.create.
+type CronjobCreate struct {
+	Command     string           `json:"command"`
+	Description *string          `json:"description,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Schedule    *CronjobSchedule `json:"schedule,omitempty"`
+	Stderr      *bool            `json:"stderr,omitempty"`
+	Stdout      *bool            `json:"stdout,omitempty"`
+	User        string           `json:"user"`
+}
+
+// CronjobEntry is an entry in the result of cronjob.query.
+type CronjobEntry struct {
+	Command     string           `json:"command"`
+	Description *string          `json:"description,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	ID          int64            `json:"id"`
+	Schedule    *CronjobSchedule `json:"schedule,omitempty"`
+	Stderr      *bool            `json:"stderr,omitempty"`
+	Stdout      *bool            `json:"stdout,omitempty"`
+	User        string           `json:"user"`
+}
+
+// CronjobSchedule appears in the schema of cronjob.create, cronjob.query, cronjob.update.
+type CronjobSchedule struct {
+	Dom    string `json:"dom"`
+	Dow    string `json:"dow"`
+	Hour   string `json:"hour"`
+	Minute string `json:"minute"`
+	Month  string `json:"month"`
+}
+
+// CronjobUpdate is parameter 2 of cronjob.update.
+type CronjobUpdate struct {
+	Command     *string          `json:"command,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Schedule    *CronjobSchedule `json:"schedule,omitempty"`
+	Stderr      *bool            `json:"stderr,omitempty"`
+	Stdout      *bool            `json:"stdout,omitempty"`
+	User        *string          `json:"user,omitempty"`
+}
+
+// DatasetCreate is parameter 1 of pool.dataset.create.
+type DatasetCreate struct {
+	Aclmode               *string                         `json:"aclmode,omitempty"`
+	Acltype               *string                         `json:"acltype,omitempty"`
+	Atime                 *string                         `json:"atime,omitempty"`
+	Casesensitivity       *string                         `json:"casesensitivity,omitempty"`
+	Checksum              *string                         `json:"checksum,omitempty"`
+	Comments              *string                         `json:"comments,omitempty"`
+	Compression           *string                         `json:"compression,omitempty"`
+	Copies                *int64                          `json:"copies,omitempty"`
+	CreateAncestors       *bool                           `json:"create_ancestors,omitempty"`
+	Deduplication         *string                         `json:"deduplication,omitempty"`
+	Encryption            *bool                           `json:"encryption,omitempty"`
+	EncryptionOptions     *DatasetCreateEncryptionOptions `json:"encryption_options,omitempty"`
+	Exec                  *string                         `json:"exec,omitempty"`
+	ForceSize             *bool                           `json:"force_size,omitempty"`
+	InheritEncryption     *bool                           `json:"inherit_encryption,omitempty"`
+	Name                  string                          `json:"name"`
+	Quota                 *int64                          `json:"quota,omitempty"`
+	QuotaCritical         *int64                          `json:"quota_critical,omitempty"`
+	QuotaWarning          *int64                          `json:"quota_warning,omitempty"`
+	Readonly              *string                         `json:"readonly,omitempty"`
+	Recordsize            *string                         `json:"recordsize,omitempty"`
+	Refquota              *int64                          `json:"refquota,omitempty"`
+	RefquotaCritical      *int64                          `json:"refquota_critical,omitempty"`
+	RefquotaWarning       *int64                          `json:"refquota_warning,omitempty"`
+	Refreservation        *int64                          `json:"refreservation,omitempty"`
+	Reservation           *int64                          `json:"reservation,omitempty"`
+	ShareType             *string                         `json:"share_type,omitempty"`
+	Snapdev               *string                         `json:"snapdev,omitempty"`
+	Snapdir               *string                         `json:"snapdir,omitempty"`
+	Sparse                *bool                           `json:"sparse,omitempty"`
+	SpecialSmallBlockSize *int64                          `json:"special_small_block_size,omitempty"`
+	Sync                  *string                         `json:"sync,omitempty"`
+	Type                  *string                         `json:"type,omitempty"`
+	UserProperties        []DatasetCreateUserProperties   `json:"user_properties,omitempty"`
+	Volblocksize          *string                         `json:"volblocksize,omitempty"`
+	Volsize               *int64                          `json:"volsize,omitempty"`
+}
+
+// DatasetCreateEncryptionOptions appears in the schema of pool.dataset.create.
+type DatasetCreateEncryptionOptions struct {
+	Algorithm   *string `json:"algorithm,omitempty"`
+	GenerateKey *bool   `json:"generate_key,omitempty"`
+	Key         *string `json:"key,omitempty"`
+	Passphrase  *string `json:"passphrase,omitempty"`
+	Pbkdf2iters *int64  `json:"pbkdf2iters,omitempty"`
+}
+
+// DatasetCreateUserProperties appears in the schema of pool.dataset.create.
+type DatasetCreateUserProperties struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// DatasetEntry is an entry in the result of pool.dataset.query.
+type DatasetEntry struct {
+	Aclmode         *ZFSProperty             `json:"aclmode,omitempty"`
+	Acltype         *ZFSProperty             `json:"acltype,omitempty"`
+	Atime           *ZFSProperty             `json:"atime,omitempty"`
+	Available       *ZFSProperty             `json:"available,omitempty"`
+	Casesensitivity *ZFSProperty             `json:"casesensitivity,omitempty"`
+	Checksum        *ZFSProperty             `json:"checksum,omitempty"`
+	Children        []map[string]interface{} `json:"children,omitempty"`
+	Comments        interface{}              `json:"comments,omitempty"`
+	Compression     *ZFSProperty             `json:"compression,omitempty"`
+	Copies          *ZFSProperty             `json:"copies,omitempty"`
+	Deduplication   *ZFSProperty             `json:"deduplication,omitempty"`
+	Encrypted       *bool                    `json:"encrypted,omitempty"`
+	EncryptionRoot  *string                  `json:"encryption_root,omitempty"`
+	Exec            *ZFSProperty             `json:"exec,omitempty"`
+	ID              string                   `json:"id"`
+	KeyLoaded       *bool                    `json:"key_loaded,omitempty"`
+	Locked          *bool                    `json:"locked,omitempty"`
+	Managedby       *ZFSProperty             `json:"managedby,omitempty"`
+	Mountpoint      *string                  `json:"mountpoint,omitempty"`
+	Name            string                   `json:"name"`
+	Pool            string                   `json:"pool"`
+	Quota           *ZFSProperty             `json:"quota,omitempty"`
+	QuotaCritical   *ZFSProperty             `json:"quota_critical,omitempty"`
+	QuotaWarning    *ZFSProperty             `json:"quota_warning,omitempty"`
+	Readonly        *ZFSProperty             `json:"readonly,omitempty"`
+	Recordsize      *ZFSProperty             `json:"recordsize,omitempty"`
+	Refquota        *ZFSProperty             `json:"refquota,omitempty"`
+	Refreservation  *ZFSProperty             `json:"refreservation,omitempty"`
+	Reservation     *ZFSProperty             `json:"reservation,omitempty"`
+	Snapdir         *ZFSProperty             `json:"snapdir,omitempty"`
+	Sync            *ZFSProperty             `json:"sync,omitempty"`
+	Type            string                   `json:"type"`
+	Used            *ZFSProperty             `json:"used,omitempty"`
+	Usedbychildren  *ZFSProperty             `json:"usedbychildren,omitempty"`
+	Usedbydataset   *ZFSProperty             `json:"usedbydataset,omitempty"`
+	Usedbysnapshots *ZFSProperty             `json:"usedbysnapshots,omitempty"`
+	UserProperties  map[string]ZFSProperty   `json:"user_properties,omitempty"`
+	Volblocksize    *ZFSProperty             `json:"volblocksize,omitempty"`
+	Volsize         *ZFSProperty             `json:"volsize,omitempty"`
+}
+
+// DatasetUpdate is parameter 2 of pool.dataset.update.
+type DatasetUpdate struct {
+	Aclmode               *string                             `json:"aclmode,omitempty"`
+	Acltype               *string                             `json:"acltype,omitempty"`
+	Atime                 *string                             `json:"atime,omitempty"`
+	Checksum              *string                             `json:"checksum,omitempty"`
+	Comments              *string                             `json:"comments,omitempty"`
+	Compression           *string                             `json:"compression,omitempty"`
+	Copies                *int64                              `json:"copies,omitempty"`
+	Deduplication         *string                             `json:"deduplication,omitempty"`
+	Exec                  *string                             `json:"exec,omitempty"`
+	ForceSize             *bool                               `json:"force_size,omitempty"`
+	Quota                 *int64                              `json:"quota,omitempty"`
+	QuotaCritical         *int64                              `json:"quota_critical,omitempty"`
+	QuotaWarning          *int64                              `json:"quota_warning,omitempty"`
+	Readonly              *string                             `json:"readonly,omitempty"`
+	Recordsize            *string                             `json:"recordsize,omitempty"`
+	Refquota              *int64                              `json:"refquota,omitempty"`
+	RefquotaCritical      *int64                              `json:"refquota_critical,omitempty"`
+	RefquotaWarning       *int64                              `json:"refquota_warning,omitempty"`
+	Refreservation        *int64                              `json:"refreservation,omitempty"`
+	Reservation           *int64                              `json:"reservation,omitempty"`
+	Snapdev               *string                             `json:"snapdev,omitempty"`
+	Snapdir               *string                             `json:"snapdir,omitempty"`
+	SpecialSmallBlockSize *int64                              `json:"special_small_block_size,omitempty"`
+	Sync                  *string                             `json:"sync,omitempty"`
+	UserPropertiesUpdate  []DatasetUpdateUserPropertiesUpdate `json:"user_properties_update,omitempty"`
+	Volsize               *int64                              `json:"volsize,omitempty"`
+}
+
+// DatasetUpdateUserPropertiesUpdate appears in the schema of pool.dataset.update.
+type DatasetUpdateUserPropertiesUpdate struct {
+	Key    string  `json:"key"`
+	Remove *bool   `json:"remove,omitempty"`
+	Value  *string `json:"value,omitempty"`
+}
+
+// DockerAddressPool appears in the schema of docker.config, docker.update.
+type DockerAddressPool struct {
+	Base string `json:"base"`
+	Size int64  `json:"size"`
+}
+
+// DockerConfig is the result of docker.config.
+type DockerConfig struct {
+	AddressPools       []DockerAddressPool `json:"address_pools,omitempty"`
+	CidrV6             *string             `json:"cidr_v6,omitempty"`
+	Dataset            *string             `json:"dataset,omitempty"`
+	EnableImageUpdates *bool               `json:"enable_image_updates,omitempty"`
+	ID                 int64               `json:"id"`
+	Nvidia             *bool               `json:"nvidia,omitempty"`
+	Pool               *string             `json:"pool,omitempty"`
+}
+
+// DockerStatus is the result of docker.status.
+type DockerStatus struct {
+	Description *string `json:"description,omitempty"`
+	Status      string  `json:"status"`
+}
+
+// DockerUpdate is parameter 1 of docker.update.
+type DockerUpdate struct {
+	AddressPools        []DockerAddressPool `json:"address_pools,omitempty"`
+	CidrV6              *string             `json:"cidr_v6,omitempty"`
+	EnableImageUpdates  *bool               `json:"enable_image_updates,omitempty"`
+	MigrateApplications *bool               `json:"migrate_applications,omitempty"`
+	Nvidia              *bool               `json:"nvidia,omitempty"`
+	Pool                *string             `json:"pool,omitempty"`
+}
+
+// ISCSIExtentCreate is parameter 1 of iscsi.extent.create.
+type ISCSIExtentCreate struct {
+	AvailThreshold *int64  `json:"avail_threshold,omitempty"`
+	Blocksize      *int64  `json:"blocksize,omitempty"`
+	Comment        *string `json:"comment,omitempty"`
+	Disk           *string `json:"disk,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
+	Filesize       *int64  `json:"filesize,omitempty"`
+	InsecureTPC    *bool   `json:"insecure_tpc,omitempty"`
+	Name           string  `json:"name"`
+	Path           *string `json:"path,omitempty"`
+	Pblocksize     *bool   `json:"pblocksize,omitempty"`
+	ProductID      *string `json:"product_id,omitempty"`
+	Ro             *bool   `json:"ro,omitempty"`
+	RPM            *string `json:"rpm,omitempty"`
+	Serial         *string `json:"serial,omitempty"`
+	Type           *string `json:"type,omitempty"`
+	Xen            *bool   `json:"xen,omitempty"`
+}
+
+// ISCSIExtentEntry is an entry in the result of iscsi.extent.query.
+type ISCSIExtentEntry struct {
+	AvailThreshold *int64      `json:"avail_threshold,omitempty"`
+	Blocksize      *int64      `json:"blocksize,omitempty"`
+	Comment        *string     `json:"comment,omitempty"`
+	Disk           *string     `json:"disk,omitempty"`
+	Enabled        *bool       `json:"enabled,omitempty"`
+	Filesize       interface{} `json:"filesize,omitempty"`
+	ID             int64       `json:"id"`
+	InsecureTPC    *bool       `json:"insecure_tpc,omitempty"`
+	Locked         *bool       `json:"locked,omitempty"`
+	NAA            *string     `json:"naa,omitempty"`
+	Name           string      `json:"name"`
+	Path           *string     `json:"path,omitempty"`
+	Pblocksize     *bool       `json:"pblocksize,omitempty"`
+	ProductID      *string     `json:"product_id,omitempty"`
+	Ro             *bool       `json:"ro,omitempty"`
+	RPM            *string     `json:"rpm,omitempty"`
+	Serial         *string     `json:"serial,omitempty"`
+	Type           string      `json:"type"`
+	Vendor         *string     `json:"vendor,omitempty"`
+	Xen            *bool       `json:"xen,omitempty"`
+}
+
+// ISCSIExtentUpdate is parameter 2 of iscsi.extent.update.
+type ISCSIExtentUpdate struct {
+	AvailThreshold *int64  `json:"avail_threshold,omitempty"`
+	Blocksize      *int64  `json:"blocksize,omitempty"`
+	Comment        *string `json:"comment,omitempty"`
+	Disk           *string `json:"disk,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
+	Filesize       *int64  `json:"filesize,omitempty"`
+	InsecureTPC    *bool   `json:"insecure_tpc,omitempty"`
+	Name           *string `json:"name,omitempty"`
+	Path           *string `json:"path,omitempty"`
+	Pblocksize     *bool   `json:"pblocksize,omitempty"`
+	ProductID      *string `json:"product_id,omitempty"`
+	Ro             *bool   `json:"ro,omitempty"`
+	RPM            *string `json:"rpm,omitempty"`
+	Serial         *string `json:"serial,omitempty"`
+	Type           *string `json:"type,omitempty"`
+	Xen            *bool   `json:"xen,omitempty"`
+}
+
+// ISCSIInitiatorCreate is parameter 1 of iscsi.initiator.create.
+type ISCSIInitiatorCreate struct {
+	AuthNetwork []string `json:"auth_network,omitempty"`
+	Comment     *string  `json:"comment,omitempty"`
+	Initiators  []string `json:"initiators,omitempty"`
+}
+
+// ISCSIInitiatorEntry is an entry in the result of iscsi.initiator.query.
+type ISCSIInitiatorEntry struct {
+	AuthNetwork []string `json:"auth_network,omitempty"`
+	Comment     *string  `json:"comment,omitempty"`
+	ID          int64    `json:"id"`
+	Initiators  []string `json:"initiators,omitempty"`
+}
+
+// ISCSIInitiatorUpdate is parameter 2 of iscsi.initiator.update.
+type ISCSIInitiatorUpdate struct {
+	AuthNetwork []string `json:"auth_network,omitempty"`
+	Comment     *string  `json:"comment,omitempty"`
+	Initiators  []string `json:"initiators,omitempty"`
+}
+
+// ISCSIPortalCreate is parameter 1 of iscsi.portal.create.
+type ISCSIPortalCreate struct {
+	Comment             *string             `json:"comment,omitempty"`
+	DiscoveryAuthgroup  *int64              `json:"discovery_authgroup,omitempty"`
+	DiscoveryAuthmethod *string             `json:"discovery_authmethod,omitempty"`
+	Listen              []ISCSIPortalListen `json:"listen"`
+}
+
+// ISCSIPortalEntry is an entry in the result of iscsi.portal.query.
+type ISCSIPortalEntry struct {
+	Comment             *string             `json:"comment,omitempty"`
+	DiscoveryAuthgroup  *int64              `json:"discovery_authgroup,omitempty"`
+	DiscoveryAuthmethod *string             `json:"discovery_authmethod,omitempty"`
+	ID                  int64               `json:"id"`
+	Listen              []ISCSIPortalListen `json:"listen,omitempty"`
+	Tag                 *int64              `json:"tag,omitempty"`
+}
+
+// ISCSIPortalListen appears in the schema of iscsi.portal.create, iscsi.portal.query, iscsi.portal.update.
+type ISCSIPortalListen struct {
+	IP   string `json:"ip"`
+	Port *int64 `json:"port,omitempty"`
+}
+
+// ISCSIPortalUpdate is parameter 2 of iscsi.portal.update.
+type ISCSIPortalUpdate struct {
+	Comment             *string             `json:"comment,omitempty"`
+	DiscoveryAuthgroup  *int64              `json:"discovery_authgroup,omitempty"`
+	DiscoveryAuthmethod *string             `json:"discovery_authmethod,omitempty"`
+	Listen              []ISCSIPortalListen `json:"listen,omitempty"`
+}
+
+// ISCSITargetCreate is parameter 1 of iscsi.target.create.
+type ISCSITargetCreate struct {
+	Alias        *string            `json:"alias,omitempty"`
+	AuthNetworks []string           `json:"auth_networks,omitempty"`
+	Groups       []ISCSITargetGroup `json:"groups,omitempty"`
+	Mode         *string            `json:"mode,omitempty"`
+	Name         string             `json:"name"`
+}
+
+// ISCSITargetEntry is an entry in the result of iscsi.target.query.
+type ISCSITargetEntry struct {
+	Alias        *string            `json:"alias,omitempty"`
+	AuthNetworks []string           `json:"auth_networks,omitempty"`
+	Groups       []ISCSITargetGroup `json:"groups,omitempty"`
+	ID           int64              `json:"id"`
+	Mode         *string            `json:"mode,omitempty"`
+	Name         string             `json:"name"`
+	RelTgtID     *int64             `json:"rel_tgt_id,omitempty"`
+}
+
+// ISCSITargetExtentCreate is parameter 1 of iscsi.targetextent.create.
+type ISCSITargetExtentCreate struct {
+	Extent int64  `json:"extent"`
+	Lunid  *int64 `json:"lunid,omitempty"`
+	Target int64  `json:"target"`
+}
+
+// ISCSITargetExtentEntry is an entry in the result of iscsi.targetextent.query.
+type ISCSITargetExtentEntry struct {
+	Extent int64 `json:"extent"`
+	ID     int64 `json:"id"`
+	Lunid  int64 `json:"lunid"`
+	Target int64 `json:"target"`
+}
+
+// ISCSITargetExtentUpdate is parameter 2 of iscsi.targetextent.update.
+type ISCSITargetExtentUpdate struct {
+	Extent *int64 `json:"extent,omitempty"`
+	Lunid  *int64 `json:"lunid,omitempty"`
+	Target *int64 `json:"target,omitempty"`
+}
+
+// ISCSITargetGroup appears in the schema of iscsi.target.create, iscsi.target.query, iscsi.target.update.
+type ISCSITargetGroup struct {
+	Auth       *int64  `json:"auth,omitempty"`
+	Authmethod *string `json:"authmethod,omitempty"`
+	Initiator  *int64  `json:"initiator,omitempty"`
+	Portal     int64   `json:"portal"`
+}
+
+// ISCSITargetUpdate is parameter 2 of iscsi.target.update.
+type ISCSITargetUpdate struct {
+	Alias        *string            `json:"alias,omitempty"`
+	AuthNetworks []string           `json:"auth_networks,omitempty"`
+	Groups       []ISCSITargetGroup `json:"groups,omitempty"`
+	Mode         *string            `json:"mode,omitempty"`
+	Name         *string            `json:"name,omitempty"`
+}
+
+// NFSConfig is the result of nfs.config.
+type NFSConfig struct {
+	AllowNonroot    *bool    `json:"allow_nonroot,omitempty"`
+	Bindip          []string `json:"bindip,omitempty"`
+	ID              int64    `json:"id"`
+	KeytabHasNFSSpn *bool    `json:"keytab_has_nfs_spn,omitempty"`
+	ManagedNfsv4ACL *bool    `json:"managed_nfsv4_acl,omitempty"`
+	MountdLog       *bool    `json:"mountd_log,omitempty"`
+	MountdPort      *int64   `json:"mountd_port,omitempty"`
+	Protocols       []string `json:"protocols,omitempty"`
+	Rdma            *bool    `json:"rdma,omitempty"`
+	RpclockdPort    *int64   `json:"rpclockd_port,omitempty"`
+	RpcstatdPort    *int64   `json:"rpcstatd_port,omitempty"`
+	Servers         *int64   `json:"servers,omitempty"`
+	StatdLockdLog   *bool    `json:"statd_lockd_log,omitempty"`
+	UDP             *bool    `json:"udp,omitempty"`
+	UserdManageGids *bool    `json:"userd_manage_gids,omitempty"`
+	V4              *bool    `json:"v4,omitempty"`
+	V4Domain        *string  `json:"v4_domain,omitempty"`
+	V4Krb           *bool    `json:"v4_krb,omitempty"`
+	V4KrbEnabled    *bool    `json:"v4_krb_enabled,omitempty"`
+	V4V3owner       *bool    `json:"v4_v3owner,omitempty"`
+}
+
+// NFSShareCreate is parameter 1 of sharing.nfs.create.
+type NFSShareCreate struct {
+	Aliases         []string `json:"aliases,omitempty"`
+	Comment         *string  `json:"comment,omitempty"`
+	Enabled         *bool    `json:"enabled,omitempty"`
+	ExposeSnapshots *bool    `json:"expose_snapshots,omitempty"`
+	Hosts           []string `json:"hosts,omitempty"`
+	MapallGroup     *string  `json:"mapall_group,omitempty"`
+	MapallUser      *string  `json:"mapall_user,omitempty"`
+	MaprootGroup    *string  `json:"maproot_group,omitempty"`
+	MaprootUser     *string  `json:"maproot_user,omitempty"`
+	Networks        []string `json:"networks,omitempty"`
+	Path            string   `json:"path"`
+	Ro              *bool    `json:"ro,omitempty"`
+	Security        []string `json:"security,omitempty"`
+}
+
+// NFSShareEntry is an entry in the result of sharing.nfs.query.
+type NFSShareEntry struct {
+	Aliases         []string `json:"aliases,omitempty"`
+	Comment         *string  `json:"comment,omitempty"`
+	Enabled         *bool    `json:"enabled,omitempty"`
+	ExposeSnapshots *bool    `json:"expose_snapshots,omitempty"`
+	Hosts           []string `json:"hosts,omitempty"`
+	ID              int64    `json:"id"`
+	Locked          *bool    `json:"locked,omitempty"`
+	MapallGroup     *string  `json:"mapall_group,omitempty"`
+	MapallUser      *string  `json:"mapall_user,omitempty"`
+	MaprootGroup    *string  `json:"maproot_group,omitempty"`
+	MaprootUser     *string  `json:"maproot_user,omitempty"`
+	Networks        []string `json:"networks,omitempty"`
+	Path            string   `json:"path"`
+	Ro              *bool    `json:"ro,omitempty"`
+	Security        []string `json:"security,omitempty"`
+}
+
+// NFSShareUpdate is parameter 2 of sharing.nfs.update.
+type NFSShareUpdate struct {
+	Aliases         []string `json:"aliases,omitempty"`
+	Comment         *string  `json:"comment,omitempty"`
+	Enabled         *bool    `json:"enabled,omitempty"`
+	ExposeSnapshots *bool    `json:"expose_snapshots,omitempty"`
+	Hosts           []string `json:"hosts,omitempty"`
+	MapallGroup     *string  `json:"mapall_group,omitempty"`
+	MapallUser      *string  `json:"mapall_user,omitempty"`
+	MaprootGroup    *string  `json:"maproot_group,omitempty"`
+	MaprootUser     *string  `json:"maproot_user,omitempty"`
+	Networks        []string `json:"networks,omitempty"`
+	Path            *string  `json:"path,omitempty"`
+	Ro              *bool    `json:"ro,omitempty"`
+	Security        []string `json:"security,omitempty"`
+}
+
+// NFSUpdate is parameter 1 of nfs.update.
+type NFSUpdate struct {
+	AllowNonroot    *bool    `json:"allow_nonroot,omitempty"`
+	Bindip          []string `json:"bindip,omitempty"`
+	MountdLog       *bool    `json:"mountd_log,omitempty"`
+	MountdPort      *int64   `json:"mountd_port,omitempty"`
+	Protocols       []string `json:"protocols,omitempty"`
+	Rdma            *bool    `json:"rdma,omitempty"`
+	RpclockdPort    *int64   `json:"rpclockd_port,omitempty"`
+	RpcstatdPort    *int64   `json:"rpcstatd_port,omitempty"`
+	Servers         *int64   `json:"servers,omitempty"`
+	StatdLockdLog   *bool    `json:"statd_lockd_log,omitempty"`
+	UserdManageGids *bool    `json:"userd_manage_gids,omitempty"`
+	V4Domain        *string  `json:"v4_domain,omitempty"`
+	V4Krb           *bool    `json:"v4_krb,omitempty"`
+}
+
+// PoolCreate is parameter 1 of pool.create.
+type PoolCreate struct {
+	AllowDuplicateSerials *bool                        `json:"allow_duplicate_serials,omitempty"`
+	Checksum              *string                      `json:"checksum,omitempty"`
+	DedupTableQuota       *string                      `json:"dedup_table_quota,omitempty"`
+	Deduplication         *string                      `json:"deduplication,omitempty"`
+	Encryption            *bool                        `json:"encryption,omitempty"`
+	EncryptionOptions     *PoolCreateEncryptionOptions `json:"encryption_options,omitempty"`
+	Name                  string                       `json:"name"`
+	Topology              PoolCreateTopology           `json:"topology"`
+}
+
+// PoolCreateEncryptionOptions appears in the schema of pool.create.
+type PoolCreateEncryptionOptions struct {
+	Algorithm   *string `json:"algorithm,omitempty"`
+	GenerateKey *bool   `json:"generate_key,omitempty"`
+	Key         *string `json:"key,omitempty"`
+	Passphrase  *string `json:"passphrase,omitempty"`
+	Pbkdf2iters *int64  `json:"pbkdf2iters,omitempty"`
+}
+
+// PoolCreateTopology appears in the schema of pool.create.
+type PoolCreateTopology struct {
+	Cache   []PoolCreateVdev `json:"cache,omitempty"`
+	Data    []PoolCreateVdev `json:"data"`
+	Dedup   []PoolCreateVdev `json:"dedup,omitempty"`
+	Log     []PoolCreateVdev `json:"log,omitempty"`
+	Spares  []string         `json:"spares,omitempty"`
+	Special []PoolCreateVdev `json:"special,omitempty"`
+}
+
+// PoolCreateVdev appears in the schema of pool.create.
+type PoolCreateVdev struct {
+	Disks           []string `json:"disks"`
+	DraidDataDisks  *int64   `json:"draid_data_disks,omitempty"`
+	DraidSpareDisks *int64   `json:"draid_spare_disks,omitempty"`
+	Type            string   `json:"type"`
+}
+
+// PoolEntry is an entry in the result of pool.query.
+type PoolEntry struct {
+	Allocated     *int64        `json:"allocated,omitempty"`
+	Encrypt       *int64        `json:"encrypt,omitempty"`
+	Fragmentation interface{}   `json:"fragmentation,omitempty"`
+	Free          *int64        `json:"free,omitempty"`
+	Freeing       *int64        `json:"freeing,omitempty"`
+	GUID          *string       `json:"guid,omitempty"`
+	Healthy       bool          `json:"healthy"`
+	ID            int64         `json:"id"`
+	IsUpgraded    *bool         `json:"is_upgraded,omitempty"`
+	Name          string        `json:"name"`
+	Path          string        `json:"path"`
+	Size          *int64        `json:"size,omitempty"`
+	Status        string        `json:"status"`
+	StatusCode    *string       `json:"status_code,omitempty"`
+	StatusDetail  *string       `json:"status_detail,omitempty"`
+	Topology      *PoolTopology `json:"topology,omitempty"`
+	Warning       *bool         `json:"warning,omitempty"`
+}
+
+// PoolTopology appears in the schema of pool.query.
+type PoolTopology struct {
+	Cache   []PoolTopologyVdev `json:"cache,omitempty"`
+	Data    []PoolTopologyVdev `json:"data,omitempty"`
+	Dedup   []PoolTopologyVdev `json:"dedup,omitempty"`
+	Log     []PoolTopologyVdev `json:"log,omitempty"`
+	Spare   []PoolTopologyVdev `json:"spare,omitempty"`
+	Special []PoolTopologyVdev `json:"special,omitempty"`
+}
+
+// PoolTopologyVdev appears in the schema of pool.query.
+type PoolTopologyVdev struct {
+	Children []PoolTopologyVdev `json:"children,omitempty"`
+	Disk     *string            `json:"disk,omitempty"`
+	GUID     *string            `json:"guid,omitempty"`
+	Name     *string            `json:"name,omitempty"`
+	Path     *string            `json:"path,omitempty"`
+	Status   string             `json:"status"`
+	Type     string             `json:"type"`
+}
+
+// SMBShareAudit appears in the schema of sharing.smb.create, sharing.smb.query, sharing.smb.update.
+type SMBShareAudit struct {
+	Enable     *bool    `json:"enable,omitempty"`
+	IgnoreList []string `json:"ignore_list,omitempty"`
+	WatchList  []string `json:"watch_list,omitempty"`
+}
+
+// SMBShareCreate is parameter 1 of sharing.smb.create.
+type SMBShareCreate struct {
+	AaplNameMangling            *bool            `json:"aapl_name_mangling,omitempty"`
+	Abe                         *bool            `json:"abe,omitempty"`
+	AccessBasedShareEnumeration *bool            `json:"access_based_share_enumeration,omitempty"`
+	ACL                         *bool            `json:"acl,omitempty"`
+	Afp                         *bool            `json:"afp,omitempty"`
+	Audit                       *SMBShareAudit   `json:"audit,omitempty"`
+	Auxsmbconf                  *string          `json:"auxsmbconf,omitempty"`
+	Browsable                   *bool            `json:"browsable,omitempty"`
+	Comment                     *string          `json:"comment,omitempty"`
+	Durablehandle               *bool            `json:"durablehandle,omitempty"`
+	Enabled                     *bool            `json:"enabled,omitempty"`
+	Fsrvp                       *bool            `json:"fsrvp,omitempty"`
+	Guestok                     *bool            `json:"guestok,omitempty"`
+	Home                        *bool            `json:"home,omitempty"`
+	Hostsallow                  []string         `json:"hostsallow,omitempty"`
+	Hostsdeny                   []string         `json:"hostsdeny,omitempty"`
+	Name                        string           `json:"name"`
+	Options                     *SMBShareOptions `json:"options,omitempty"`
+	Path                        string           `json:"path"`
+	PathSuffix                  *string          `json:"path_suffix,omitempty"`
+	Purpose                     *string          `json:"purpose,omitempty"`
+	Readonly                    *bool            `json:"readonly,omitempty"`
+	Recyclebin                  *bool            `json:"recyclebin,omitempty"`
+	Ro                          *bool            `json:"ro,omitempty"`
+	Shadowcopy                  *bool            `json:"shadowcopy,omitempty"`
+	Streams                     *bool            `json:"streams,omitempty"`
+	Timemachine                 *bool            `json:"timemachine,omitempty"`
+	TimemachineQuota            *int64           `json:"timemachine_quota,omitempty"`
+}
+
+// SMBShareEntry is an entry in the result of sharing.smb.query.
+type SMBShareEntry struct {
+	AaplNameMangling            *bool            `json:"aapl_name_mangling,omitempty"`
+	Abe                         *bool            `json:"abe,omitempty"`
+	AccessBasedShareEnumeration *bool            `json:"access_based_share_enumeration,omitempty"`
+	ACL                         *bool            `json:"acl,omitempty"`
+	Afp                         *bool            `json:"afp,omitempty"`
+	Audit                       *SMBShareAudit   `json:"audit,omitempty"`
+	Auxsmbconf                  *string          `json:"auxsmbconf,omitempty"`
+	Browsable                   *bool            `json:"browsable,omitempty"`
+	Comment                     *string          `json:"comment,omitempty"`
+	Durablehandle               *bool            `json:"durablehandle,omitempty"`
+	Enabled                     *bool            `json:"enabled,omitempty"`
+	Fsrvp                       *bool            `json:"fsrvp,omitempty"`
+	Guestok                     *bool            `json:"guestok,omitempty"`
+	Home                        *bool            `json:"home,omitempty"`
+	Hostsallow                  []string         `json:"hostsallow,omitempty"`
+	Hostsdeny                   []string         `json:"hostsdeny,omitempty"`
+	ID                          int64            `json:"id"`
+	Locked                      *bool            `json:"locked,omitempty"`
+	Name                        string           `json:"name"`
+	Options                     *SMBShareOptions `json:"options,omitempty"`
+	Path                        string           `json:"path"`
+	PathSuffix                  *string          `json:"path_suffix,omitempty"`
+	Purpose                     *string          `json:"purpose,omitempty"`
+	Readonly                    *bool            `json:"readonly,omitempty"`
+	Recyclebin                  *bool            `json:"recyclebin,omitempty"`
+	Ro                          *bool            `json:"ro,omitempty"`
+	Shadowcopy                  *bool            `json:"shadowcopy,omitempty"`
+	Streams                     *bool            `json:"streams,omitempty"`
+	Timemachine                 *bool            `json:"timemachine,omitempty"`
+	TimemachineQuota            *int64           `json:"timemachine_quota,omitempty"`
+}
+
+// SMBShareOptions appears in the schema of sharing.smb.create, sharing.smb.query, sharing.smb.update.
+type SMBShareOptions struct {
+	Auxsmbconf       *string  `json:"auxsmbconf,omitempty"`
+	Hostsallow       []string `json:"hostsallow,omitempty"`
+	Hostsdeny        []string `json:"hostsdeny,omitempty"`
+	PathSuffix       *string  `json:"path_suffix,omitempty"`
+	TimemachineQuota *int64   `json:"timemachine_quota,omitempty"`
+	Vuid             *string  `json:"vuid,omitempty"`
+}
+
+// SMBShareUpdate is parameter 2 of sharing.smb.update.
+type SMBShareUpdate struct {
+	AaplNameMangling            *bool            `json:"aapl_name_mangling,omitempty"`
+	Abe                         *bool            `json:"abe,omitempty"`
+	AccessBasedShareEnumeration *bool            `json:"access_based_share_enumeration,omitempty"`
+	ACL                         *bool            `json:"acl,omitempty"`
+	Afp                         *bool            `json:"afp,omitempty"`
+	Audit                       *SMBShareAudit   `json:"audit,omitempty"`
+	Auxsmbconf                  *string          `json:"auxsmbconf,omitempty"`
+	Browsable                   *bool            `json:"browsable,omitempty"`
+	Comment                     *string          `json:"comment,omitempty"`
+	Durablehandle               *bool            `json:"durablehandle,omitempty"`
+	Enabled                     *bool            `json:"enabled,omitempty"`
+	Fsrvp                       *bool            `json:"fsrvp,omitempty"`
+	Guestok                     *bool            `json:"guestok,omitempty"`
+	Home                        *bool            `json:"home,omitempty"`
+	Hostsallow                  []string         `json:"hostsallow,omitempty"`
+	Hostsdeny                   []string         `json:"hostsdeny,omitempty"`
+	Name                        *string          `json:"name,omitempty"`
+	Options                     *SMBShareOptions `json:"options,omitempty"`
+	Path                        *string          `json:"path,omitempty"`
+	PathSuffix                  *string          `json:"path_suffix,omitempty"`
+	Purpose                     *string          `json:"purpose,omitempty"`
+	Readonly                    *bool            `json:"readonly,omitempty"`
+	Recyclebin                  *bool            `json:"recyclebin,omitempty"`
+	Ro                          *bool            `json:"ro,omitempty"`
+	Shadowcopy                  *bool            `json:"shadowcopy,omitempty"`
+	Streams                     *bool            `json:"streams,omitempty"`
+	Timemachine                 *bool            `json:"timemachine,omitempty"`
+	TimemachineQuota            *int64           `json:"timemachine_quota,omitempty"`
+}
+
+// ServiceEntry is an entry in the result of service.query.
+type ServiceEntry struct {
+	Enable  *bool   `json:"enable,omitempty"`
+	ID      int64   `json:"id"`
+	Pids    []int64 `json:"pids,omitempty"`
+	Service string  `json:"service"`
+	State   string  `json:"state"`
+}
+
+// SnapshotCreate is parameter 1 of zfs.snapshot.create.
+type SnapshotCreate struct {
+	Dataset      string                 `json:"dataset"`
+	Exclude      []string               `json:"exclude,omitempty"`
+	Name         *string                `json:"name,omitempty"`
+	NamingSchema *string                `json:"naming_schema,omitempty"`
+	Properties   map[string]interface{} `json:"properties,omitempty"`
+	Recursive    *bool                  `json:"recursive,omitempty"`
+	SuspendVms   *bool                  `json:"suspend_vms,omitempty"`
+	VmwareSync   *bool                  `json:"vmware_sync,omitempty"`
+}
+
+// SnapshotEntry is an entry in the result of zfs.snapshot.query.
+type SnapshotEntry struct {
+	Createtxg      *string                `json:"createtxg,omitempty"`
+	Dataset        *string                `json:"dataset,omitempty"`
+	Holds          interface{}            `json:"holds,omitempty"`
+	ID             string                 `json:"id"`
+	Name           string                 `json:"name"`
+	Pool           *string                `json:"pool,omitempty"`
+	Properties     map[string]ZFSProperty `json:"properties,omitempty"`
+	SnapshotName   *string                `json:"snapshot_name,omitempty"`
+	Type           *string                `json:"type,omitempty"`
+	UserProperties map[string]interface{} `json:"user_properties,omitempty"`
+}
+
+// SnapshotUpdate is parameter 2 of zfs.snapshot.update.
+type SnapshotUpdate struct {
+	UserPropertiesUpdate []SnapshotUpdateUserPropertiesUpdate `json:"user_properties_update,omitempty"`
+}
+
+// SnapshotUpdateUserPropertiesUpdate appears in the schema of zfs.snapshot.update.
+type SnapshotUpdateUserPropertiesUpdate struct {
+	Key    string  `json:"key"`
+	Remove *bool   `json:"remove,omitempty"`
+	Value  *string `json:"value,omitempty"`
+}
+
+// StaticRouteCreate is parameter 1 of staticroute.create.
+type StaticRouteCreate struct {
+	Description *string `json:"description,omitempty"`
+	Destination string  `json:"destination"`
+	Gateway     string  `json:"gateway"`
+}
+
+// StaticRouteEntry is an entry in the result of staticroute.query.
+type StaticRouteEntry struct {
+	Description *string `json:"description,omitempty"`
+	Destination string  `json:"destination"`
+	Gateway     string  `json:"gateway"`
+	ID          int64   `json:"id"`
+}
+
+// StaticRouteUpdate is parameter 2 of staticroute.update.
+type StaticRouteUpdate struct {
+	Description *string `json:"description,omitempty"`
+	Destination *string `json:"destination,omitempty"`
+	Gateway     *string `json:"gateway,omitempty"`
+}
+
+// UserCreate is parameter 1 of user.create.
+type UserCreate struct {
+	Email                *string  `json:"email,omitempty"`
+	FullName             string   `json:"full_name"`
+	Group                *int64   `json:"group,omitempty"`
+	GroupCreate          *bool    `json:"group_create,omitempty"`
+	Groups               []int64  `json:"groups,omitempty"`
+	Home                 *string  `json:"home,omitempty"`
+	HomeCreate           *bool    `json:"home_create,omitempty"`
+	HomeMode             *string  `json:"home_mode,omitempty"`
+	Locked               *bool    `json:"locked,omitempty"`
+	Password             *string  `json:"password,omitempty"`
+	PasswordDisabled     *bool    `json:"password_disabled,omitempty"`
+	RandomPassword       *bool    `json:"random_password,omitempty"`
+	Shell                *string  `json:"shell,omitempty"`
+	SMB                  *bool    `json:"smb,omitempty"`
+	SSHPasswordEnabled   *bool    `json:"ssh_password_enabled,omitempty"`
+	Sshpubkey            *string  `json:"sshpubkey,omitempty"`
+	SudoCommands         []string `json:"sudo_commands,omitempty"`
+	SudoCommandsNopasswd []string `json:"sudo_commands_nopasswd,omitempty"`
+	UID                  *int64   `json:"uid,omitempty"`
+	Username             string   `json:"username"`
+	UsernsIdmap          *int64   `json:"userns_idmap,omitempty"`
+}
+
+// UserEntry is an entry in the result of user.query.
+type UserEntry struct {
+	APIKeys                 []int64    `json:"api_keys,omitempty"`
+	Builtin                 *bool      `json:"builtin,omitempty"`
+	Email                   *string    `json:"email,omitempty"`
+	FullName                *string    `json:"full_name,omitempty"`
+	Group                   *UserGroup `json:"group,omitempty"`
+	Groups                  []int64    `json:"groups,omitempty"`
+	Home                    *string    `json:"home,omitempty"`
+	ID                      int64      `json:"id"`
+	Immutable               *bool      `json:"immutable,omitempty"`
+	Local                   *bool      `json:"local,omitempty"`
+	Locked                  *bool      `json:"locked,omitempty"`
+	PasswordDisabled        *bool      `json:"password_disabled,omitempty"`
+	Roles                   []string   `json:"roles,omitempty"`
+	Shell                   *string    `json:"shell,omitempty"`
+	SMB                     *bool      `json:"smb,omitempty"`
+	SSHPasswordEnabled      *bool      `json:"ssh_password_enabled,omitempty"`
+	Sshpubkey               *string    `json:"sshpubkey,omitempty"`
+	SudoCommands            []string   `json:"sudo_commands,omitempty"`
+	SudoCommandsNopasswd    []string   `json:"sudo_commands_nopasswd,omitempty"`
+	TwofactorAuthConfigured *bool      `json:"twofactor_auth_configured,omitempty"`
+	UID                     int64      `json:"uid"`
+	Username                string     `json:"username"`
+}
+
+// UserGroup appears in the schema of user.query.
+type UserGroup struct {
+	BsdgrpBuiltin              *bool    `json:"bsdgrp_builtin,omitempty"`
+	BsdgrpGID                  *int64   `json:"bsdgrp_gid,omitempty"`
+	BsdgrpGroup                *string  `json:"bsdgrp_group,omitempty"`
+	BsdgrpSMB                  *bool    `json:"bsdgrp_smb,omitempty"`
+	BsdgrpSudoCommands         []string `json:"bsdgrp_sudo_commands,omitempty"`
+	BsdgrpSudoCommandsNopasswd []string `json:"bsdgrp_sudo_commands_nopasswd,omitempty"`
+	ID                         int64    `json:"id"`
+}
+
+// UserUpdate is parameter 2 of user.update.
+type UserUpdate struct {
+	Email                *string  `json:"email,omitempty"`
+	FullName             *string  `json:"full_name,omitempty"`
+	Group                *int64   `json:"group,omitempty"`
+	Groups               []int64  `json:"groups,omitempty"`
+	Home                 *string  `json:"home,omitempty"`
+	HomeCreate           *bool    `json:"home_create,omitempty"`
+	HomeMode             *string  `json:"home_mode,omitempty"`
+	Locked               *bool    `json:"locked,omitempty"`
+	Password             *string  `json:"password,omitempty"`
+	PasswordDisabled     *bool    `json:"password_disabled,omitempty"`
+	RandomPassword       *bool    `json:"random_password,omitempty"`
+	Shell                *string  `json:"shell,omitempty"`
+	SMB                  *bool    `json:"smb,omitempty"`
+	SSHPasswordEnabled   *bool    `json:"ssh_password_enabled,omitempty"`
+	Sshpubkey            *string  `json:"sshpubkey,omitempty"`
+	SudoCommands         []string `json:"sudo_commands,omitempty"`
+	SudoCommandsNopasswd []string `json:"sudo_commands_nopasswd,omitempty"`
+	UID                  *int64   `json:"uid,omitempty"`
+	Username             *string  `json:"username,omitempty"`
+	UsernsIdmap          *int64   `json:"userns_idmap,omitempty"`
+}
+
+// VMCreate is parameter 1 of vm.create.
+type VMCreate struct {
+	ArchType                   *string `json:"arch_type,omitempty"`
+	Autostart                  *bool   `json:"autostart,omitempty"`
+	Bootloader                 *string `json:"bootloader,omitempty"`
+	BootloaderOVMF             *string `json:"bootloader_ovmf,omitempty"`
+	CommandLineArgs            *string `json:"command_line_args,omitempty"`
+	Cores                      *int64  `json:"cores,omitempty"`
+	CPUMode                    *string `json:"cpu_mode,omitempty"`
+	CPUModel                   *string `json:"cpu_model,omitempty"`
+	Cpuset                     *string `json:"cpuset,omitempty"`
+	Description                *string `json:"description,omitempty"`
+	EnableCPUTopologyExtension *bool   `json:"enable_cpu_topology_extension,omitempty"`
+	EnsureDisplayDevice        *bool   `json:"ensure_display_device,omitempty"`
+	HideFromMSR                *bool   `json:"hide_from_msr,omitempty"`
+	HypervEnlightenments       *bool   `json:"hyperv_enlightenments,omitempty"`
+	MachineType                *string `json:"machine_type,omitempty"`
+	Memory                     int64   `json:"memory"`
+	MinMemory                  *int64  `json:"min_memory,omitempty"`
+	Name                       string  `json:"name"`
+	Nodeset                    *string `json:"nodeset,omitempty"`
+	PinVcpus                   *bool   `json:"pin_vcpus,omitempty"`
+	ShutdownTimeout            *int64  `json:"shutdown_timeout,omitempty"`
+	SuspendOnSnapshot          *bool   `json:"suspend_on_snapshot,omitempty"`
+	Threads                    *int64  `json:"threads,omitempty"`
+	Time                       *string `json:"time,omitempty"`
+	TrustedPlatformModule      *bool   `json:"trusted_platform_module,omitempty"`
+	UUID                       *string `json:"uuid,omitempty"`
+	Vcpus                      *int64  `json:"vcpus,omitempty"`
+}
+
+// VMDeviceAttributes appears in the schema of vm.device.create, vm.device.query, vm.device.update.
+type VMDeviceAttributes struct {
+	Bind                *string `json:"bind,omitempty"`
+	Boot                *bool   `json:"boot,omitempty"`
+	ControllerType      *string `json:"controller_type,omitempty"`
+	Device              *string `json:"device,omitempty"`
+	Dtype               string  `json:"dtype"`
+	Iotype              *string `json:"iotype,omitempty"`
+	LogicalSectorsize   *int64  `json:"logical_sectorsize,omitempty"`
+	MAC                 *string `json:"mac,omitempty"`
+	NICAttach           *string `json:"nic_attach,omitempty"`
+	Path                *string `json:"path,omitempty"`
+	PhysicalSectorsize  *int64  `json:"physical_sectorsize,omitempty"`
+	Port                *int64  `json:"port,omitempty"`
+	Pptdev              *string `json:"pptdev,omitempty"`
+	Resolution          *string `json:"resolution,omitempty"`
+	Serial              *string `json:"serial,omitempty"`
+	Size                *int64  `json:"size,omitempty"`
+	TrustGuestRxFilters *bool   `json:"trust_guest_rx_filters,omitempty"`
+	Type                *string `json:"type,omitempty"`
+	Wait                *bool   `json:"wait,omitempty"`
+	Web                 *bool   `json:"web,omitempty"`
+	WebPort             *int64  `json:"web_port,omitempty"`
+}
+
+// VMDeviceCreate is parameter 1 of vm.device.create.
+type VMDeviceCreate struct {
+	Attributes VMDeviceAttributes `json:"attributes"`
+	Dtype      *string            `json:"dtype,omitempty"`
+	Order      *int64             `json:"order,omitempty"`
+	VM         int64              `json:"vm"`
+}
+
+// VMDeviceEntry is an entry in the result of vm.device.query.
+type VMDeviceEntry struct {
+	Attributes *VMDeviceAttributes `json:"attributes,omitempty"`
+	Dtype      *string             `json:"dtype,omitempty"`
+	ID         int64               `json:"id"`
+	Order      *int64              `json:"order,omitempty"`
+	VM         int64               `json:"vm"`
+}
+
+// VMDeviceUpdate is parameter 2 of vm.device.update.
+type VMDeviceUpdate struct {
+	Attributes *VMDeviceAttributes `json:"attributes,omitempty"`
+	Order      *int64              `json:"order,omitempty"`
+	VM         *int64              `json:"vm,omitempty"`
+}
+
+// VMEntry is an entry in the result of vm.query.
+type VMEntry struct {
+	ArchType                   *string                  `json:"arch_type,omitempty"`
+	Autostart                  *bool                    `json:"autostart,omitempty"`
+	Bootloader                 *string                  `json:"bootloader,omitempty"`
+	BootloaderOVMF             *string                  `json:"bootloader_ovmf,omitempty"`
+	CommandLineArgs            *string                  `json:"command_line_args,omitempty"`
+	Cores                      *int64                   `json:"cores,omitempty"`
+	CPUMode                    *string                  `json:"cpu_mode,omitempty"`
+	CPUModel                   *string                  `json:"cpu_model,omitempty"`
+	Cpuset                     *string                  `json:"cpuset,omitempty"`
+	Description                *string                  `json:"description,omitempty"`
+	Devices                    []map[string]interface{} `json:"devices,omitempty"`
+	DisplayAvailable           *bool                    `json:"display_available,omitempty"`
+	EnableCPUTopologyExtension *bool                    `json:"enable_cpu_topology_extension,omitempty"`
+	EnsureDisplayDevice        *bool                    `json:"ensure_display_device,omitempty"`
+	HideFromMSR                *bool                    `json:"hide_from_msr,omitempty"`
+	HypervEnlightenments       *bool                    `json:"hyperv_enlightenments,omitempty"`
+	ID                         int64                    `json:"id"`
+	MachineType                *string                  `json:"machine_type,omitempty"`
+	Memory                     *int64                   `json:"memory,omitempty"`
+	MinMemory                  *int64                   `json:"min_memory,omitempty"`
+	Name                       string                   `json:"name"`
+	Nodeset                    *string                  `json:"nodeset,omitempty"`
+	PinVcpus                   *bool                    `json:"pin_vcpus,omitempty"`
+	ShutdownTimeout            *int64                   `json:"shutdown_timeout,omitempty"`
+	Status                     *VMStatus                `json:"status,omitempty"`
+	SuspendOnSnapshot          *bool                    `json:"suspend_on_snapshot,omitempty"`
+	Threads                    *int64                   `json:"threads,omitempty"`
+	Time                       *string                  `json:"time,omitempty"`
+	TrustedPlatformModule      *bool                    `json:"trusted_platform_module,omitempty"`
+	UUID                       *string                  `json:"uuid,omitempty"`
+	Vcpus                      *int64                   `json:"vcpus,omitempty"`
+}
+
+// VMStatus appears in the schema of vm.query.
+type VMStatus struct {
+	DomainState *string `json:"domain_state,omitempty"`
+	Pid         *int64  `json:"pid,omitempty"`
+	State       string  `json:"state"`
+}
+
+// VMUpdate is parameter 2 of vm.update.
+type VMUpdate struct {
+	ArchType                   *string `json:"arch_type,omitempty"`
+	Autostart                  *bool   `json:"autostart,omitempty"`
+	Bootloader                 *string `json:"bootloader,omitempty"`
+	BootloaderOVMF             *string `json:"bootloader_ovmf,omitempty"`
+	CommandLineArgs            *string `json:"command_line_args,omitempty"`
+	Cores                      *int64  `json:"cores,omitempty"`
+	CPUMode                    *string `json:"cpu_mode,omitempty"`
+	CPUModel                   *string `json:"cpu_model,omitempty"`
+	Cpuset                     *string `json:"cpuset,omitempty"`
+	Description                *string `json:"description,omitempty"`
+	EnableCPUTopologyExtension *bool   `json:"enable_cpu_topology_extension,omitempty"`
+	EnsureDisplayDevice        *bool   `json:"ensure_display_device,omitempty"`
+	HideFromMSR                *bool   `json:"hide_from_msr,omitempty"`
+	HypervEnlightenments       *bool   `json:"hyperv_enlightenments,omitempty"`
+	MachineType                *string `json:"machine_type,omitempty"`
+	Memory                     *int64  `json:"memory,omitempty"`
+	MinMemory                  *int64  `json:"min_memory,omitempty"`
+	Name                       *string `json:"name,omitempty"`
+	Nodeset                    *string `json:"nodeset,omitempty"`
+	PinVcpus                   *bool   `json:"pin_vcpus,omitempty"`
+	ShutdownTimeout            *int64  `json:"shutdown_timeout,omitempty"`
+	SuspendOnSnapshot          *bool   `json:"suspend_on_snapshot,omitempty"`
+	Threads                    *int64  `json:"threads,omitempty"`
+	Time                       *string `json:"time,omitempty"`
+	TrustedPlatformModule      *bool   `json:"trusted_platform_module,omitempty"`
+	UUID                       *string `json:"uuid,omitempty"`
+	Vcpus                      *int64  `json:"vcpus,omitempty"`
+}
+
+// ZFSProperty appears in the schema of pool.dataset.query, zfs.snapshot.query.
+type ZFSProperty struct {
+	Parsed   interface{} `json:"parsed,omitempty"`
+	Rawvalue *string     `json:"rawvalue,omitempty"`
+	Source   *string     `json:"source,omitempty"`
+	Value    *string     `json:"value,omitempty"`
+}
@@ -0,0 +1,23 @@
+package api
+
+// Text returns the value of a property as TrueNAS formats it, e.g. "LZ4" or
+// "10G". ok is false if the property or its value is missing.
+func (p *ZFSProperty) Text() (value string, ok bool) {
+	if p == nil || p.Value == nil {
+		return "", false
+	}
+	return *p.Value, true
+}
+
+// Int returns the parsed value of a numeric property, e.g. a quota in bytes.
+// ok is false if the property is missing or its parsed value isn't a number.
+func (p *ZFSProperty) Int() (value int64, ok bool) {
+	if p == nil {
+		return 0, false
+	}
+	parsed, ok := p.Parsed.(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(parsed), true
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeMissingFields(t *testing.T) {
+	// A dataset with only its identity fields decodes, and the property
+	// helpers report the rest as missing rather than panicking
+	var dataset DatasetEntry
+	if err := json.Unmarshal([]byte(`{"id":"tank/data","name":"tank/data","type":"FILESYSTEM","pool":"tank","quota":{"value":null,"parsed":null}}`), &dataset); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if _, ok := dataset.Compression.Text(); ok {
+		t.Error("Compression.Text() ok for a missing property")
+	}
+	if _, ok := dataset.Quota.Int(); ok {
+		t.Error("Quota.Int() ok for a null value")
+	}
+	if _, ok := dataset.Quota.Text(); ok {
+		t.Error("Quota.Text() ok for a null value")
+	}
+	if dataset.Mountpoint != nil || dataset.Encrypted != nil {
+		t.Errorf("missing fields decoded as %v, %v", dataset.Mountpoint, dataset.Encrypted)
+	}
+}
+
+func TestZFSProperty(t *testing.T) {
+	var p ZFSProperty
+	if err := json.Unmarshal([]byte(`{"value":"10G","rawvalue":"10737418240","parsed":10737418240,"source":"LOCAL"}`), &p); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if v, ok := p.Text(); !ok || v != "10G" {
+		t.Errorf("Text() = %q, %v", v, ok)
+	}
+	if v, ok := p.Int(); !ok || v != 10737418240 {
+		t.Errorf("Int() = %d, %v", v, ok)
+	}
+
+	p = ZFSProperty{Parsed: "lz4"}
+	if _, ok := p.Int(); ok {
+		t.Error("Int() ok for a string value")
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPasswordAuthenticationWithOTP(t *testing.T) {
+	ts := newTestServer(t)
+
+	var mechanisms []string
+	ts.handle("auth.login_ex", func(params json.RawMessage) (interface{}, *JSONRPCError) {
+		var args []map[string]interface{}
+		_ = json.Unmarshal(params, &args)
+		mechanism, _ := args[0]["mechanism"].(string)
+		mechanisms = append(mechanisms, mechanism)
+
+		switch mechanism {
+		case "PASSWORD_PLAIN":
+			if args[0]["username"] != "admin" || args[0]["password"] != "secret" {
+				return map[string]interface{}{"response_type": "AUTH_ERR"}, nil
+			}
+			return map[string]interface{}{"response_type": "OTP_REQUIRED", "username": "admin"}, nil
+		case "OTP_TOKEN":
+			if args[0]["otp_token"] != "123456" {
+				return map[string]interface{}{"response_type": "AUTH_ERR"}, nil
+			}
+			return map[string]interface{}{"response_type": "SUCCESS"}, nil
+		}
+		return map[string]interface{}{"response_type": "AUTH_ERR"}, nil
+	})
+
+	c := NewClient(&Config{
+		Host:     ts.host(),
+		Username: "admin",
+		Password: "secret",
+		OTPToken: "123456",
+		Timeout:  5 * time.Second,
+	})
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if len(mechanisms) != 2 || mechanisms[0] != "PASSWORD_PLAIN" || mechanisms[1] != "OTP_TOKEN" {
+		t.Errorf("login_ex mechanisms = %v, want [PASSWORD_PLAIN OTP_TOKEN]", mechanisms)
+	}
+}
+
+func TestPasswordAuthenticationMissingOTP(t *testing.T) {
+	ts := newTestServer(t)
+	ts.handle("auth.login_ex", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return map[string]interface{}{"response_type": "OTP_REQUIRED"}, nil
+	})
+
+	c := NewClient(&Config{
+		Host:     ts.host(),
+		Username: "admin",
+		Password: "secret",
+		Timeout:  5 * time.Second,
+	})
+	t.Cleanup(func() { _ = c.Close() })
+
+	err := c.Connect(context.Background())
+	if err == nil {
+		t.Fatal("Connect() expected error when OTP is required but not configured")
+	}
+	if !contains(err.Error(), "otp_token") {
+		t.Errorf("Connect() error = %v, want mention of otp_token", err)
+	}
+	if !IsAuthError(err) {
+		t.Errorf("IsAuthError(%v) = false, want true", err)
+	}
+	if c.isConnected() {
+		t.Error("client marked connected after failed authentication")
+	}
+}
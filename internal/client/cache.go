@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// readCache keeps the results of read-only calls for a short time so that
+// refreshing many resources of one kind costs one bulk query instead of a
+// get_instance call each. Identical reads in flight at the same time share a
+// single call. Any other call invalidates the cached reads of its group, see
+// cacheGroup.
+type readCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	results map[string]cachedResult
+	bulk    map[string]cachedBulk
+	// generations counts the invalidations of each group, so a read that
+	// was in flight across a mutation doesn't store its stale result
+	generations map[string]uint64
+
+	flight singleflight.Group
+}
+
+// cachedResult is the raw result of a read-only call
+type cachedResult struct {
+	group   string
+	data    json.RawMessage
+	expires time.Time
+}
+
+// cachedBulk is every record of a resource keyed by its JSON-encoded id
+type cachedBulk struct {
+	records map[string]json.RawMessage
+	expires time.Time
+}
+
+func newReadCache(ttl time.Duration) *readCache {
+	return &readCache{
+		ttl:         ttl,
+		results:     make(map[string]cachedResult),
+		bulk:        make(map[string]cachedBulk),
+		generations: make(map[string]uint64),
+	}
+}
+
+// cacheGroup returns the group of a method or resource name whose cached
+// reads a mutation invalidates: its first component, so that changing a
+// vm.device also drops cached vm.query results, which embed the devices.
+// Snapshots and datasets share a group since each changes the other's space
+// accounting.
+func cacheGroup(name string) string {
+	group, _, _ := strings.Cut(name, ".")
+	if group == "zfs" {
+		return "pool"
+	}
+	return group
+}
+
+// isCacheableRead reports whether the result of a method may be served from
+// the cache. Job and session state under core is never cached.
+func isCacheableRead(method string) bool {
+	return isReadOnlyMethod(method) && !strings.HasPrefix(method, "core.")
+}
+
+// isSessionMethod reports whether a method only concerns the connection
+// itself and leaves cached reads valid
+func isSessionMethod(method string) bool {
+	return strings.HasPrefix(method, "core.") || strings.HasPrefix(method, "auth.")
+}
+
+// read serves a read-only call from the cache, calling fetch on a miss
+func (rc *readCache) read(method string, params interface{}, result interface{}, fetch func(*json.RawMessage) error) error {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		var data json.RawMessage
+		if err := fetch(&data); err != nil {
+			return err
+		}
+		return decodeResult(data, result)
+	}
+	key := method + " " + string(encoded)
+	group := cacheGroup(method)
+
+	rc.mu.Lock()
+	entry, ok := rc.results[key]
+	gen := rc.generations[group]
+	rc.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return decodeResult(entry.data, result)
+	}
+
+	v, err, _ := rc.flight.Do(fmt.Sprintf("%s@%d", key, gen), func() (interface{}, error) {
+		var data json.RawMessage
+		if err := fetch(&data); err != nil {
+			return nil, err
+		}
+		rc.mu.Lock()
+		if rc.generations[group] == gen {
+			rc.results[key] = cachedResult{group: group, data: data, expires: time.Now().Add(rc.ttl)}
+		}
+		rc.mu.Unlock()
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	return decodeResult(v.(json.RawMessage), result)
+}
+
+// records returns every record of a resource, calling fetch if they aren't
+// cached
+func (rc *readCache) records(resource string, fetch func(*[]json.RawMessage) error) (map[string]json.RawMessage, error) {
+	group := cacheGroup(resource)
+
+	rc.mu.Lock()
+	entry, ok := rc.bulk[resource]
+	gen := rc.generations[group]
+	rc.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.records, nil
+	}
+
+	v, err, _ := rc.flight.Do(fmt.Sprintf("bulk %s@%d", resource, gen), func() (interface{}, error) {
+		var list []json.RawMessage
+		if err := fetch(&list); err != nil {
+			return nil, err
+		}
+		records := make(map[string]json.RawMessage, len(list))
+		for _, rec := range list {
+			var ref struct {
+				ID json.RawMessage `json:"id"`
+			}
+			if json.Unmarshal(rec, &ref) == nil && len(ref.ID) > 0 {
+				records[string(ref.ID)] = rec
+			}
+		}
+		rc.mu.Lock()
+		if rc.generations[group] == gen {
+			rc.bulk[resource] = cachedBulk{records: records, expires: time.Now().Add(rc.ttl)}
+		}
+		rc.mu.Unlock()
+		return records, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]json.RawMessage), nil
+}
+
+// invalidate drops the cached reads of the group a method belongs to
+func (rc *readCache) invalidate(method string) {
+	if rc == nil || method == "" || isSessionMethod(method) {
+		return
+	}
+	group := cacheGroup(method)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.generations[group]++
+	for key, entry := range rc.results {
+		if entry.group == group {
+			delete(rc.results, key)
+		}
+	}
+	for resource := range rc.bulk {
+		if cacheGroup(resource) == group {
+			delete(rc.bulk, resource)
+		}
+	}
+}
+
+// invalidateJob drops the cached reads a finished job may have changed.
+// Job-based methods return before the change is made, so the invalidation
+// when the call returned isn't enough.
+func (rc *readCache) invalidateJob(job map[string]interface{}) {
+	method, _ := job["method"].(string)
+	rc.invalidate(method)
+}
+
+// decodeResult unmarshals a raw result into result, like call does
+func decodeResult(data json.RawMessage, result interface{}) error {
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// cachedInstance looks an instance up among the cached records of its
+// resource, fetching them with one unfiltered query if needed. It returns
+// false if the instance isn't there, or the query failed, so the caller can
+// fall back to get_instance.
+func (c *Client) cachedInstance(ctx context.Context, resource string, id interface{}, result interface{}) (bool, error) {
+	key, err := json.Marshal(id)
+	if err != nil {
+		return false, nil
+	}
+	records, err := c.cache.records(resource, func(list *[]json.RawMessage) error {
+		return c.callWithRetry(ctx, resource+".query", NewQueryParams().queryArgs(), list)
+	})
+	if err != nil {
+		return false, nil
+	}
+	rec, ok := records[string(key)]
+	if !ok {
+		return false, nil
+	}
+	return true, decodeResult(rec, result)
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+type cacheTestGroup struct {
+	ID    int64  `json:"id"`
+	Group string `json:"group"`
+}
+
+func TestReadCache(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+	var ids []interface{}
+	for i := 0; i < 50; i++ {
+		ids = append(ids, srv.Seed("group", map[string]interface{}{"group": fmt.Sprintf("group%02d", i), "gid": float64(1000 + i)}))
+	}
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, CacheTTL: time.Minute})
+	defer c.Close()
+	ctx := context.Background()
+
+	t.Run("bulk query serves get_instance", func(t *testing.T) {
+		for i, id := range ids {
+			var group cacheTestGroup
+			if err := c.GetInstance(ctx, "group", id, &group); err != nil {
+				t.Fatalf("GetInstance(%v) error = %v", id, err)
+			}
+			if want := fmt.Sprintf("group%02d", i); group.Group != want {
+				t.Errorf("GetInstance(%v) = %s, want %s", id, group.Group, want)
+			}
+		}
+		if calls := srv.CallCount("group.query"); calls != 1 {
+			t.Errorf("group.query called %d times, want 1", calls)
+		}
+		if calls := srv.CallCount("group.get_instance"); calls != 0 {
+			t.Errorf("group.get_instance called %d times, want 0", calls)
+		}
+	})
+
+	t.Run("missing instance falls back to get_instance", func(t *testing.T) {
+		var group cacheTestGroup
+		err := c.GetInstance(ctx, "group", 9999, &group)
+		if !IsNotFoundError(err) {
+			t.Errorf("GetInstance() error = %v, want not found", err)
+		}
+		if calls := srv.CallCount("group.get_instance"); calls != 1 {
+			t.Errorf("group.get_instance called %d times, want 1", calls)
+		}
+	})
+
+	t.Run("mutation invalidates", func(t *testing.T) {
+		before := srv.CallCount("group.query")
+		if err := c.Update(ctx, "group", ids[0], map[string]interface{}{"group": "renamed"}, nil); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		var group cacheTestGroup
+		if err := c.GetInstance(ctx, "group", ids[0], &group); err != nil {
+			t.Fatalf("GetInstance() error = %v", err)
+		}
+		if group.Group != "renamed" {
+			t.Errorf("GetInstance() after update = %s, want renamed", group.Group)
+		}
+		if calls := srv.CallCount("group.query") - before; calls != 1 {
+			t.Errorf("group.query called %d times after update, want 1", calls)
+		}
+	})
+
+	t.Run("other namespaces stay cached", func(t *testing.T) {
+		before := srv.CallCount("group.query")
+		if err := c.Create(ctx, "staticroute", map[string]interface{}{"destination": "10.0.0.0/8", "gateway": "192.168.1.1"}, nil); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		var group cacheTestGroup
+		if err := c.GetInstance(ctx, "group", ids[1], &group); err != nil {
+			t.Fatalf("GetInstance() error = %v", err)
+		}
+		if calls := srv.CallCount("group.query") - before; calls != 0 {
+			t.Errorf("group.query called %d times after an unrelated change, want 0", calls)
+		}
+	})
+
+	t.Run("results are not shared", func(t *testing.T) {
+		var first, second []map[string]interface{}
+		params := NewQueryParams().WithFilter("gid", OpLess, 1005)
+		if err := c.Query(ctx, "group", params, &first); err != nil {
+			t.Fatal(err)
+		}
+		first[0]["group"] = "modified"
+		if err := c.Query(ctx, "group", params, &second); err != nil {
+			t.Fatal(err)
+		}
+		if second[0]["group"] == "modified" {
+			t.Error("a cached result was changed by an earlier caller")
+		}
+	})
+}
+
+func TestReadCacheCoalescing(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+	release := make(chan struct{})
+	srv.Handle("group.query", func([]json.RawMessage) (interface{}, error) {
+		<-release
+		return []interface{}{map[string]interface{}{"id": 1, "group": "wheel"}}, nil
+	})
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, CacheTTL: time.Minute})
+	defer c.Close()
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var groups []cacheTestGroup
+			if err := c.Query(context.Background(), "group", nil, &groups); err != nil {
+				errs <- err
+			} else if len(groups) != 1 {
+				errs <- fmt.Errorf("got %d groups, want 1", len(groups))
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if calls := srv.CallCount("group.query"); calls != 1 {
+		t.Errorf("group.query called %d times, want 1", calls)
+	}
+}
+
+func TestReadCacheExpiry(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+	id := srv.Seed("group", map[string]interface{}{"group": "wheel", "gid": float64(0)})
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, CacheTTL: 50 * time.Millisecond})
+	defer c.Close()
+	ctx := context.Background()
+
+	var group cacheTestGroup
+	for i := 0; i < 2; i++ {
+		if err := c.GetInstance(ctx, "group", id, &group); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := c.GetInstance(ctx, "group", id, &group); err != nil {
+		t.Fatal(err)
+	}
+	if calls := srv.CallCount("group.query"); calls != 2 {
+		t.Errorf("group.query called %d times, want 2", calls)
+	}
+}
+
+func TestCacheGroup(t *testing.T) {
+	tests := map[string]string{
+		"pool.dataset.update": "pool",
+		"zfs.snapshot.create": "pool",
+		"vm.device.query":     "vm",
+		"sharing.nfs":         "sharing",
+		"staticroute":         "staticroute",
+	}
+	for name, want := range tests {
+		if got := cacheGroup(name); got != want {
+			t.Errorf("cacheGroup(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
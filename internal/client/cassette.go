@@ -0,0 +1,315 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Transport carries JSON-RPC calls to TrueNAS. By default the client talks
+// to Host over a WebSocket; Config.Transport replaces that, e.g. with a
+// Replayer in tests. API errors are returned in the response, not as err.
+type Transport interface {
+	RoundTrip(ctx context.Context, req *JSONRPCRequest) (*JSONRPCResponse, error)
+}
+
+// notifier is implemented by transports that deliver server-pushed
+// notifications themselves rather than through the WebSocket reader
+type notifier interface {
+	setNotificationHandler(handler func(*JSONRPCNotification))
+}
+
+// redacted replaces secrets in recorded sessions and wire logs
+const redacted = "REDACTED"
+
+// Interaction is one recorded call: the request, the response and the
+// notifications the server pushed before the next call completed
+type Interaction struct {
+	Method        string                `json:"method"`
+	Params        json.RawMessage       `json:"params,omitempty"`
+	Result        json.RawMessage       `json:"result,omitempty"`
+	Error         *JSONRPCError         `json:"error,omitempty"`
+	Notifications []JSONRPCNotification `json:"notifications,omitempty"`
+}
+
+// Cassette is a recorded API session, stored as JSON so it can be checked
+// in as a test fixture
+type Cassette struct {
+	// Comment describes where the session was captured, e.g. the TrueNAS
+	// version
+	Comment      string        `json:"comment,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette reads a cassette written by Save
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette as indented JSON
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Recorder captures the calls a client makes, with secrets redacted. Set it
+// as Config.Recorder, run the session against a real TrueNAS, then Save the
+// cassette. A Recorder may be shared by several clients.
+type Recorder struct {
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns an empty Recorder. comment is stored in the cassette.
+func NewRecorder(comment string) *Recorder {
+	return &Recorder{cassette: Cassette{Comment: comment}}
+}
+
+// Cassette returns a copy of what has been recorded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cassette := Cassette{
+		Comment:      r.cassette.Comment,
+		Interactions: make([]Interaction, len(r.cassette.Interactions)),
+	}
+	copy(cassette.Interactions, r.cassette.Interactions)
+	return &cassette
+}
+
+// Save writes what has been recorded so far to path
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+func (r *Recorder) record(req *JSONRPCRequest, resp *JSONRPCResponse) {
+	interaction := Interaction{
+		Method: req.Method,
+		Params: redactParams(req.Method, req.Params),
+		Result: redactJSON(resp.Result),
+	}
+	if resp.Error != nil {
+		interaction.Error = &JSONRPCError{
+			Code:    resp.Error.Code,
+			Message: resp.Error.Message,
+			Data:    redactJSON(resp.Error.Data),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
+
+// recordNotification attaches a notification to the last completed call.
+// Notifications that arrive before any call has completed are dropped.
+func (r *Recorder) recordNotification(msg *JSONRPCNotification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(r.cassette.Interactions)
+	if n == 0 {
+		return
+	}
+	last := &r.cassette.Interactions[n-1]
+	last.Notifications = append(last.Notifications, JSONRPCNotification{
+		JSONRPC: msg.JSONRPC,
+		Method:  msg.Method,
+		Params:  redactJSON(msg.Params),
+	})
+}
+
+// Replayer is a Transport that answers calls from a cassette. Each call is
+// matched to the first unused interaction with the same method and params,
+// so the replay doesn't depend on the order concurrent calls were made in.
+// Notifications recorded with an interaction are delivered before its
+// response is returned. A Replayer can serve several clients in turn, e.g.
+// one per Terraform command, but notifications only go to the most recently
+// created.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	notify   func(*JSONRPCNotification)
+}
+
+// NewReplayer returns a Replayer serving cassette
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+func (r *Replayer) setNotificationHandler(handler func(*JSONRPCNotification)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notify = handler
+}
+
+// RoundTrip answers req from the cassette, or fails if nothing recorded
+// matches it
+func (r *Replayer) RoundTrip(ctx context.Context, req *JSONRPCRequest) (*JSONRPCResponse, error) {
+	params := redactParams(req.Method, req.Params)
+
+	r.mu.Lock()
+	var match *Interaction
+	for i := range r.cassette.Interactions {
+		interaction := &r.cassette.Interactions[i]
+		if r.used[i] || interaction.Method != req.Method || !jsonEqual(interaction.Params, params) {
+			continue
+		}
+		r.used[i] = true
+		match = interaction
+		break
+	}
+	notify := r.notify
+	r.mu.Unlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("replay: no recorded call to %s with params %s", req.Method, params)
+	}
+
+	if notify != nil {
+		for i := range match.Notifications {
+			notify(&match.Notifications[i])
+		}
+	}
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  match.Result,
+		Error:   match.Error,
+	}, nil
+}
+
+// Unused returns the recorded interactions that haven't been replayed, so
+// tests can check that a session made every call it was expected to
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// redactParams normalizes request params to JSON and removes secrets. The
+// auth.login* methods take credentials positionally, so every string
+// argument after the first is redacted there, and for
+// auth.login_with_api_key the key itself.
+func redactParams(method string, params interface{}) json.RawMessage {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil
+	}
+
+	var args []interface{}
+	if method == "auth.login_with_api_key" || method == "auth.login" {
+		if err := json.Unmarshal(data, &args); err == nil {
+			for i, arg := range args {
+				if _, ok := arg.(string); ok && (i > 0 || method == "auth.login_with_api_key") {
+					args[i] = redacted
+				}
+			}
+			data, _ = json.Marshal(args)
+		}
+	}
+	return redactJSON(data)
+}
+
+// redactJSON replaces the values of secret-looking keys anywhere in a JSON
+// document
+func redactJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if isSecretKey(k) && val != nil && val != "" {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactValue(val)
+		}
+		return v
+	}
+	return v
+}
+
+// secretKeys are the key fragments treated as secrets
+var secretKeys = []string{"password", "passphrase", "secret", "token", "privatekey", "private_key", "api_key", "apikey"}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "key" {
+		// api_key.create returns the new key as "key"
+		return true
+	}
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual reports whether two JSON documents hold the same value
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
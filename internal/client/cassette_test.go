@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+// cassetteSession makes the calls the record and replay tests compare
+func cassetteSession(t *testing.T, c *Client) (map[string]interface{}, map[string]interface{}) {
+	t.Helper()
+	ctx := context.Background()
+
+	pool, err := c.CreateWithJob(ctx, "pool", map[string]interface{}{
+		"name":     "tank",
+		"topology": map[string]interface{}{"data": []interface{}{map[string]interface{}{"type": "STRIPE", "disks": []string{"sdb"}}}},
+	}, 10*time.Second, nil)
+	if err != nil {
+		t.Fatalf("CreateWithJob() error = %v", err)
+	}
+
+	var user map[string]interface{}
+	err = c.Create(ctx, "user", map[string]interface{}{
+		"username":     "alice",
+		"password":     "correct-horse",
+		"group_create": true,
+	}, &user)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := c.GetInstance(ctx, "user", 99, nil); !IsNotFoundError(err) {
+		t.Fatalf("GetInstance() error = %v, want not found", err)
+	}
+	return pool, user
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+
+	recorder := NewRecorder("testserver")
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, Recorder: recorder})
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	recordedPool, recordedUser := cassetteSession(t, c)
+	_ = c.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{testserver.APIKey, "correct-horse"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains secret %q", secret)
+		}
+	}
+	srv.Close()
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette() error = %v", err)
+	}
+	if cassette.Comment != "testserver" {
+		t.Errorf("Comment = %q", cassette.Comment)
+	}
+
+	// The replaying client uses a different key; credentials are redacted
+	// on both sides so the login still matches
+	replayer := NewReplayer(cassette)
+	c = NewClient(&Config{Host: "replay", APIKey: "1-other", Timeout: 5 * time.Second, Transport: replayer})
+	defer c.Close()
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() on replay error = %v", err)
+	}
+	pool, user := cassetteSession(t, c)
+
+	if pool["path"] != recordedPool["path"] || user["uid"] != recordedUser["uid"] {
+		t.Errorf("replayed pool %v, user %v; recorded %v, %v", pool, user, recordedPool, recordedUser)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() = %v, want every interaction replayed", unused)
+	}
+}
+
+func TestReplayerNoMatch(t *testing.T) {
+	replayer := NewReplayer(&Cassette{Interactions: []Interaction{
+		{Method: "auth.login_ex", Params: json.RawMessage(`[{"api_key":"REDACTED","mechanism":"API_KEY_PLAIN"}]`), Result: json.RawMessage(`{"response_type":"SUCCESS"}`)},
+		{Method: "pool.query", Params: json.RawMessage(`[[["name","=","tank"]]]`), Result: json.RawMessage(`[]`)},
+	}})
+	c := NewClient(&Config{Host: "replay", APIKey: "1-key", Transport: replayer})
+	defer c.Close()
+	ctx := context.Background()
+
+	var pools []map[string]interface{}
+	if err := c.Query(ctx, "pool", NewQueryParams().WithFilter("name", "=", "tank"), &pools); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	// Each interaction is only replayed once
+	err := c.Query(ctx, "pool", NewQueryParams().WithFilter("name", "=", "tank"), &pools)
+	if err == nil || !strings.Contains(err.Error(), "no recorded call to pool.query") {
+		t.Errorf("second Query() error = %v, want no recorded call", err)
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		params interface{}
+		want   string
+	}{
+		{
+			name:   "login_ex api key",
+			method: "auth.login_ex",
+			params: []interface{}{map[string]interface{}{"mechanism": "API_KEY_PLAIN", "username": "root", "api_key": "1-abc"}},
+			want:   `[{"api_key":"REDACTED","mechanism":"API_KEY_PLAIN","username":"root"}]`,
+		},
+		{
+			name:   "login_ex otp",
+			method: "auth.login_ex",
+			params: []interface{}{map[string]interface{}{"mechanism": "OTP_TOKEN", "otp_token": "123456"}},
+			want:   `[{"mechanism":"OTP_TOKEN","otp_token":"REDACTED"}]`,
+		},
+		{
+			name:   "legacy api key login",
+			method: "auth.login_with_api_key",
+			params: []interface{}{"1-abc"},
+			want:   `["REDACTED"]`,
+		},
+		{
+			name:   "legacy password login",
+			method: "auth.login",
+			params: []interface{}{"root", "hunter2"},
+			want:   `["root","REDACTED"]`,
+		},
+		{
+			name:   "nested secrets",
+			method: "pool.create",
+			params: []interface{}{map[string]interface{}{"name": "tank", "encryption_options": map[string]interface{}{"passphrase": "pw", "algorithm": "AES-256-GCM"}}},
+			want:   `[{"encryption_options":{"algorithm":"AES-256-GCM","passphrase":"REDACTED"},"name":"tank"}]`,
+		},
+		{
+			name:   "certificate key",
+			method: "certificate.create",
+			params: []interface{}{map[string]interface{}{"name": "web", "certificate": "PEM", "privatekey": "KEY"}},
+			want:   `[{"certificate":"PEM","name":"web","privatekey":"REDACTED"}]`,
+		},
+		{
+			name:   "display and iscsi auth secrets",
+			method: "vm.device.create",
+			params: []interface{}{map[string]interface{}{"attributes": map[string]interface{}{"display_password": "pw", "secret": "s", "peersecret": "p"}}},
+			want:   `[{"attributes":{"display_password":"REDACTED","peersecret":"REDACTED","secret":"REDACTED"}}]`,
+		},
+		{
+			name:   "empty secrets kept",
+			method: "user.update",
+			params: []interface{}{1, map[string]interface{}{"password": ""}},
+			want:   `[1,{"password":""}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactParams(tt.method, tt.params)); got != tt.want {
+				t.Errorf("redactParams() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,9 @@ package client
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"sync"
@@ -13,10 +12,13 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	defaultTimeout     = 10 * time.Second
+	defaultJobTimeout  = 10 * time.Minute
 	defaultPingPeriod  = 30 * time.Second
 	defaultPongTimeout = 60 * time.Second
 	maxReconnectDelay  = 30 * time.Second
@@ -25,35 +27,164 @@ const (
 
 // Client represents a TrueNAS API client
 type Client struct {
-	host      string
+	// hosts are the endpoints of the API: one, or the controllers of an HA
+	// pair; see failover.go. activeHost indexes the one connected to.
+	hosts      []string
+	activeHost atomic.Int32
+
 	apiKey    string
+	username  string
+	password  string
+	otpToken  string
 	verifySSL bool
 	timeout   time.Duration
-
-	conn      *websocket.Conn
-	connMu    sync.Mutex
+	// jobTimeout is how long WaitForJob waits when not given a timeout
+	jobTimeout time.Duration
+
+	// TLS settings, see buildTLSConfig
+	caCertPEM      string
+	tlsServerName  string
+	tlsFingerprint string
+	clientCertPEM  string
+	clientKeyPEM   string
+
+	// Network path to TrueNAS, see dialer.go
+	proxyURL  string
+	sshTunnel *SSHTunnelConfig
+	tunnel    *sshDialer
+	tunnelMu  sync.Mutex
+
+	// Keepalive settings, see keepalive
+	pingPeriod  time.Duration
+	pongTimeout time.Duration
+
+	// transport replaces the WebSocket connection when set, and recorder
+	// captures every exchange; see cassette.go
+	transport Transport
+	recorder  *Recorder
+
+	// Wire logging to tflog at TRACE level and to a transcript, see
+	// wirelog.go
+	traceWire bool
+	wireLog   *WireLog
+
+	// TrueNAS release detected on connect, see version.go
+	version   Version
+	versionMu sync.RWMutex
+
+	// WebSocket connections, see pool.go. The first is the primary.
+	slots     []*connSlot
 	requestID int64
 
-	// Response channels keyed by request ID
-	responses   map[int64]chan *JSONRPCResponse
+	// connectMu serializes connection attempts so concurrent callers that
+	// find the connection down share a single reconnect
+	connectMu sync.Mutex
+
+	// Pending requests keyed by request ID
+	responses   map[int64]*pendingRequest
 	responsesMu sync.Mutex
 
+	// Job state pushed by the middleware's event stream
+	jobs *jobTracker
+
+	// Recent read results, nil unless enabled; see cache.go
+	cache *readCache
+
+	// Request rate and per-namespace concurrency limits, see limits.go. The
+	// rate limiter is nil unless enabled.
+	limiter     *rateLimiter
+	concurrency *concurrencyLimits
+
+	// OpenTelemetry spans of calls and jobs, see tracing.go
+	tracer trace.Tracer
+
 	// Context for managing goroutines
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 
-	// Connection state
+	// Connection state. A client is only marked connected once it has
+	// authenticated.
 	connected   bool
 	connectedMu sync.RWMutex
 }
 
-// Config holds configuration for the TrueNAS client
+// pendingRequest is a request waiting for its response. It remembers the
+// connection it was sent on so a dying connection only fails its own calls.
+type pendingRequest struct {
+	conn *websocket.Conn
+	ch   chan *JSONRPCResponse
+}
+
+// Config holds configuration for the TrueNAS client. Set either APIKey or
+// Username and Password; OTPToken is only used with a password.
 type Config struct {
-	Host      string
+	Host string
+	// Hosts, if set, replaces Host with endpoints that are tried in order,
+	// e.g. the virtual IP and both controllers of an HA pair. The client
+	// connects to the active controller and follows a failover.
+	Hosts []string
+
 	APIKey    string
+	Username  string
+	Password  string
+	OTPToken  string
 	VerifySSL bool
-	Timeout   time.Duration
+	// Timeout is how long to wait for the response to a request, unless the
+	// request's context has a later deadline. Zero means 10 seconds.
+	Timeout time.Duration
+	// JobTimeout is how long to wait for a job when the caller doesn't say.
+	// Zero means 10 minutes.
+	JobTimeout time.Duration
+
+	// CACertPEM holds PEM-encoded CA certificates to trust in addition to
+	// the system pool
+	CACertPEM string
+	// TLSServerName overrides the name used to verify the server certificate
+	TLSServerName string
+	// TLSFingerprint pins the server's leaf certificate by SHA-256
+	// fingerprint instead of verifying its chain
+	TLSFingerprint string
+	// ClientCertPEM and ClientKeyPEM enable mutual TLS
+	ClientCertPEM string
+	ClientKeyPEM  string
+
+	// ProxyURL routes the connection through an http, https or socks5
+	// proxy. If empty, HTTPS_PROXY and NO_PROXY from the environment apply.
+	ProxyURL string
+	// SSHTunnel routes the connection through an SSH jump host
+	SSHTunnel *SSHTunnelConfig
+
+	// Transport, if set, carries calls instead of a WebSocket connection to
+	// Host, e.g. a Replayer serving a recorded session
+	Transport Transport
+	// Recorder, if set, captures every call and notification so the session
+	// can be saved as a cassette
+	Recorder *Recorder
+	// WireLog, if set, receives a transcript of every call and notification.
+	// Close closes it.
+	WireLog *WireLog
+
+	// MaxConnections is the most WebSocket connections the client opens to
+	// spread concurrent calls over. Zero means one.
+	MaxConnections int
+
+	// RateLimit, if positive, is the most requests per second sent to the
+	// API, with bursts of up to RateBurst requests
+	RateLimit float64
+	RateBurst int
+	// Concurrency limits how many calls that change something may run at
+	// once per API namespace, see LimitConcurrency
+	Concurrency map[string]int
+
+	// CacheTTL, if positive, keeps the results of read-only calls for that
+	// long and serves GetInstance from one bulk query per resource. Calls
+	// that change anything invalidate the cached reads of their namespace.
+	CacheTTL time.Duration
+
+	// TracerProvider receives a span for every Call and WaitForJob. If nil,
+	// the global tracer provider is used.
+	TracerProvider trace.TracerProvider
 }
 
 // NewClient creates a new TrueNAS API client
@@ -62,51 +193,154 @@ func NewClient(cfg *Config) *Client {
 	if timeout == 0 {
 		timeout = defaultTimeout
 	}
+	jobTimeout := cfg.JobTimeout
+	if jobTimeout == 0 {
+		jobTimeout = defaultJobTimeout
+	}
+
+	hosts := cfg.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{cfg.Host}
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Client{
-		host:      cfg.Host,
-		apiKey:    cfg.APIKey,
-		verifySSL: cfg.VerifySSL,
-		timeout:   timeout,
-		responses: make(map[int64]chan *JSONRPCResponse),
-		ctx:       ctx,
-		cancel:    cancel,
+	c := &Client{
+		hosts:          hosts,
+		apiKey:         cfg.APIKey,
+		username:       cfg.Username,
+		password:       cfg.Password,
+		otpToken:       cfg.OTPToken,
+		caCertPEM:      cfg.CACertPEM,
+		tlsServerName:  cfg.TLSServerName,
+		tlsFingerprint: cfg.TLSFingerprint,
+		clientCertPEM:  cfg.ClientCertPEM,
+		clientKeyPEM:   cfg.ClientKeyPEM,
+		proxyURL:       cfg.ProxyURL,
+		sshTunnel:      cfg.SSHTunnel,
+		verifySSL:      cfg.VerifySSL,
+		timeout:        timeout,
+		jobTimeout:     jobTimeout,
+		pingPeriod:     defaultPingPeriod,
+		pongTimeout:    defaultPongTimeout,
+		transport:      cfg.Transport,
+		recorder:       cfg.Recorder,
+		traceWire:      traceEnabled(),
+		wireLog:        cfg.WireLog,
+		slots:          newConnSlots(cfg.MaxConnections),
+		responses:      make(map[int64]*pendingRequest),
+		jobs:           newJobTracker(),
+		concurrency:    newConcurrencyLimits(),
+		tracer:         newTracer(cfg.TracerProvider),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+	if cfg.RateLimit > 0 {
+		c.limiter = newRateLimiter(cfg.RateLimit, cfg.RateBurst)
 	}
+	for namespace, n := range cfg.Concurrency {
+		c.LimitConcurrency(namespace, n)
+	}
+	if cfg.CacheTTL > 0 {
+		c.cache = newReadCache(cfg.CacheTTL)
+	}
+	if n, ok := c.transport.(notifier); ok {
+		n.setNotificationHandler(c.handleNotification)
+	}
+	return c
 }
 
 // Connect establishes a WebSocket connection and authenticates
 func (c *Client) Connect(ctx context.Context) error {
-	c.connMu.Lock()
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
 
 	if c.isConnected() {
-		c.connMu.Unlock()
 		return nil
 	}
+	if c.ctx.Err() != nil {
+		return errClientClosed
+	}
+
+	// A custom transport has no connection to set up, only a session
+	if c.transport != nil {
+		if err := c.authenticate(ctx); err != nil {
+			return err
+		}
+		c.detectVersion(ctx)
+		c.setConnected(true)
+		return nil
+	}
+
+	if len(c.hosts) > 1 {
+		if err := c.connectActive(ctx); err != nil {
+			return err
+		}
+	} else if err := c.connectPrimary(ctx, c.hosts[0]); err != nil {
+		return err
+	}
+	c.detectVersion(withSlot(ctx, c.slots[0]))
 
+	c.setConnected(true)
+	return nil
+}
+
+// connectPrimary opens the primary connection to host and authenticates it
+func (c *Client) connectPrimary(ctx context.Context, host string) error {
+	conn, err := c.dial(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	primary := c.slots[0]
+	primary.mu.Lock()
+	if primary.conn != nil {
+		// Drop whatever is left of the previous connection
+		_ = primary.conn.Close()
+	}
+	primary.conn = conn
+	primary.mu.Unlock()
+	c.startConn(conn)
+
+	// Authenticate this connection with the configured credentials
+	if err := c.authenticate(withSlot(ctx, primary)); err != nil {
+		c.dropConnection(conn)
+		return err
+	}
+	return nil
+}
+
+// dial opens a WebSocket connection to the API on host, not yet
+// authenticated
+func (c *Client) dial(ctx context.Context, host string) (*websocket.Conn, error) {
 	// Build WebSocket URL
 	u := url.URL{
 		Scheme: "wss",
-		Host:   c.host,
+		Host:   host,
 		Path:   apiPath,
 	}
 
 	// Configure TLS
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: !c.verifySSL,
+	tlsConfig, err := c.buildTLSConfig()
+	if err != nil {
+		return nil, err
 	}
 
-	// Create a net.Dialer with explicit timeouts to ensure TCP connection attempts timeout
-	netDialer := &net.Dialer{
-		Timeout:   c.timeout,
-		KeepAlive: 30 * time.Second,
+	// Route the connection directly, through a proxy or through an SSH tunnel
+	netDialContext, err := c.netDialContext()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := c.proxyFunc()
+	if err != nil {
+		return nil, err
 	}
 
 	dialer := websocket.Dialer{
 		TLSClientConfig:  tlsConfig,
 		HandshakeTimeout: c.timeout,
-		NetDialContext:   netDialer.DialContext,
+		NetDialContext:   netDialContext,
+		Proxy:            proxy,
 	}
 
 	// Create a context with timeout for the connection attempt
@@ -116,31 +350,15 @@ func (c *Client) Connect(ctx context.Context) error {
 	// Connect
 	conn, _, err := dialer.DialContext(connectCtx, u.String(), http.Header{})
 	if err != nil {
-		return NewConnectionError(c.host, err)
-	}
-
-	// Set initial read deadline
-	_ = conn.SetReadDeadline(time.Now().Add(c.timeout))
-
-	c.conn = conn
-	c.setConnected(true)
-
-	// Start response reader
-	c.wg.Add(1)
-	go c.readResponses()
-
-	// Release the lock before calling authenticate, which calls Call(), which needs the lock
-	c.connMu.Unlock()
-
-	// Authenticate with API key
-	if err := c.authenticate(ctx); err != nil {
-		c.connMu.Lock()
-		_ = c.close()
-		c.connMu.Unlock()
-		return err
+		return nil, NewConnectionError(host, err)
 	}
 
-	return nil
+	// Any inbound traffic, including pongs, proves the connection is alive
+	_ = conn.SetReadDeadline(time.Now().Add(c.pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.pongTimeout))
+	})
+	return conn, nil
 }
 
 // loginExResponse represents the response from auth.login_ex
@@ -149,54 +367,219 @@ type loginExResponse struct {
 	Username     string `json:"username,omitempty"`
 }
 
-// authenticate performs API key authentication using auth.login_ex (TrueNAS 25.04+)
-// with fallback to auth.login_with_api_key for older versions
+// authenticate logs in with the configured credentials. It runs before the
+// client is marked connected, so it uses call rather than Call.
 func (c *Client) authenticate(ctx context.Context) error {
-	// Try modern auth.login_ex with API_KEY_PLAIN mechanism first
+	if c.apiKey == "" && c.username != "" {
+		return c.authenticatePassword(ctx)
+	}
+	return c.authenticateAPIKey(ctx)
+}
+
+// authenticateAPIKey performs API key authentication using auth.login_ex (TrueNAS 25.04+)
+// with fallback to auth.login_with_api_key for older versions. Once the
+// version is known, e.g. on reconnect, only the method it supports is tried.
+func (c *Client) authenticateAPIKey(ctx context.Context) error {
+	if c.Supports(CapabilityLoginEx) {
+		var loginExResp loginExResponse
+		err := c.call(ctx, "auth.login_ex", []interface{}{
+			map[string]interface{}{
+				"mechanism": "API_KEY_PLAIN",
+				"api_key":   c.apiKey,
+			},
+		}, &loginExResp)
+		if err == nil {
+			if loginExResp.ResponseType == "SUCCESS" {
+				return nil
+			}
+			return &AuthError{Reason: "login_ex returned " + loginExResp.ResponseType}
+		}
+		if !c.Version().IsZero() {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	// Fall back to legacy auth.login_with_api_key for older TrueNAS versions
+	var result bool
+	err := c.call(ctx, "auth.login_with_api_key", []interface{}{c.apiKey}, &result)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	if !result {
+		return &AuthError{Reason: "invalid API key"}
+	}
+	return nil
+}
+
+// authenticatePassword performs username/password authentication using
+// auth.login_ex with the PASSWORD_PLAIN mechanism, answering the OTP_REQUIRED
+// continuation when two-factor authentication is enabled. Older versions fall
+// back to auth.login.
+func (c *Client) authenticatePassword(ctx context.Context) error {
+	if !c.Supports(CapabilityLoginEx) {
+		return c.authenticateLegacyPassword(ctx)
+	}
+
 	var loginExResp loginExResponse
-	err := c.Call(ctx, "auth.login_ex", []interface{}{
+	err := c.call(ctx, "auth.login_ex", []interface{}{
 		map[string]interface{}{
-			"mechanism": "API_KEY_PLAIN",
-			"api_key":   c.apiKey,
+			"mechanism": "PASSWORD_PLAIN",
+			"username":  c.username,
+			"password":  c.password,
 		},
 	}, &loginExResp)
-	if err == nil {
-		if loginExResp.ResponseType == "SUCCESS" {
-			return nil
+	if err != nil {
+		if c.Version().IsZero() {
+			// Fall back to legacy auth.login for older TrueNAS versions
+			return c.authenticateLegacyPassword(ctx)
 		}
-		return fmt.Errorf("authentication failed: login_ex returned %s", loginExResp.ResponseType)
+		return fmt.Errorf("authentication failed: %w", err)
 	}
 
-	// Fall back to legacy auth.login_with_api_key for older TrueNAS versions
+	if loginExResp.ResponseType == "OTP_REQUIRED" {
+		if c.otpToken == "" {
+			return &AuthError{Reason: fmt.Sprintf("two-factor authentication is enabled for %q but no otp_token was provided", c.username)}
+		}
+		loginExResp = loginExResponse{}
+		err = c.call(ctx, "auth.login_ex", []interface{}{
+			map[string]interface{}{
+				"mechanism": "OTP_TOKEN",
+				"otp_token": c.otpToken,
+			},
+		}, &loginExResp)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if loginExResp.ResponseType != "SUCCESS" {
+		return &AuthError{Reason: "login_ex returned " + loginExResp.ResponseType}
+	}
+	return nil
+}
+
+// authenticateLegacyPassword logs in with auth.login, which takes the OTP
+// token as an optional third argument
+func (c *Client) authenticateLegacyPassword(ctx context.Context) error {
 	var result bool
-	err = c.Call(ctx, "auth.login_with_api_key", []interface{}{c.apiKey}, &result)
-	if err != nil {
+	args := []interface{}{c.username, c.password}
+	if c.otpToken != "" {
+		args = append(args, c.otpToken)
+	}
+	if err := c.call(ctx, "auth.login", args, &result); err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 	if !result {
-		return fmt.Errorf("authentication failed: invalid API key")
+		return &AuthError{Reason: "invalid username, password or OTP token"}
 	}
 	return nil
 }
 
-// Call makes a JSON-RPC call and waits for the response
-func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+// Call makes a JSON-RPC call and waits for the response. If the connection
+// is down it is re-established first. Read-only methods are retried
+// transparently when the connection drops mid-call; anything else returns a
+// *ConnectionLostError so the caller can decide whether to re-read. Calls
+// wait for the configured rate and concurrency limits, see limits.go.
+func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) (err error) {
+	ctx, span := c.startCallSpan(ctx, method)
+	defer func() { endSpan(span, err) }()
+
+	if c.cache == nil {
+		return c.callWithRetry(ctx, method, params, result)
+	}
+	if isCacheableRead(method) {
+		hit := true
+		defer func() { span.SetAttributes(attrCacheHit.Bool(hit)) }()
+		return c.cache.read(method, params, result, func(data *json.RawMessage) error {
+			hit = false
+			return c.callWithRetry(ctx, method, params, data)
+		})
+	}
+	err = c.callWithRetry(ctx, method, params, result)
+	c.cache.invalidate(method)
+	return err
+}
 
-	// Ensure we're connected
-	if !c.isConnected() {
-		if err := c.Connect(ctx); err != nil {
+// callWithRetry is Call without the read cache. It waits for the rate and
+// concurrency limits before sending the request.
+func (c *Client) callWithRetry(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if !isReadOnlyMethod(method) {
+		release, err := c.concurrency.acquire(ctx, method)
+		if err != nil {
 			return err
 		}
+		defer release()
 	}
 
+	for attempt := 0; ; attempt++ {
+		if !c.isConnected() {
+			if err := c.reconnect(ctx); err != nil {
+				return err
+			}
+		}
+		if err := c.limiter.wait(ctx); err != nil {
+			return err
+		}
+
+		err := c.call(ctx, method, params, result)
+
+		var lostErr *ConnectionLostError
+		if err == nil || !errors.As(err, &lostErr) || !isReadOnlyMethod(method) || attempt >= maxCallRetries {
+			trace.SpanFromContext(ctx).SetAttributes(attrRetries.Int(attempt))
+			return err
+		}
+	}
+}
+
+// call sends a single JSON-RPC request on the current connection and waits
+// for the response
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
 	// Generate request ID
 	id := atomic.AddInt64(&c.requestID, 1)
 
+	// Build request
+	req := NewRequest(id, method, params)
+	c.logRequest(ctx, req)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("rpc.jsonrpc.request_id", id))
+
+	start := time.Now()
+	var resp *JSONRPCResponse
+	var err error
+	if c.transport != nil {
+		resp, err = c.transport.RoundTrip(ctx, req)
+	} else {
+		resp, err = c.roundTrip(ctx, req)
+	}
+	c.logResponse(ctx, req, resp, err, time.Since(start))
+	if err != nil {
+		return err
+	}
+	if c.recorder != nil {
+		c.recorder.record(req, resp)
+	}
+
+	if resp.Error != nil {
+		if resp.Error.Code == errCodeConnectionLost {
+			return NewConnectionLostError(method, errors.New(resp.Error.Message))
+		}
+		return NewAPIError(resp.Error)
+	}
+	if result != nil && resp.Result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+	return nil
+}
+
+// roundTrip sends a request on the WebSocket connection and waits for the
+// response
+func (c *Client) roundTrip(ctx context.Context, req *JSONRPCRequest) (*JSONRPCResponse, error) {
+	id := req.ID
+	method := req.Method
+
 	// Create response channel
 	respChan := make(chan *JSONRPCResponse, 1)
-	c.responsesMu.Lock()
-	c.responses[id] = respChan
-	c.responsesMu.Unlock()
 
 	defer func() {
 		c.responsesMu.Lock()
@@ -204,79 +587,92 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}, re
 		c.responsesMu.Unlock()
 	}()
 
-	// Build request
-	req := NewRequest(id, method, params)
-
 	// Send request with write deadline
-	c.connMu.Lock()
-	if c.conn == nil {
-		c.connMu.Unlock()
-		// Connection was lost between isConnected() check and here; reconnect
-		c.setConnected(false)
-		if err := c.Connect(ctx); err != nil {
-			return err
+	slot, conn := c.acquire(ctx)
+	if conn == nil {
+		slot.mu.Unlock()
+		if slot == c.slots[0] {
+			c.setConnected(false)
 		}
-		return c.Call(ctx, method, params, result)
+		return nil, NewConnectionLostError(method, errors.New("not connected"))
 	}
-	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
-	err := c.conn.WriteJSON(req)
-	c.connMu.Unlock()
+	slot.inflight.Add(1)
+	defer slot.inflight.Add(-1)
+
+	c.responsesMu.Lock()
+	c.responses[id] = &pendingRequest{conn: conn, ch: respChan}
+	c.responsesMu.Unlock()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	err := conn.WriteJSON(req)
+	slot.mu.Unlock()
 
 	if err != nil {
-		// Write failed - connection is broken, mark disconnected
-		c.setConnected(false)
-		return fmt.Errorf("failed to send request: %w", err)
+		// Write failed - connection is broken, drop it so the reader exits
+		// and the next call reconnects
+		c.dropConnection(conn)
+		return nil, NewConnectionLostError(method, err)
 	}
 
 	// Wait for response with timeout
+	timeout := c.requestTimeout(ctx)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 	select {
 	case resp := <-respChan:
-		if resp.Error != nil {
-			return NewAPIError(resp.Error)
-		}
-		if result != nil && resp.Result != nil {
-			if err := json.Unmarshal(resp.Result, result); err != nil {
-				return fmt.Errorf("failed to unmarshal response: %w", err)
-			}
-		}
-		return nil
+		return resp, nil
 	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(c.timeout):
-		return fmt.Errorf("request timeout after %v", c.timeout)
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, &TimeoutError{Method: method, Timeout: timeout}
 	}
 }
 
-// readResponses reads responses from the WebSocket connection
-func (c *Client) readResponses() {
+// requestTimeout is how long to wait for a response: the configured timeout,
+// or until ctx's deadline if that is later, so a call the caller has given
+// more time, e.g. under a resource's timeouts block, isn't cut short
+func (c *Client) requestTimeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > c.timeout {
+			return remaining
+		}
+	}
+	return c.timeout
+}
+
+// readResponses reads responses from a WebSocket connection until it fails,
+// then closes done
+func (c *Client) readResponses(conn *websocket.Conn, done chan struct{}) {
 	defer func() {
 		// Recover from panics (e.g., gorilla/websocket panics on reads
 		// from a failed connection after middleware restarts)
 		_ = recover()
 
-		c.setConnected(false)
-
 		// Close the broken connection so Connect() can establish a new one
-		c.connMu.Lock()
-		if c.conn != nil {
-			_ = c.conn.Close()
-			c.conn = nil
-		}
-		c.connMu.Unlock()
+		c.dropConnection(conn)
 
-		// Notify all waiting callers that the connection was lost
+		// Notify callers waiting on this connection that it was lost
 		c.responsesMu.Lock()
-		for id, ch := range c.responses {
-			ch <- &JSONRPCResponse{
+		for id, p := range c.responses {
+			if p.conn != conn {
+				continue
+			}
+			select {
+			case p.ch <- &JSONRPCResponse{
 				ID: id,
 				Error: &JSONRPCError{
-					Code:    -1,
+					Code:    errCodeConnectionLost,
 					Message: "connection lost",
 				},
+			}:
+			default:
+				// Already answered
 			}
+			delete(c.responses, id)
 		}
 		c.responsesMu.Unlock()
 
+		close(done)
 		c.wg.Done()
 	}()
 
@@ -287,71 +683,120 @@ func (c *Client) readResponses() {
 		default:
 		}
 
-		c.connMu.Lock()
-		conn := c.conn
-		c.connMu.Unlock()
-
-		if conn == nil {
-			return
-		}
-
-		var resp JSONRPCResponse
-		if err := conn.ReadJSON(&resp); err != nil {
-			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-				return
-			}
-			// Check if it's a timeout - if so, check if we should continue
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				// Check if context is cancelled
-				select {
-				case <-c.ctx.Done():
-					return
-				default:
-					// Refresh deadline and continue
-					c.connMu.Lock()
-					if c.conn != nil {
-						_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
-					}
-					c.connMu.Unlock()
-					continue
-				}
-			}
-			// Other connection error - mark as disconnected and exit
+		var msg jsonrpcMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			// Close frames, network errors and read deadline expiry (no
+			// pong within pongTimeout) all mean the connection is gone.
+			// gorilla/websocket connections can't be read again after an
+			// error, so exit and let the next call reconnect.
 			return
 		}
 
 		// Successfully read a response - refresh deadline for next read
-		c.connMu.Lock()
-		if c.conn != nil {
-			_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+		_ = conn.SetReadDeadline(time.Now().Add(c.pongTimeout))
+
+		// Server-pushed events have no request ID and nobody waiting on them
+		if msg.isNotification() {
+			c.handleNotification(&JSONRPCNotification{JSONRPC: msg.JSONRPC, Method: msg.Method, Params: msg.Params})
+			continue
+		}
+		if msg.ID == nil {
+			continue
 		}
-		c.connMu.Unlock()
 
 		// Route response to waiting caller
 		c.responsesMu.Lock()
-		if ch, ok := c.responses[resp.ID]; ok {
-			ch <- &resp
+		if p, ok := c.responses[*msg.ID]; ok {
+			select {
+			case p.ch <- &JSONRPCResponse{
+				JSONRPC: msg.JSONRPC,
+				Result:  msg.Result,
+				Error:   msg.Error,
+				ID:      *msg.ID,
+			}:
+			default:
+			}
 		}
 		c.responsesMu.Unlock()
 	}
 }
 
-// Close closes the client connection
+// dropConnection closes conn and takes it out of its slot. Losing the
+// primary connection marks the client disconnected.
+func (c *Client) dropConnection(conn *websocket.Conn) {
+	_ = conn.Close()
+	for i, slot := range c.slots {
+		slot.mu.Lock()
+		if slot.conn != conn {
+			slot.mu.Unlock()
+			continue
+		}
+		slot.conn = nil
+		slot.up.Store(false)
+		slot.mu.Unlock()
+
+		if i == 0 {
+			c.setConnected(false)
+			// Subscriptions belong to the connection and must be renewed
+			c.jobs.reset()
+		}
+		return
+	}
+}
+
+// handleNotification dispatches a server-pushed notification. It runs on the
+// reader goroutine, so handlers must not block or issue calls.
+func (c *Client) handleNotification(msg *JSONRPCNotification) {
+	if c.recorder != nil {
+		c.recorder.recordNotification(msg)
+	}
+	c.logNotification(msg)
+	if msg.Method != "collection_update" {
+		return
+	}
+
+	var update CollectionUpdate
+	if err := json.Unmarshal(msg.Params, &update); err != nil {
+		return
+	}
+
+	switch update.Collection {
+	case jobEventName:
+		c.handleJobEvent(&update)
+	}
+}
+
+// Close closes the client connections and the wire log
 func (c *Client) Close() error {
 	c.cancel()
-	c.connMu.Lock()
-	defer c.connMu.Unlock()
-	return c.close()
+	defer c.closeTunnel()
+	err := c.close()
+	if c.wireLog != nil {
+		if closeErr := c.wireLog.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
+// close closes every connection and returns the error closing the primary
 func (c *Client) close() error {
 	c.setConnected(false)
-	if c.conn != nil {
-		err := c.conn.Close()
-		c.conn = nil
-		return err
+	c.jobs.reset()
+
+	var err error
+	for i, slot := range c.slots {
+		slot.mu.Lock()
+		if slot.conn != nil {
+			if closeErr := slot.conn.Close(); i == 0 {
+				err = closeErr
+			}
+			slot.conn = nil
+		}
+		slot.up.Store(false)
+		slot.mu.Unlock()
 	}
-	return nil
+	return err
 }
 
 func (c *Client) isConnected() bool {
@@ -369,40 +814,18 @@ func (c *Client) setConnected(connected bool) {
 // Query performs a query operation with optional filtering
 func (c *Client) Query(ctx context.Context, resource string, params *QueryParams, result interface{}) error {
 	method := resource + ".query"
-	var args []interface{}
-	if params != nil {
-		// Convert filters to the format expected by TrueNAS
-		queryOptions := map[string]interface{}{}
-		if params.Limit > 0 {
-			queryOptions["limit"] = params.Limit
-		}
-		if params.Offset > 0 {
-			queryOptions["offset"] = params.Offset
-		}
-		if params.Count {
-			queryOptions["count"] = params.Count
-		}
-		if len(params.OrderBy) > 0 {
-			queryOptions["order_by"] = params.OrderBy
-		}
-		if len(params.Select) > 0 {
-			queryOptions["select"] = params.Select
-		}
-
-		if len(params.Filters) > 0 {
-			args = append(args, params.Filters)
-		} else {
-			args = append(args, []interface{}{})
-		}
-		if len(queryOptions) > 0 {
-			args = append(args, queryOptions)
-		}
-	}
-	return c.Call(ctx, method, args, result)
+	return c.Call(ctx, method, params.queryArgs(), result)
 }
 
-// GetInstance retrieves a single instance by ID
+// GetInstance retrieves a single instance by ID. With the read cache
+// enabled, the instance is looked up in one query of every record of the
+// resource, shared by all GetInstance calls until it expires.
 func (c *Client) GetInstance(ctx context.Context, resource string, id interface{}, result interface{}) error {
+	if c.cache != nil {
+		if found, err := c.cachedInstance(ctx, resource, id, result); found {
+			return err
+		}
+	}
 	method := resource + ".get_instance"
 	return c.Call(ctx, method, []interface{}{id}, result)
 }
@@ -431,60 +854,123 @@ func (c *Client) DeleteWithOptions(ctx context.Context, resource string, id inte
 	return c.Call(ctx, method, []interface{}{id, options}, nil)
 }
 
-// WaitForJob waits for a TrueNAS job to complete and returns the result
-func (c *Client) WaitForJob(ctx context.Context, jobID int64, timeout time.Duration) (map[string]interface{}, error) {
-	deadline := time.Now().Add(timeout)
-	pollInterval := 2 * time.Second
+// WaitForJob waits for a TrueNAS job to complete and returns the result.
+// Job state changes are pushed over the core.get_jobs event stream; polling
+// is only used as a fallback when events are unavailable or missed.
+// onProgress, if non-nil, is called whenever the job reports new progress.
+// If ctx ends or the timeout expires first, the job is aborted on the server
+// and a *JobCancelledError is returned; it wraps a *TimeoutError if the
+// timeout or ctx's deadline passed. A timeout of zero or less means the
+// client's JobTimeout.
+func (c *Client) WaitForJob(ctx context.Context, jobID int64, timeout time.Duration, onProgress JobProgressFunc) (map[string]interface{}, error) {
+	return c.waitForJob(ctx, jobID, timeout, onProgress, true)
+}
 
-	for {
-		if time.Now().After(deadline) {
-			return nil, fmt.Errorf("timeout waiting for job %d to complete", jobID)
+// FollowJob waits for a job like WaitForJob, but leaves it running if ctx
+// ends or the timeout expires first, for jobs such as scrubs that are meant
+// to outlive the wait. It then returns a *TimeoutError, or ctx's error if
+// ctx was cancelled.
+func (c *Client) FollowJob(ctx context.Context, jobID int64, timeout time.Duration, onProgress JobProgressFunc) (map[string]interface{}, error) {
+	return c.waitForJob(ctx, jobID, timeout, onProgress, false)
+}
+
+// waitForJob implements WaitForJob and FollowJob; abort says whether to
+// abort the job when giving up on it
+func (c *Client) waitForJob(ctx context.Context, jobID int64, timeout time.Duration, onProgress JobProgressFunc, abort bool) (result map[string]interface{}, err error) {
+	ctx, span := c.startJobSpan(ctx, jobID)
+	defer func() { endSpan(span, err) }()
+
+	if timeout <= 0 {
+		timeout = c.jobTimeout
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	// ctxErr describes why ctx ended. Its deadline, e.g. from a resource's
+	// timeouts block, may be much shorter than timeout, so the error says
+	// which of the two passed and how long the wait ran.
+	start := time.Now()
+	ctxErr := func() error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &TimeoutError{JobID: jobID, Timeout: time.Since(start).Round(time.Millisecond), Deadline: true}
 		}
+		return ctx.Err()
+	}
 
-		// The API returns an array, get the first element
-		var jobs []map[string]interface{}
-		err := c.Call(ctx, "core.get_jobs", []interface{}{
-			[][]interface{}{{"id", "=", jobID}},
-		}, &jobs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to query job status: %w", err)
+	giveUp := func(cause error) error {
+		if !abort {
+			return cause
 		}
+		return c.abortJob(jobID, cause)
+	}
 
-		if len(jobs) == 0 {
-			return nil, fmt.Errorf("job %d not found", jobID)
+	var lastProgress JobProgress
+	report := func(job map[string]interface{}) {
+		if onProgress == nil {
+			return
 		}
+		if p, ok := jobProgress(jobID, job); ok && p != lastProgress {
+			lastProgress = p
+			onProgress(p)
+		}
+	}
 
-		job := jobs[0]
-		state, _ := job["state"].(string)
+	// Register before querying so an event arriving in between is not lost
+	notify := c.jobs.watch(jobID)
+	defer c.jobs.unwatch(jobID)
 
-		switch state {
-		case "SUCCESS":
-			if result, ok := job["result"].(map[string]interface{}); ok {
-				return result, nil
-			}
-			// Some jobs return simple values or nil
-			return job, nil
-		case "FAILED":
-			errMsg := "job failed"
-			if e, ok := job["error"].(string); ok {
-				errMsg = e
+	for {
+		subscribed := c.subscribeJobs(ctx)
+
+		job, err := c.getJob(ctx, jobID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, giveUp(ctxErr())
 			}
-			return nil, fmt.Errorf("job %d failed: %s", jobID, errMsg)
-		case "ABORTED":
-			return nil, fmt.Errorf("job %d was aborted", jobID)
-		default:
-			// Job still running, wait and poll again
+			return nil, err
+		}
+		if done, result, err := jobOutcome(jobID, job); done {
+			c.cache.invalidateJob(job)
+			span.SetAttributes(jobAttributes(job)...)
+			return result, err
+		}
+		report(job)
+
+		pollInterval := jobPollInterval
+		if subscribed {
+			pollInterval = jobEventFallbackInterval
+		}
+		poll := time.NewTimer(pollInterval)
+
+	wait:
+		for {
 			select {
+			case <-notify:
+				if job := c.jobs.latest(jobID); job != nil {
+					if done, result, err := jobOutcome(jobID, job); done {
+						poll.Stop()
+						c.cache.invalidateJob(job)
+						span.SetAttributes(jobAttributes(job)...)
+						return result, err
+					}
+					report(job)
+				}
+			case <-poll.C:
+				break wait
+			case <-deadline.C:
+				poll.Stop()
+				return nil, giveUp(&TimeoutError{JobID: jobID, Timeout: timeout})
 			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(pollInterval):
+				poll.Stop()
+				return nil, giveUp(ctxErr())
 			}
 		}
 	}
 }
 
 // CreateWithJob creates a resource and waits for the job to complete
-func (c *Client) CreateWithJob(ctx context.Context, resource string, data interface{}, timeout time.Duration) (map[string]interface{}, error) {
+func (c *Client) CreateWithJob(ctx context.Context, resource string, data interface{}, timeout time.Duration, onProgress JobProgressFunc) (map[string]interface{}, error) {
 	method := resource + ".create"
 
 	var jobID float64
@@ -493,11 +979,11 @@ func (c *Client) CreateWithJob(ctx context.Context, resource string, data interf
 		return nil, err
 	}
 
-	return c.WaitForJob(ctx, int64(jobID), timeout)
+	return c.WaitForJob(ctx, int64(jobID), timeout, onProgress)
 }
 
 // UpdateWithJob updates a resource and waits for the job to complete
-func (c *Client) UpdateWithJob(ctx context.Context, resource string, id interface{}, data interface{}, timeout time.Duration) (map[string]interface{}, error) {
+func (c *Client) UpdateWithJob(ctx context.Context, resource string, id interface{}, data interface{}, timeout time.Duration, onProgress JobProgressFunc) (map[string]interface{}, error) {
 	method := resource + ".update"
 
 	var jobID float64
@@ -506,5 +992,11 @@ func (c *Client) UpdateWithJob(ctx context.Context, resource string, id interfac
 		return nil, err
 	}
 
-	return c.WaitForJob(ctx, int64(jobID), timeout)
+	return c.WaitForJob(ctx, int64(jobID), timeout, onProgress)
+}
+
+// JobTimeout returns how long WaitForJob waits for a job when not given a
+// timeout
+func (c *Client) JobTimeout() time.Duration {
+	return c.jobTimeout
 }
@@ -1,8 +1,10 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestNewRequest(t *testing.T) {
@@ -168,8 +170,8 @@ func TestNewClientConfig(t *testing.T) {
 	if client == nil {
 		t.Fatal("NewClient returned nil")
 	}
-	if client.host != cfg.Host {
-		t.Errorf("client.host = %v, want %v", client.host, cfg.Host)
+	if client.host() != cfg.Host {
+		t.Errorf("client.host() = %v, want %v", client.host(), cfg.Host)
 	}
 	if client.apiKey != cfg.APIKey {
 		t.Errorf("client.apiKey = %v, want %v", client.apiKey, cfg.APIKey)
@@ -180,4 +182,25 @@ func TestNewClientConfig(t *testing.T) {
 	if client.responses == nil {
 		t.Error("client.responses map is nil")
 	}
+	if client.timeout != defaultTimeout || client.JobTimeout() != defaultJobTimeout {
+		t.Errorf("timeouts = %v and %v, want the defaults", client.timeout, client.JobTimeout())
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	c := NewClient(&Config{Host: "truenas.local", Timeout: 10 * time.Second})
+
+	if got := c.requestTimeout(context.Background()); got != 10*time.Second {
+		t.Errorf("requestTimeout() without a deadline = %v, want 10s", got)
+	}
+	short, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if got := c.requestTimeout(short); got != 10*time.Second {
+		t.Errorf("requestTimeout() with an earlier deadline = %v, want 10s", got)
+	}
+	long, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if got := c.requestTimeout(long); got < 59*time.Minute {
+		t.Errorf("requestTimeout() with a later deadline = %v, want about an hour", got)
+	}
 }
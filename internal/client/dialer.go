@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHTunnelConfig describes a jump host the WebSocket connection is tunnelled
+// through
+type SSHTunnelConfig struct {
+	// Host is the bastion address as host or host:port (default port 22)
+	Host string
+	User string
+	// PrivateKey is the PEM-encoded private key used to authenticate
+	PrivateKey string
+	// HostKey is the bastion's public key in authorized_keys format. If
+	// empty, the key is checked against ~/.ssh/known_hosts.
+	HostKey string
+}
+
+// proxyFunc returns the proxy selection for the WebSocket dialer: the
+// configured proxy URL, or HTTPS_PROXY/NO_PROXY from the environment.
+// gorilla/websocket supports http, https and socks5 proxy URLs.
+func (c *Client) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if c.sshTunnel != nil {
+		// The tunnel is the route to TrueNAS; don't send it via a proxy too
+		return nil, nil
+	}
+	if c.proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(c.proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", c.proxyURL, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", c.proxyURL)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// netDialContext returns the function used to open the TCP connection to
+// TrueNAS: through the SSH tunnel if one is configured, otherwise directly.
+func (c *Client) netDialContext() (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	if c.sshTunnel == nil {
+		netDialer := &net.Dialer{
+			Timeout:   c.timeout,
+			KeepAlive: 30 * time.Second,
+		}
+		return netDialer.DialContext, nil
+	}
+
+	c.tunnelMu.Lock()
+	defer c.tunnelMu.Unlock()
+
+	if c.tunnel == nil {
+		tunnel, err := newSSHDialer(c.sshTunnel, c.timeout)
+		if err != nil {
+			return nil, err
+		}
+		c.tunnel = tunnel
+	}
+	return c.tunnel.DialContext, nil
+}
+
+// closeTunnel shuts down the SSH tunnel, if any
+func (c *Client) closeTunnel() {
+	c.tunnelMu.Lock()
+	defer c.tunnelMu.Unlock()
+
+	if c.tunnel != nil {
+		c.tunnel.Close()
+		c.tunnel = nil
+	}
+}
+
+// sshDialer opens connections through an SSH jump host. The SSH session is
+// established lazily and re-established if it dies, so it survives
+// WebSocket reconnects.
+type sshDialer struct {
+	addr    string
+	config  *ssh.ClientConfig
+	timeout time.Duration
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+func newSSHDialer(cfg *SSHTunnelConfig, timeout time.Duration) (*sshDialer, error) {
+	if cfg.Host == "" || cfg.User == "" || cfg.PrivateKey == "" {
+		return nil, errors.New("ssh_tunnel requires host, user and private_key")
+	}
+
+	signer, err := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh_tunnel private_key: %w", err)
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(cfg.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := cfg.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	return &sshDialer{
+		addr: addr,
+		config: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         timeout,
+		},
+		timeout: timeout,
+	}, nil
+}
+
+// sshHostKeyCallback verifies the bastion against a pinned key, falling back
+// to the user's known_hosts file
+func sshHostKeyCallback(hostKey string) (ssh.HostKeyCallback, error) {
+	if hostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ssh_tunnel host_key: %w", err)
+		}
+		return ssh.FixedHostKey(key), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("ssh_tunnel host_key is not set and the home directory is unknown: %w", err)
+	}
+	callback, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("ssh_tunnel host_key is not set and ~/.ssh/known_hosts could not be loaded: %w", err)
+	}
+	return callback, nil
+}
+
+// DialContext opens a connection to addr from the jump host
+func (d *sshDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, addr)
+	if err != nil {
+		// The SSH session may have died since it was last used; retry once
+		// on a fresh one
+		d.reset(client)
+		client, err = d.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = client.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return withDeadlines(conn), nil
+}
+
+// withDeadlines bridges an SSH channel through an in-memory pipe. SSH
+// channels don't support deadlines, which the WebSocket handshake and the
+// keepalive pong timeout rely on; the pipe end does.
+func withDeadlines(conn net.Conn) net.Conn {
+	local, remote := net.Pipe()
+	go func() {
+		_, _ = io.Copy(conn, remote)
+		_ = conn.Close()
+	}()
+	go func() {
+		_, _ = io.Copy(remote, conn)
+		_ = remote.Close()
+	}()
+	return local
+}
+
+// connect returns the current SSH session, establishing one if needed
+func (d *sshDialer) connect(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	netDialer := &net.Dialer{Timeout: d.timeout, KeepAlive: 30 * time.Second}
+	conn, err := netDialer.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return nil, fmt.Errorf("ssh_tunnel: failed to reach %s: %w", d.addr, err)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(d.timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, d.addr, d.config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("ssh_tunnel: failed to connect to %s: %w", d.addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	d.client = ssh.NewClient(sshConn, chans, reqs)
+	return d.client, nil
+}
+
+// reset drops a broken SSH session so the next dial reconnects
+func (d *sshDialer) reset(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client == client {
+		_ = d.client.Close()
+		d.client = nil
+	}
+}
+
+// Close closes the SSH session
+func (d *sshDialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil {
+		_ = d.client.Close()
+		d.client = nil
+	}
+}
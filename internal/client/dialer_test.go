@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestProxyFuncValidation(t *testing.T) {
+	tests := []struct {
+		proxyURL string
+		wantErr  bool
+	}{
+		{proxyURL: ""},
+		{proxyURL: "http://proxy.local:3128"},
+		{proxyURL: "socks5://bastion:1080"},
+		{proxyURL: "ftp://proxy.local", wantErr: true},
+		{proxyURL: "://bad", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		c := NewClient(&Config{Host: "truenas.local", ProxyURL: tt.proxyURL})
+		_, err := c.proxyFunc()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("proxyFunc(%q) error = %v, wantErr %v", tt.proxyURL, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConnectThroughHTTPProxy(t *testing.T) {
+	ts := newTestServer(t)
+
+	var tunnels int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		atomic.AddInt32(&tunnels, 1)
+		w.WriteHeader(http.StatusOK)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		pipe(conn, upstream)
+	}))
+	t.Cleanup(proxy.Close)
+
+	c := NewClient(&Config{Host: ts.host(), APIKey: "test-key", ProxyURL: proxy.URL})
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() through proxy error = %v", err)
+	}
+	if got := atomic.LoadInt32(&tunnels); got != 1 {
+		t.Errorf("proxy tunnels = %d, want 1", got)
+	}
+}
+
+func TestConnectThroughSSHTunnel(t *testing.T) {
+	ts := newTestServer(t)
+	bastion, hostKey := startTestSSHServer(t)
+	clientKey, _ := generateTestSSHKey(t)
+
+	c := NewClient(&Config{
+		Host:   ts.host(),
+		APIKey: "test-key",
+		SSHTunnel: &SSHTunnelConfig{
+			Host:       bastion,
+			User:       "jump",
+			PrivateKey: clientKey,
+			HostKey:    hostKey,
+		},
+	})
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() through SSH tunnel error = %v", err)
+	}
+
+	// A wrong host key must be rejected
+	_, otherHostKey := generateTestSSHKey(t)
+	c2 := NewClient(&Config{
+		Host:   ts.host(),
+		APIKey: "test-key",
+		SSHTunnel: &SSHTunnelConfig{
+			Host:       bastion,
+			User:       "jump",
+			PrivateKey: clientKey,
+			HostKey:    otherHostKey,
+		},
+	})
+	t.Cleanup(func() { _ = c2.Close() })
+
+	if err := c2.Connect(context.Background()); err == nil {
+		t.Error("Connect() with mismatched SSH host key expected error")
+	}
+}
+
+// generateTestSSHKey returns a PEM private key and its authorized_keys line
+func generateTestSSHKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	pub, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to convert public key: %v", err)
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	return string(privPEM), string(ssh.MarshalAuthorizedKey(pub))
+}
+
+// startTestSSHServer runs an SSH server that accepts any public key and
+// forwards direct-tcpip channels. It returns its address and host key.
+func startTestSSHServer(t *testing.T) (string, string) {
+	t.Helper()
+
+	hostPEM, hostAuthorized := generateTestSSHKey(t)
+	hostSigner, err := ssh.ParsePrivateKey([]byte(hostPEM))
+	if err != nil {
+		t.Fatalf("failed to parse host key: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(ssh.ConnMetadata, ssh.PublicKey) (*ssh.Permissions, error) {
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for newChan := range chans {
+					if newChan.ChannelType() != "direct-tcpip" {
+						_ = newChan.Reject(ssh.UnknownChannelType, "unsupported")
+						continue
+					}
+					// RFC 4254 7.2: string host, uint32 port, string origin, uint32 origin port
+					payload := newChan.ExtraData()
+					hostLen := binary.BigEndian.Uint32(payload)
+					host := string(payload[4 : 4+hostLen])
+					port := binary.BigEndian.Uint32(payload[4+hostLen:])
+
+					upstream, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+					if err != nil {
+						_ = newChan.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					channel, chReqs, err := newChan.Accept()
+					if err != nil {
+						_ = upstream.Close()
+						continue
+					}
+					go ssh.DiscardRequests(chReqs)
+					go pipe(channel, upstream)
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), hostAuthorized
+}
+
+// pipe copies between two connections until either side closes
+func pipe(a, b io.ReadWriteCloser) {
+	go func() {
+		_, _ = io.Copy(a, b)
+		_ = a.Close()
+	}()
+	_, _ = io.Copy(b, a)
+	_ = b.Close()
+}
@@ -1,8 +1,12 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Error codes from TrueNAS API
@@ -18,6 +22,25 @@ const (
 	ErrCodeNotAuthorized    = 2
 	ErrCodeNotFound         = 3
 	ErrCodeValidation       = 4
+
+	// errCodeConnectionLost is used internally to fail pending calls when
+	// the connection drops before a response arrives
+	errCodeConnectionLost = -1
+)
+
+// errClientClosed is returned when a call is made after Close
+var errClientClosed = errors.New("client is closed")
+
+// Error kinds, matched with errors.Is however deeply the error has been
+// wrapped. Use errors.As with the concrete error types for details.
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrValidation     = errors.New("validation failed")
+	ErrAuth           = errors.New("authentication failed")
+	ErrTimeout        = errors.New("timed out")
+	ErrConnectionLost = errors.New("connection lost")
+	ErrJobFailed      = errors.New("job failed")
+	ErrMethodNotFound = errors.New("method not found")
 )
 
 // APIError represents an error from the TrueNAS API
@@ -25,18 +48,64 @@ type APIError struct {
 	Code    int
 	Message string
 	Details string
+	// ErrName is the errno name TrueNAS reports for the failure, e.g.
+	// "ENOENT"
+	ErrName string
+	// ValidationErrors lists the fields TrueNAS rejected, if the call failed
+	// validation
+	ValidationErrors []ValidationFailure
+}
+
+// ValidationFailure is a single field rejected by TrueNAS validation
+type ValidationFailure struct {
+	// Attribute is the API field name, prefixed with the method's schema
+	// name (e.g. "sharing_smb_create.path")
+	Attribute string
+	Message   string
+	Errno     int
+}
+
+// Field returns the attribute name without its schema prefix
+func (f ValidationFailure) Field() string {
+	if _, field, ok := strings.Cut(f.Attribute, "."); ok {
+		return field
+	}
+	return f.Attribute
 }
 
 func (e *APIError) Error() string {
+	if len(e.ValidationErrors) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "TrueNAS API error %d: %s", e.Code, e.Message)
+		for _, f := range e.ValidationErrors {
+			fmt.Fprintf(&b, "\n  %s: %s", f.Attribute, f.Message)
+		}
+		return b.String()
+	}
 	if e.Details != "" {
 		return fmt.Sprintf("TrueNAS API error %d: %s (%s)", e.Code, e.Message, e.Details)
 	}
 	return fmt.Sprintf("TrueNAS API error %d: %s", e.Code, e.Message)
 }
 
+// Is reports whether the error is of the given kind
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.IsNotFound()
+	case ErrAuth:
+		return e.IsAuthError()
+	case ErrValidation:
+		return e.IsValidationError()
+	case ErrMethodNotFound:
+		return e.Code == ErrCodeMethodNotFound
+	}
+	return false
+}
+
 // IsNotFound returns true if the error indicates a resource was not found
 func (e *APIError) IsNotFound() bool {
-	if e.Code == ErrCodeNotFound {
+	if e.Code == ErrCodeNotFound || e.ErrName == "ENOENT" {
 		return true
 	}
 	// TrueNAS Scale 25 returns InvalidParams with InstanceNotFound in details
@@ -55,7 +124,49 @@ func (e *APIError) IsAuthError() bool {
 
 // IsValidationError returns true if the error is a validation error
 func (e *APIError) IsValidationError() bool {
-	return e.Code == ErrCodeValidation
+	return e.Code == ErrCodeValidation || len(e.ValidationErrors) > 0
+}
+
+// AuthError is returned when TrueNAS rejects the configured credentials
+type AuthError struct {
+	Reason string
+}
+
+func (e *AuthError) Error() string {
+	return "authentication failed: " + e.Reason
+}
+
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuth
+}
+
+// TimeoutError is returned when TrueNAS doesn't answer a request, or a job
+// doesn't finish, within the allotted time
+type TimeoutError struct {
+	// Method is the request that timed out; empty for a job
+	Method string
+	// JobID is the job that didn't finish in time; zero for a request
+	JobID   int64
+	Timeout time.Duration
+	// Deadline reports that the caller's deadline passed rather than the
+	// job timeout; Timeout is then how long the job was waited for
+	Deadline bool
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Deadline {
+		return fmt.Sprintf("timeout waiting for job %d to complete: the operation's deadline passed after waiting %v", e.JobID, e.Timeout)
+	}
+	if e.Method == "" {
+		return fmt.Sprintf("timeout waiting for job %d to complete after %v", e.JobID, e.Timeout)
+	}
+	return fmt.Sprintf("request timeout after %v waiting for %s", e.Timeout, e.Method)
+}
+
+// Is matches ErrTimeout, and context.DeadlineExceeded so callers can treat
+// both kinds of deadline alike
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == context.DeadlineExceeded
 }
 
 // ConnectionError represents a connection-related error
@@ -91,17 +202,196 @@ func (e *ConnectionError) Unwrap() error {
 	return e.Err
 }
 
+// HostsError is returned when none of several hosts could be reached, or
+// none of the reachable ones is the active controller. It lists why each
+// one was passed over.
+type HostsError struct {
+	Hosts []string
+	Errs  []error
+}
+
+func (e *HostsError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "no active TrueNAS controller among %d hosts:", len(e.Hosts))
+	for i, host := range e.Hosts {
+		err := e.Errs[i]
+		// Skip the configuration advice of each ConnectionError
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			err = connErr.Err
+		}
+		fmt.Fprintf(&b, "\n  %s: %v", host, err)
+	}
+	return b.String()
+}
+
+func (e *HostsError) Unwrap() []error {
+	return e.Errs
+}
+
+// ConnectionLostError is returned when the connection drops while a call is
+// in flight. For mutating methods the request may or may not have been
+// applied on the server, so callers should re-read before retrying.
+type ConnectionLostError struct {
+	Method string
+	Err    error
+}
+
+func (e *ConnectionLostError) Error() string {
+	return fmt.Sprintf("connection to TrueNAS lost during %s: %v", e.Method, e.Err)
+}
+
+func (e *ConnectionLostError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConnectionLostError) Is(target error) bool {
+	return target == ErrConnectionLost
+}
+
+// NewConnectionLostError creates a new ConnectionLostError
+func NewConnectionLostError(method string, err error) *ConnectionLostError {
+	return &ConnectionLostError{
+		Method: method,
+		Err:    err,
+	}
+}
+
+// JobError represents a TrueNAS job that failed or was aborted
+type JobError struct {
+	JobID   int64
+	State   string
+	Message string
+	Logs    string
+}
+
+func (e *JobError) Error() string {
+	var msg string
+	if e.State == "ABORTED" {
+		msg = fmt.Sprintf("job %d was aborted", e.JobID)
+	} else {
+		msg = fmt.Sprintf("job %d failed: %s", e.JobID, e.Message)
+	}
+	if e.Logs != "" {
+		msg += "\n\nJob logs:\n" + strings.TrimSpace(e.Logs)
+	}
+	return msg
+}
+
+func (e *JobError) Is(target error) bool {
+	return target == ErrJobFailed
+}
+
+// newJobError creates a JobError from a finished core.get_jobs entry
+func newJobError(jobID int64, state string, job map[string]interface{}) *JobError {
+	jobErr := &JobError{
+		JobID:   jobID,
+		State:   state,
+		Message: "job failed",
+	}
+	if e, ok := job["error"].(string); ok && e != "" {
+		jobErr.Message = e
+	}
+	if logs, ok := job["logs_excerpt"].(string); ok {
+		jobErr.Logs = logs
+	}
+	return jobErr
+}
+
+// JobCancelledError is returned when waiting for a job stops early because
+// the caller was cancelled or the wait timed out. The client tries to abort
+// the server-side job so it does not keep running behind Terraform's back,
+// then reads it back: Aborted is only set if the job ended ABORTED, and
+// State says how it ended otherwise.
+type JobCancelledError struct {
+	JobID    int64
+	Err      error
+	Aborted  bool
+	AbortErr error
+
+	// State is the job's state read back after the abort, e.g. SUCCESS if
+	// it finished before the abort reached it. It is empty if the job
+	// couldn't be read.
+	State string
+
+	// JobErr is the job's failure if it ended FAILED
+	JobErr *JobError
+}
+
+func (e *JobCancelledError) Error() string {
+	switch {
+	case e.AbortErr != nil:
+		return fmt.Sprintf("%v; aborting job %d on TrueNAS failed, it may still be running: %v", e.Err, e.JobID, e.AbortErr)
+	case e.Aborted:
+		return fmt.Sprintf("%v; job %d was aborted on TrueNAS", e.Err, e.JobID)
+	case e.State == "SUCCESS":
+		return fmt.Sprintf("%v; job %d completed on TrueNAS before it could be aborted", e.Err, e.JobID)
+	case e.State == "FAILED" && e.JobErr != nil:
+		return fmt.Sprintf("%v; job %d failed on TrueNAS before it could be aborted: %s", e.Err, e.JobID, e.JobErr.Message)
+	case e.State != "":
+		return fmt.Sprintf("%v; job %d was asked to abort but is still %s on TrueNAS", e.Err, e.JobID, e.State)
+	}
+	return fmt.Sprintf("%v; job %d was asked to abort on TrueNAS, it may still be running", e.Err, e.JobID)
+}
+
+func (e *JobCancelledError) Unwrap() error {
+	return e.Err
+}
+
 // NewAPIError creates a new APIError from a JSONRPCError
 func NewAPIError(rpcErr *JSONRPCError) *APIError {
 	details := ""
 	if rpcErr.Data != nil {
 		details = string(rpcErr.Data)
 	}
-	return &APIError{
+	apiErr := &APIError{
 		Code:    rpcErr.Code,
 		Message: rpcErr.Message,
 		Details: details,
 	}
+
+	// TrueNAS describes the failure in an object with the errno name and,
+	// for validation errors, the rejected fields
+	var errData struct {
+		ErrName string          `json:"errname"`
+		Extra   json.RawMessage `json:"extra"`
+	}
+	if len(rpcErr.Data) > 0 && json.Unmarshal(rpcErr.Data, &errData) == nil {
+		apiErr.ErrName = errData.ErrName
+		apiErr.ValidationErrors = parseValidationErrors(errData.Extra)
+	}
+	return apiErr
+}
+
+// parseValidationErrors extracts the [attribute, message, errno] entries
+// TrueNAS sends in the "extra" member of a validation error's data
+func parseValidationErrors(extra json.RawMessage) []ValidationFailure {
+	// extra is only a list of entries for validation errors; other errors
+	// may carry an object or null here
+	var entries [][]interface{}
+	if err := json.Unmarshal(extra, &entries); err != nil {
+		return nil
+	}
+
+	var failures []ValidationFailure
+	for _, entry := range entries {
+		if len(entry) < 2 {
+			continue
+		}
+		attribute, ok := entry[0].(string)
+		if !ok {
+			continue
+		}
+		message, _ := entry[1].(string)
+		failure := ValidationFailure{Attribute: attribute, Message: message}
+		if len(entry) > 2 {
+			if errno, ok := entry[2].(float64); ok {
+				failure.Errno = int(errno)
+			}
+		}
+		failures = append(failures, failure)
+	}
+	return failures
 }
 
 // NewConnectionError creates a new ConnectionError
@@ -114,16 +404,48 @@ func NewConnectionError(host string, err error) *ConnectionError {
 
 // IsNotFoundError checks if an error is a not found error
 func IsNotFoundError(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.IsNotFound()
-	}
-	return false
+	return errors.Is(err, ErrNotFound)
 }
 
 // IsAuthError checks if an error is an authentication error
 func IsAuthError(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.IsAuthError()
+	return errors.Is(err, ErrAuth)
+}
+
+// IsValidationError checks if an error is a validation error
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsTimeoutError checks if an error is due to a request or job timing out,
+// or the caller's deadline passing
+func IsTimeoutError(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsJobError checks if an error is due to a job failing or being aborted
+func IsJobError(err error) bool {
+	return errors.Is(err, ErrJobFailed)
+}
+
+// IsMethodNotFoundError checks if an error is due to TrueNAS not knowing the
+// method, usually because the version doesn't support it
+func IsMethodNotFoundError(err error) bool {
+	return errors.Is(err, ErrMethodNotFound)
+}
+
+// ValidationFailures returns the fields TrueNAS rejected, or nil if err is not
+// a validation error
+func ValidationFailures(err error) []ValidationFailure {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ValidationErrors
 	}
-	return false
+	return nil
+}
+
+// IsConnectionLostError checks if an error is due to the connection dropping
+// mid-call
+func IsConnectionLostError(err error) bool {
+	return errors.Is(err, ErrConnectionLost)
 }
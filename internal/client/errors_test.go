@@ -1,9 +1,12 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 )
 
 func TestAPIError(t *testing.T) {
@@ -204,6 +207,20 @@ func TestIsNotFoundError(t *testing.T) {
 			err:  &APIError{Code: ErrCodeInternalError, Message: "internal"},
 			want: false,
 		},
+		{
+			name: "wrapped not found error",
+			err:  fmt.Errorf("failed to query job status: %w", &APIError{Code: ErrCodeNotFound, Message: "not found"}),
+			want: true,
+		},
+		{
+			name: "ENOENT call error",
+			err: NewAPIError(&JSONRPCError{
+				Code:    -32001,
+				Message: "Method call error",
+				Data:    json.RawMessage(`{"error": 2, "errname": "ENOENT", "reason": "[ENOENT] None: Pool 5 does not exist"}`),
+			}),
+			want: true,
+		},
 		{
 			name: "standard error",
 			err:  errors.New("some error"),
@@ -261,3 +278,91 @@ func TestIsAuthError(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAPIErrorValidation(t *testing.T) {
+	data := json.RawMessage(`{
+		"error": 22,
+		"errname": "EINVAL",
+		"reason": "[EINVAL] sharing_smb_create.path: This field is required",
+		"extra": [
+			["sharing_smb_create.path", "This field is required", 22],
+			["sharing_smb_create.name", "Share with this name already exists", 17]
+		]
+	}`)
+	apiErr := NewAPIError(&JSONRPCError{Code: -32001, Message: "Method call error", Data: data})
+
+	want := []ValidationFailure{
+		{Attribute: "sharing_smb_create.path", Message: "This field is required", Errno: 22},
+		{Attribute: "sharing_smb_create.name", Message: "Share with this name already exists", Errno: 17},
+	}
+	if len(apiErr.ValidationErrors) != len(want) {
+		t.Fatalf("APIError.ValidationErrors = %+v, want %+v", apiErr.ValidationErrors, want)
+	}
+	for i := range want {
+		if apiErr.ValidationErrors[i] != want[i] {
+			t.Errorf("ValidationErrors[%d] = %+v, want %+v", i, apiErr.ValidationErrors[i], want[i])
+		}
+	}
+	if got := apiErr.ValidationErrors[0].Field(); got != "path" {
+		t.Errorf("ValidationFailure.Field() = %q, want %q", got, "path")
+	}
+	if !apiErr.IsValidationError() {
+		t.Error("APIError.IsValidationError() = false, want true")
+	}
+	if !contains(apiErr.Error(), "sharing_smb_create.name: Share with this name already exists") {
+		t.Errorf("APIError.Error() = %q, want it to list the failures", apiErr.Error())
+	}
+
+	wrapped := fmt.Errorf("create failed: %w", apiErr)
+	if got := ValidationFailures(wrapped); len(got) != 2 {
+		t.Errorf("ValidationFailures() = %+v, want 2 failures", got)
+	}
+
+	other := NewAPIError(&JSONRPCError{Code: -32001, Message: "Method call error", Data: json.RawMessage(`{"error": 2, "extra": null}`)})
+	if other.ValidationErrors != nil || other.IsValidationError() {
+		t.Errorf("non-validation error parsed as validation error: %+v", other.ValidationErrors)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	kinds := []error{ErrNotFound, ErrValidation, ErrAuth, ErrTimeout, ErrConnectionLost, ErrJobFailed, ErrMethodNotFound}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not found", &APIError{Code: ErrCodeNotFound, Message: "not found"}, ErrNotFound},
+		{"validation", &APIError{Code: ErrCodeValidation, Message: "invalid"}, ErrValidation},
+		{"API auth", &APIError{Code: ErrCodeNotAuthenticated, Message: "not authenticated"}, ErrAuth},
+		{"rejected credentials", &AuthError{Reason: "invalid API key"}, ErrAuth},
+		{"request timeout", &TimeoutError{Method: "pool.query", Timeout: time.Second}, ErrTimeout},
+		{"job timeout", &JobCancelledError{JobID: 1, Err: &TimeoutError{JobID: 1, Timeout: time.Second}}, ErrTimeout},
+		{"connection lost", NewConnectionLostError("pool.create", errors.New("EOF")), ErrConnectionLost},
+		{"job failed", &JobError{JobID: 1, State: "FAILED", Message: "boom"}, ErrJobFailed},
+		{"method not found", &APIError{Code: ErrCodeMethodNotFound, Message: "Method not found"}, ErrMethodNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", tt.err))
+			for _, kind := range kinds {
+				if got := errors.Is(wrapped, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", wrapped, kind, got, kind == tt.want)
+				}
+			}
+		})
+	}
+
+	if !IsTimeoutError(&TimeoutError{Method: "pool.query", Timeout: time.Second}) {
+		t.Error("IsTimeoutError() = false for TimeoutError")
+	}
+	if !IsTimeoutError(fmt.Errorf("wait: %w", context.DeadlineExceeded)) {
+		t.Error("IsTimeoutError() = false for context.DeadlineExceeded")
+	}
+
+	var jobErr *JobError
+	if !errors.As(fmt.Errorf("app delete: %w", &JobError{JobID: 12, Logs: "log"}), &jobErr) || jobErr.JobID != 12 {
+		t.Errorf("errors.As(*JobError) = %+v, want job 12", jobErr)
+	}
+}
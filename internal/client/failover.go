@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// failoverWindow is how long a client with several hosts keeps reconnecting
+// after losing its connection. The virtual IP of an HA pair is unreachable
+// for a minute or two while the standby controller takes over.
+const failoverWindow = 3 * time.Minute
+
+// Statuses failover.status reports for a controller that serves the API.
+// The standby controller reports BACKUP, and either may report ELECTING or
+// IMPORTING during a failover.
+const (
+	failoverMaster = "MASTER"
+	failoverSingle = "SINGLE"
+)
+
+// host returns the host the client is connected to, or last connected to
+func (c *Client) host() string {
+	return c.hosts[c.activeHost.Load()]
+}
+
+// connectActive opens the primary connection to the first of several hosts
+// that is the active controller. Each host is tried in order; a controller
+// that is reachable but on standby is skipped like one that isn't.
+func (c *Client) connectActive(ctx context.Context) error {
+	hostsErr := &HostsError{}
+	for i, host := range c.hosts {
+		err := c.connectPrimary(ctx, host)
+		if err == nil {
+			err = c.checkActive(ctx, host)
+			if err == nil {
+				c.activeHost.Store(int32(i))
+				return nil
+			}
+			primary := c.slots[0]
+			primary.mu.Lock()
+			conn := primary.conn
+			primary.mu.Unlock()
+			if conn != nil {
+				c.dropConnection(conn)
+			}
+		}
+		if !isRetryableConnectError(err) {
+			// Both controllers share their credentials, so the others
+			// would reject them too
+			return err
+		}
+		hostsErr.Hosts = append(hostsErr.Hosts, host)
+		hostsErr.Errs = append(hostsErr.Errs, err)
+	}
+	return hostsErr
+}
+
+// checkActive asks the controller on the primary connection whether it is
+// the active one. Systems without HA don't have failover.status and always
+// are.
+func (c *Client) checkActive(ctx context.Context, host string) error {
+	var status string
+	err := c.call(withSlot(ctx, c.slots[0]), "failover.status", nil, &status)
+	if errors.Is(err, ErrMethodNotFound) {
+		return nil
+	}
+	if err != nil {
+		return NewConnectionError(host, fmt.Errorf("failover.status: %w", err))
+	}
+	if status != failoverMaster && status != failoverSingle {
+		return NewConnectionError(host, fmt.Errorf("controller is %s, not the active one", status))
+	}
+	return nil
+}
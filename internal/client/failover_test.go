@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func TestConnectFindsActiveController(t *testing.T) {
+	standby := testserver.New()
+	defer standby.Close()
+	standby.SetFailoverStatus("BACKUP")
+	active := testserver.New()
+	defer active.Close()
+	active.SetFailoverStatus("MASTER")
+
+	// The first host is down, as the virtual IP is during a failover
+	down := testserver.New()
+	downHost := down.Host()
+	down.Close()
+
+	c := NewClient(&Config{Hosts: []string{downHost, standby.Host(), active.Host()}, APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if c.host() != active.Host() {
+		t.Errorf("connected to %s, want the active controller %s", c.host(), active.Host())
+	}
+	if standby.Connections() != 0 {
+		t.Error("the connection to the standby controller was left open")
+	}
+	if err := c.Query(context.Background(), "pool", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if active.CallCount("pool.query") != 1 {
+		t.Error("pool.query was not sent to the active controller")
+	}
+}
+
+func TestConnectWithoutFailoverStatus(t *testing.T) {
+	// Systems without HA may not know failover.status
+	srv := testserver.New()
+	defer srv.Close()
+	srv.Handle("failover.status", func(params []json.RawMessage) (interface{}, error) {
+		return nil, &testserver.Error{Code: testserver.CodeMethodNotFound, Message: "Method not found"}
+	})
+
+	c := NewClient(&Config{Hosts: []string{srv.Host(), "192.0.2.1:443"}, APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+}
+
+func TestConnectNoActiveController(t *testing.T) {
+	a := testserver.New()
+	defer a.Close()
+	a.SetFailoverStatus("BACKUP")
+	b := testserver.New()
+	defer b.Close()
+	b.SetFailoverStatus("ELECTING")
+
+	c := NewClient(&Config{Hosts: []string{a.Host(), b.Host()}, APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	err := c.Connect(context.Background())
+
+	var hostsErr *HostsError
+	if !errors.As(err, &hostsErr) || len(hostsErr.Hosts) != 2 {
+		t.Fatalf("Connect() error = %v, want a HostsError for both hosts", err)
+	}
+	if !isRetryableConnectError(err) {
+		t.Error("a HostsError is not retried")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "BACKUP") || !strings.Contains(msg, "ELECTING") || strings.Contains(msg, "Please verify") {
+		t.Errorf("Connect() error message = %q", msg)
+	}
+}
+
+func TestConnectStopsOnAuthFailure(t *testing.T) {
+	a := testserver.New()
+	defer a.Close()
+	b := testserver.New()
+	defer b.Close()
+
+	c := NewClient(&Config{Hosts: []string{a.Host(), b.Host()}, APIKey: "1-wrong", Timeout: 5 * time.Second})
+	defer c.Close()
+	if err := c.Connect(context.Background()); !IsAuthError(err) {
+		t.Fatalf("Connect() error = %v, want an authentication error", err)
+	}
+	if b.CallCount("auth.login_ex") != 0 {
+		t.Error("the rejected credentials were tried on the next host")
+	}
+}
+
+func TestFailover(t *testing.T) {
+	first := testserver.New()
+	first.SetFailoverStatus("MASTER")
+	second := testserver.New()
+	defer second.Close()
+	second.SetFailoverStatus("BACKUP")
+
+	c := NewClient(&Config{Hosts: []string{first.Host(), second.Host()}, APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The active controller goes away and the standby takes over
+	first.Close()
+	second.SetFailoverStatus("MASTER")
+
+	if err := c.Query(ctx, "pool", nil, nil); err != nil {
+		t.Fatalf("Query() after failover error = %v", err)
+	}
+	if c.host() != second.Host() || second.CallCount("pool.query") != 1 {
+		t.Errorf("connected to %s after failover, want %s", c.host(), second.Host())
+	}
+}
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// jobEventName is the event the middleware publishes job changes under
+	jobEventName = "core.get_jobs"
+
+	// jobPollInterval is used when the job event stream is unavailable
+	jobPollInterval = 2 * time.Second
+
+	// jobAbortTimeout bounds the core.job_abort call made after the caller's
+	// context has already ended
+	jobAbortTimeout = 10 * time.Second
+
+	// jobAbortPollInterval is how often an aborted job is read back until
+	// it ends
+	jobAbortPollInterval = 250 * time.Millisecond
+
+	// jobEventFallbackInterval is a safety-net poll while subscribed, in case
+	// an event is missed (e.g. across a reconnect)
+	jobEventFallbackInterval = 30 * time.Second
+)
+
+// JobProgress describes how far along a running job is
+type JobProgress struct {
+	JobID       int64
+	Method      string
+	Percent     float64
+	Description string
+}
+
+// JobProgressFunc receives progress updates while waiting for a job. It may
+// be nil.
+type JobProgressFunc func(JobProgress)
+
+// subscriptionState tracks whether the current connection is subscribed to
+// the job event stream
+type subscriptionState int
+
+const (
+	subscriptionNone subscriptionState = iota
+	subscriptionActive
+	subscriptionUnavailable
+)
+
+// jobTracker caches job state pushed by the middleware and wakes WaitForJob
+// callers as soon as their job changes. Only jobs with a registered waiter
+// are tracked, so the cache stays bounded.
+type jobTracker struct {
+	mu      sync.Mutex
+	waiters map[int64]*jobWaiter
+
+	// subMu serializes core.subscribe calls so concurrent waiters don't
+	// subscribe the same connection twice. reset runs on the reader
+	// goroutine that answers those calls, so it must never take subMu.
+	subMu sync.Mutex
+
+	// stateMu guards state and epoch. epoch counts resets, so a
+	// subscription that completes after its connection was lost isn't
+	// taken for one on the next connection.
+	stateMu sync.Mutex
+	state   subscriptionState
+	epoch   uint64
+}
+
+type jobWaiter struct {
+	refs   int
+	job    map[string]interface{}
+	notify chan struct{}
+}
+
+func newJobTracker() *jobTracker {
+	return &jobTracker{
+		waiters: make(map[int64]*jobWaiter),
+	}
+}
+
+// watch registers interest in a job and returns the channel signalled on
+// every change to it
+func (t *jobTracker) watch(jobID int64) <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.waiters[jobID]
+	if !ok {
+		w = &jobWaiter{notify: make(chan struct{}, 1)}
+		t.waiters[jobID] = w
+	}
+	w.refs++
+	return w.notify
+}
+
+// unwatch drops interest in a job, forgetting its cached state once the
+// last waiter is gone
+func (t *jobTracker) unwatch(jobID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.waiters[jobID]
+	if !ok {
+		return
+	}
+	w.refs--
+	if w.refs <= 0 {
+		delete(t.waiters, jobID)
+	}
+}
+
+// update merges pushed fields into the cached job and wakes its waiters.
+// It is called from the reader goroutine and must never block.
+func (t *jobTracker) update(jobID int64, fields map[string]interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.waiters[jobID]
+	if !ok {
+		return
+	}
+	if w.job == nil {
+		w.job = make(map[string]interface{}, len(fields))
+	}
+	for k, v := range fields {
+		w.job[k] = v
+	}
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// latest returns a copy of the most recent pushed state of a job, or nil if
+// no event has been received for it
+func (t *jobTracker) latest(jobID int64) map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.waiters[jobID]
+	if !ok || w.job == nil {
+		return nil
+	}
+	job := make(map[string]interface{}, len(w.job))
+	for k, v := range w.job {
+		job[k] = v
+	}
+	return job
+}
+
+// reset forgets the subscription, e.g. after the connection is lost
+func (t *jobTracker) reset() {
+	t.stateMu.Lock()
+	defer t.stateMu.Unlock()
+	t.state = subscriptionNone
+	t.epoch++
+}
+
+// subscription returns the subscription state and the epoch it belongs to
+func (t *jobTracker) subscription() (subscriptionState, uint64) {
+	t.stateMu.Lock()
+	defer t.stateMu.Unlock()
+	return t.state, t.epoch
+}
+
+// settle records the outcome of a subscription attempt made in epoch,
+// unless the connection has been reset since
+func (t *jobTracker) settle(epoch uint64, state subscriptionState) {
+	t.stateMu.Lock()
+	defer t.stateMu.Unlock()
+	if t.epoch == epoch {
+		t.state = state
+	}
+}
+
+// handleJobEvent routes a core.get_jobs collection update to the tracker
+func (c *Client) handleJobEvent(update *CollectionUpdate) {
+	id, ok := update.Fields["id"].(float64)
+	if !ok {
+		id, ok = update.ID.(float64)
+	}
+	if !ok {
+		return
+	}
+	c.jobs.update(int64(id), update.Fields)
+}
+
+// subscribeJobs subscribes the primary connection to the job event stream.
+// It returns false if events are unavailable and callers must poll. Only a
+// middleware that doesn't know or rejects the subscription is remembered;
+// after any other failure, e.g. a cancelled ctx or a dropped connection,
+// the next call tries again.
+func (c *Client) subscribeJobs(ctx context.Context) bool {
+	c.jobs.subMu.Lock()
+	defer c.jobs.subMu.Unlock()
+
+	state, epoch := c.jobs.subscription()
+	switch state {
+	case subscriptionActive:
+		return true
+	case subscriptionUnavailable:
+		return false
+	}
+
+	var subscriptionID interface{}
+	if err := c.Call(withSlot(ctx, c.slots[0]), "core.subscribe", []interface{}{jobEventName}, &subscriptionID); err != nil {
+		if IsMethodNotFoundError(err) || IsValidationError(err) {
+			c.jobs.settle(epoch, subscriptionUnavailable)
+		}
+		return false
+	}
+	c.jobs.settle(epoch, subscriptionActive)
+	return true
+}
+
+// getJob fetches the current state of a job with core.get_jobs
+func (c *Client) getJob(ctx context.Context, jobID int64) (map[string]interface{}, error) {
+	// The API returns an array, get the first element
+	var jobs []map[string]interface{}
+	err := c.Call(ctx, "core.get_jobs", []interface{}{
+		[][]interface{}{{"id", "=", jobID}},
+	}, &jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job status: %w", err)
+	}
+
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("job %d not found", jobID)
+	}
+	return jobs[0], nil
+}
+
+// abortJob asks the middleware to abort a job the caller stopped waiting
+// for, then reads the job back until it ends, since the job may have
+// finished before the abort reached it. It uses its own context since the
+// caller's is usually already done.
+func (c *Client) abortJob(jobID int64, cause error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), jobAbortTimeout)
+	defer cancel()
+
+	cancelErr := &JobCancelledError{JobID: jobID, Err: cause}
+	if err := c.Call(ctx, "core.job_abort", []interface{}{jobID}, nil); err != nil {
+		cancelErr.AbortErr = err
+		return cancelErr
+	}
+
+	for {
+		job, err := c.getJob(ctx, jobID)
+		if err != nil {
+			cancelErr.AbortErr = err
+			return cancelErr
+		}
+		cancelErr.State, _ = job["state"].(string)
+		switch cancelErr.State {
+		case "ABORTED":
+			cancelErr.Aborted = true
+			return cancelErr
+		case "SUCCESS":
+			return cancelErr
+		case "FAILED":
+			cancelErr.JobErr = newJobError(jobID, cancelErr.State, job)
+			return cancelErr
+		}
+
+		select {
+		case <-time.After(jobAbortPollInterval):
+		case <-ctx.Done():
+			return cancelErr
+		}
+	}
+}
+
+// jobOutcome inspects a job and reports whether it has finished, along with
+// its result or failure
+func jobOutcome(jobID int64, job map[string]interface{}) (bool, map[string]interface{}, error) {
+	state, _ := job["state"].(string)
+
+	switch state {
+	case "SUCCESS":
+		if result, ok := job["result"].(map[string]interface{}); ok {
+			return true, result, nil
+		}
+		// Some jobs return simple values or nil
+		return true, job, nil
+	case "FAILED", "ABORTED":
+		return true, nil, newJobError(jobID, state, job)
+	default:
+		return false, nil, nil
+	}
+}
+
+// jobProgress extracts the progress of a job, returning false if the job
+// carries no progress information
+func jobProgress(jobID int64, job map[string]interface{}) (JobProgress, bool) {
+	progress, ok := job["progress"].(map[string]interface{})
+	if !ok {
+		return JobProgress{}, false
+	}
+
+	p := JobProgress{JobID: jobID}
+	p.Method, _ = job["method"].(string)
+	p.Percent, _ = progress["percent"].(float64)
+	p.Description, _ = progress["description"].(string)
+	if p.Percent == 0 && p.Description == "" {
+		return JobProgress{}, false
+	}
+	return p, true
+}
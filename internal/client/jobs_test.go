@@ -0,0 +1,465 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJobOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      map[string]interface{}
+		wantDone bool
+		wantErr  bool
+	}{
+		{
+			name:     "running",
+			job:      map[string]interface{}{"state": "RUNNING"},
+			wantDone: false,
+		},
+		{
+			name:     "success with result",
+			job:      map[string]interface{}{"state": "SUCCESS", "result": map[string]interface{}{"id": 1.0}},
+			wantDone: true,
+		},
+		{
+			name:     "failed",
+			job:      map[string]interface{}{"state": "FAILED", "error": "boom"},
+			wantDone: true,
+			wantErr:  true,
+		},
+		{
+			name:     "aborted",
+			job:      map[string]interface{}{"state": "ABORTED"},
+			wantDone: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, _, err := jobOutcome(1, tt.job)
+			if done != tt.wantDone {
+				t.Errorf("jobOutcome() done = %v, want %v", done, tt.wantDone)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("jobOutcome() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForJobEvent(t *testing.T) {
+	ts := newTestServer(t)
+	ts.handle("core.subscribe", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return "sub-1", nil
+	})
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return []map[string]interface{}{{"id": 42, "state": "RUNNING"}}, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	go func() {
+		// Wait until the client has subscribed and checked the job once
+		for ts.callCount("core.get_jobs") == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		ts.notify("collection_update", map[string]interface{}{
+			"msg":        "changed",
+			"collection": "core.get_jobs",
+			"id":         42,
+			"fields": map[string]interface{}{
+				"id":     42,
+				"state":  "SUCCESS",
+				"result": map[string]interface{}{"name": "tank"},
+			},
+		})
+	}()
+
+	start := time.Now()
+	result, err := c.WaitForJob(context.Background(), 42, 10*time.Second, nil)
+	if err != nil {
+		t.Fatalf("WaitForJob() error = %v", err)
+	}
+	if result["name"] != "tank" {
+		t.Errorf("WaitForJob() result = %v, want name=tank", result)
+	}
+	if elapsed := time.Since(start); elapsed >= jobPollInterval {
+		t.Errorf("WaitForJob() took %v, expected event to wake it before the poll interval", elapsed)
+	}
+	if got := ts.callCount("core.get_jobs"); got != 1 {
+		t.Errorf("core.get_jobs called %d times, want 1", got)
+	}
+}
+
+func TestWaitForJobPollingFallback(t *testing.T) {
+	ts := newTestServer(t)
+
+	var polls int32
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		if atomic.AddInt32(&polls, 1) == 1 {
+			return []map[string]interface{}{{"id": 7, "state": "RUNNING"}}, nil
+		}
+		return []map[string]interface{}{{
+			"id":           7,
+			"state":        "FAILED",
+			"error":        "disk busy",
+			"logs_excerpt": "zpool: cannot open sdb",
+		}}, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	_, err := c.WaitForJob(context.Background(), 7, 10*time.Second, nil)
+	if err == nil {
+		t.Fatal("WaitForJob() expected error for failed job")
+	}
+	if !contains(err.Error(), "disk busy") {
+		t.Errorf("WaitForJob() error = %v, want job error message", err)
+	}
+	var jobErr *JobError
+	if !errors.As(err, &jobErr) {
+		t.Fatalf("WaitForJob() error type = %T, want *JobError", err)
+	}
+	if jobErr.Logs != "zpool: cannot open sdb" {
+		t.Errorf("JobError.Logs = %q, want logs excerpt", jobErr.Logs)
+	}
+	if !contains(err.Error(), "zpool: cannot open sdb") {
+		t.Errorf("WaitForJob() error = %v, want logs excerpt in message", err)
+	}
+	if got := ts.callCount("core.subscribe"); got != 1 {
+		t.Errorf("core.subscribe called %d times, want 1", got)
+	}
+	if got := atomic.LoadInt32(&polls); got != 2 {
+		t.Errorf("core.get_jobs called %d times, want 2", got)
+	}
+}
+
+func TestWaitForJobProgress(t *testing.T) {
+	ts := newTestServer(t)
+	ts.handle("core.subscribe", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return "sub-1", nil
+	})
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return []map[string]interface{}{{
+			"id":       9,
+			"method":   "app.create",
+			"state":    "RUNNING",
+			"progress": map[string]interface{}{"percent": 10, "description": "Pulling images"},
+		}}, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	go func() {
+		for ts.callCount("core.get_jobs") == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		for _, fields := range []map[string]interface{}{
+			{"id": 9, "progress": map[string]interface{}{"percent": 40, "description": "Pulling images"}},
+			{"id": 9, "progress": map[string]interface{}{"percent": 40, "description": "Pulling images"}},
+			{"id": 9, "state": "SUCCESS", "progress": map[string]interface{}{"percent": 100, "description": "Done"}},
+		} {
+			ts.notify("collection_update", map[string]interface{}{
+				"msg":        "changed",
+				"collection": "core.get_jobs",
+				"id":         9,
+				"fields":     fields,
+			})
+		}
+	}()
+
+	var updates []JobProgress
+	_, err := c.WaitForJob(context.Background(), 9, 10*time.Second, func(p JobProgress) {
+		updates = append(updates, p)
+	})
+	if err != nil {
+		t.Fatalf("WaitForJob() error = %v", err)
+	}
+
+	if len(updates) == 0 || updates[0].Percent != 10 || updates[0].Method != "app.create" {
+		t.Fatalf("first progress update = %+v, want 10%% from app.create", updates)
+	}
+	// Duplicate events must not produce duplicate updates
+	for i := 1; i < len(updates); i++ {
+		if updates[i] == updates[i-1] {
+			t.Errorf("progress update %d repeated: %+v", i, updates[i])
+		}
+	}
+}
+
+func TestWaitForJobAbortsOnCancel(t *testing.T) {
+	ts := newTestServer(t)
+	var aborted atomic.Bool
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		if aborted.Load() {
+			return []map[string]interface{}{{"id": 3, "state": "ABORTED"}}, nil
+		}
+		return []map[string]interface{}{{"id": 3, "state": "RUNNING"}}, nil
+	})
+	ts.handle("core.job_abort", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		aborted.Store(true)
+		return nil, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for ts.callCount("core.get_jobs") == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+	}()
+
+	_, err := c.WaitForJob(ctx, 3, time.Minute, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WaitForJob() error = %v, want context.Canceled", err)
+	}
+	var cancelErr *JobCancelledError
+	if !errors.As(err, &cancelErr) {
+		t.Fatalf("WaitForJob() error type = %T, want *JobCancelledError", err)
+	}
+	if !cancelErr.Aborted {
+		t.Errorf("JobCancelledError.Aborted = false, want true (abort error: %v)", cancelErr.AbortErr)
+	}
+	if got := ts.callCount("core.job_abort"); got != 1 {
+		t.Errorf("core.job_abort called %d times, want 1", got)
+	}
+}
+
+func TestWaitForJobAbortFailsOnTimeout(t *testing.T) {
+	ts := newTestServer(t)
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return []map[string]interface{}{{"id": 4, "state": "RUNNING"}}, nil
+	})
+	ts.handle("core.job_abort", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return nil, &JSONRPCError{Code: ErrCodeInvalidParams, Message: "Job is not abortable"}
+	})
+
+	c := newTestClient(t, ts)
+
+	_, err := c.WaitForJob(context.Background(), 4, 100*time.Millisecond, nil)
+	var cancelErr *JobCancelledError
+	if !errors.As(err, &cancelErr) {
+		t.Fatalf("WaitForJob() error = %v, want *JobCancelledError", err)
+	}
+	if cancelErr.Aborted {
+		t.Error("JobCancelledError.Aborted = true, want false")
+	}
+	if !contains(err.Error(), "may still be running") {
+		t.Errorf("WaitForJob() error = %v, want it to say the job may still be running", err)
+	}
+}
+
+func TestWaitForJobAbortReportsFinalState(t *testing.T) {
+	tests := []struct {
+		name  string
+		final map[string]interface{}
+		want  string
+	}{
+		{"succeeded", map[string]interface{}{"id": 9, "state": "SUCCESS"}, "completed on TrueNAS before it could be aborted"},
+		{"failed", map[string]interface{}{"id": 9, "state": "FAILED", "error": "disk busy"}, "failed on TrueNAS before it could be aborted: disk busy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			var aborted atomic.Bool
+			ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+				if aborted.Load() {
+					return []map[string]interface{}{tt.final}, nil
+				}
+				return []map[string]interface{}{{"id": 9, "state": "RUNNING"}}, nil
+			})
+			// The job ends on its own before the abort reaches it, which
+			// core.job_abort still answers without an error
+			ts.handle("core.job_abort", func(json.RawMessage) (interface{}, *JSONRPCError) {
+				aborted.Store(true)
+				return nil, nil
+			})
+
+			c := newTestClient(t, ts)
+
+			_, err := c.WaitForJob(context.Background(), 9, 100*time.Millisecond, nil)
+			var cancelErr *JobCancelledError
+			if !errors.As(err, &cancelErr) {
+				t.Fatalf("WaitForJob() error = %v, want *JobCancelledError", err)
+			}
+			if cancelErr.Aborted {
+				t.Error("JobCancelledError.Aborted = true, want false")
+			}
+			if cancelErr.State != tt.final["state"] {
+				t.Errorf("JobCancelledError.State = %q, want %q", cancelErr.State, tt.final["state"])
+			}
+			if !contains(err.Error(), tt.want) || contains(err.Error(), "was aborted") {
+				t.Errorf("WaitForJob() error = %v, want it to say the job %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForJobContextDeadline(t *testing.T) {
+	ts := newTestServer(t)
+	var aborted atomic.Bool
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		if aborted.Load() {
+			return []map[string]interface{}{{"id": 5, "state": "ABORTED"}}, nil
+		}
+		return []map[string]interface{}{{"id": 5, "state": "RUNNING"}}, nil
+	})
+	ts.handle("core.job_abort", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		aborted.Store(true)
+		return nil, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	// A resource's timeouts block bounds ctx; the default job timeout is
+	// longer
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := c.WaitForJob(ctx, 5, 0, nil)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.JobID != 5 {
+		t.Fatalf("WaitForJob() error = %v, want a timeout of job 5", err)
+	}
+	// The error names the deadline that passed, not the job timeout
+	if !timeoutErr.Deadline || timeoutErr.Timeout >= time.Second {
+		t.Errorf("TimeoutError = %+v, want the deadline after about 100ms", timeoutErr)
+	}
+	if !contains(err.Error(), "deadline") || contains(err.Error(), c.JobTimeout().String()) {
+		t.Errorf("WaitForJob() error = %v, want it to blame the deadline", err)
+	}
+	if got := ts.callCount("core.job_abort"); got != 1 {
+		t.Errorf("core.job_abort called %d times, want 1", got)
+	}
+}
+
+func TestFollowJobLeavesJobRunning(t *testing.T) {
+	ts := newTestServer(t)
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return []map[string]interface{}{{"id": 6, "state": "RUNNING"}}, nil
+	})
+	ts.handle("core.job_abort", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return nil, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	_, err := c.FollowJob(context.Background(), 6, 100*time.Millisecond, nil)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.JobID != 6 {
+		t.Fatalf("FollowJob() error = %v, want a timeout of job 6", err)
+	}
+	var cancelErr *JobCancelledError
+	if errors.As(err, &cancelErr) {
+		t.Errorf("FollowJob() error = %v, want the job left running", err)
+	}
+	if got := ts.callCount("core.job_abort"); got != 0 {
+		t.Errorf("core.job_abort called %d times, want 0", got)
+	}
+}
+
+func TestSubscribeJobsRetriesAfterTransientErrors(t *testing.T) {
+	ts := newTestServer(t)
+	var fail atomic.Bool
+	fail.Store(true)
+	ts.handle("core.subscribe", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		if fail.Load() {
+			return nil, &JSONRPCError{Code: ErrCodeInternalError, Message: "middleware is busy"}
+		}
+		return "sub-1", nil
+	})
+
+	c := newTestClient(t, ts)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// A caller giving up must not disable events for later callers
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if c.subscribeJobs(ctx) {
+		t.Fatal("subscribeJobs() with a cancelled ctx = true")
+	}
+	if c.subscribeJobs(context.Background()) {
+		t.Fatal("subscribeJobs() after a server error = true")
+	}
+
+	fail.Store(false)
+	if !c.subscribeJobs(context.Background()) {
+		t.Fatal("subscribeJobs() = false, want it to retry after transient errors")
+	}
+}
+
+func TestSubscribeJobsRemembersMissingMethod(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if c.subscribeJobs(context.Background()) {
+			t.Fatal("subscribeJobs() = true without core.subscribe")
+		}
+	}
+	if got := ts.callCount("core.subscribe"); got != 1 {
+		t.Errorf("core.subscribe called %d times, want 1", got)
+	}
+}
+
+func TestWaitForJobConnectionLostDuringSubscribe(t *testing.T) {
+	ts := newTestServer(t)
+	var dropped atomic.Bool
+	ts.handle("core.subscribe", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		if !dropped.Swap(true) {
+			// The reader goroutine resets the subscription while this
+			// call is still waiting for its answer
+			ts.dropConnections()
+		}
+		return "sub-1", nil
+	})
+	ts.handle("core.get_jobs", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return []map[string]interface{}{{"id": 8, "state": "SUCCESS", "result": map[string]interface{}{"name": "tank"}}}, nil
+	})
+	ts.handle("core.job_abort", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return nil, nil
+	})
+
+	c := newTestClient(t, ts)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	start := time.Now()
+	result, err := c.WaitForJob(ctx, 8, 0, nil)
+	if err != nil {
+		t.Fatalf("WaitForJob() error = %v", err)
+	}
+	if result["name"] != "tank" {
+		t.Errorf("WaitForJob() result = %v, want name=tank", result)
+	}
+	if elapsed := time.Since(start); elapsed >= jobPollInterval {
+		t.Errorf("WaitForJob() took %v, want the lost connection to fail core.subscribe at once", elapsed)
+	}
+	if got := ts.callCount("core.job_abort"); got != 0 {
+		t.Errorf("core.job_abort called %d times, want 0", got)
+	}
+
+	// The subscription made on the lost connection doesn't count
+	if !c.subscribeJobs(context.Background()) {
+		t.Fatal("subscribeJobs() = false after reconnecting")
+	}
+	if got := ts.callCount("core.subscribe"); got != 2 {
+		t.Errorf("core.subscribe called %d times, want 2", got)
+	}
+}
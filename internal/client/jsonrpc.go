@@ -25,6 +25,41 @@ type JSONRPCError struct {
 	Data    json.RawMessage `json:"data,omitempty"`
 }
 
+// JSONRPCNotification represents a server-initiated JSON-RPC 2.0 message.
+// Notifications carry a method and params but no request ID.
+type JSONRPCNotification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// jsonrpcMessage is the envelope for anything read off the socket. It is
+// either a response to one of our requests (ID set) or a notification
+// (Method set, no ID).
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+}
+
+// isNotification returns true if the message was pushed by the server rather
+// than sent in reply to a request
+func (m *jsonrpcMessage) isNotification() bool {
+	return m.ID == nil && m.Method != ""
+}
+
+// CollectionUpdate is the payload of a collection_update notification, sent
+// for every event the client has subscribed to with core.subscribe
+type CollectionUpdate struct {
+	Msg        string                 `json:"msg"`
+	Collection string                 `json:"collection"`
+	ID         interface{}            `json:"id,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+}
+
 // NewRequest creates a new JSON-RPC 2.0 request
 func NewRequest(id int64, method string, params interface{}) *JSONRPCRequest {
 	return &JSONRPCRequest{
@@ -44,6 +79,9 @@ type QueryParams struct {
 	Select  []string                 `json:"select,omitempty"`
 	Filters [][]interface{}          `json:"filters,omitempty"`
 	Options map[string]interface{}   `json:"options,omitempty"`
+	// Extra is the extra query option, e.g. retrieve_children for
+	// pool.dataset.query or properties for zfs.snapshot.query
+	Extra map[string]interface{} `json:"extra,omitempty"`
 }
 
 // NewQueryParams creates a new QueryParams with defaults
@@ -57,6 +95,24 @@ func (q *QueryParams) WithFilter(field string, operator string, value interface{
 	return q
 }
 
+// WithFilters adds filters built with Where, Or and And to the query. A
+// record must match all of them.
+func (q *QueryParams) WithFilters(filters ...Filter) *QueryParams {
+	for _, f := range filters {
+		if f.isGroup() {
+			// The top-level filter list is already an AND
+			for _, inner := range f {
+				q.WithFilters(inner.(Filter))
+			}
+			continue
+		}
+		if len(f) > 0 {
+			q.Filters = append(q.Filters, []interface{}(f))
+		}
+	}
+	return q
+}
+
 // WithLimit sets the limit for the query
 func (q *QueryParams) WithLimit(limit int) *QueryParams {
 	q.Limit = limit
@@ -74,3 +130,20 @@ func (q *QueryParams) WithSelect(fields ...string) *QueryParams {
 	q.Select = fields
 	return q
 }
+
+// WithOrderBy sets the fields to sort by. Prefix a field with "-" to sort in
+// descending order.
+func (q *QueryParams) WithOrderBy(fields ...string) *QueryParams {
+	q.OrderBy = fields
+	return q
+}
+
+// WithExtra sets an extra query option, e.g. WithExtra("retrieve_children",
+// false) for pool.dataset.query
+func (q *QueryParams) WithExtra(key string, value interface{}) *QueryParams {
+	if q.Extra == nil {
+		q.Extra = map[string]interface{}{}
+	}
+	q.Extra[key] = value
+	return q
+}
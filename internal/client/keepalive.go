@@ -0,0 +1,34 @@
+package client
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// keepalive sends a WebSocket ping every pingPeriod until done is closed.
+// Idle connections would otherwise be dropped silently by NAT or firewalls.
+// The pong handler set in Connect pushes the read deadline out by
+// pongTimeout, so a peer that stops answering makes the reader fail and the
+// connection is dropped before the next Call needs it.
+func (c *Client) keepalive(conn *websocket.Conn, done <-chan struct{}) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			// WriteControl is safe to call concurrently with WriteJSON
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.timeout)); err != nil {
+				c.dropConnection(conn)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestKeepaliveSendsPings(t *testing.T) {
+	ts := newTestServer(t)
+
+	c := newTestClient(t, ts)
+	c.pingPeriod = 20 * time.Millisecond
+	c.pongTimeout = 500 * time.Millisecond
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// Stay idle for longer than the pong timeout; pongs must keep it alive
+	time.Sleep(time.Second)
+
+	if got := ts.pingCount(); got < 5 {
+		t.Errorf("server received %d pings, want at least 5", got)
+	}
+	if !c.isConnected() {
+		t.Error("client disconnected while the server was answering pings")
+	}
+}
+
+func TestKeepaliveDetectsDeadConnection(t *testing.T) {
+	ts := newTestServer(t)
+	ts.ignorePings = true
+
+	c := newTestClient(t, ts)
+	c.pingPeriod = 20 * time.Millisecond
+	c.pongTimeout = 200 * time.Millisecond
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.isConnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not notice the server stopped answering pings")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that spaces out the requests sent to the
+// API, see Config.RateLimit
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until a request may be sent or ctx ends
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	// Take a token now, going into debt if there is none, and sleep until
+	// the debt is paid off. Callers queue up in the order they arrived.
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the token back for the callers behind us
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// concurrencyLimits caps how many calls that change something may run at
+// once in an API namespace, see Client.LimitConcurrency
+type concurrencyLimits struct {
+	mu     sync.Mutex
+	limits map[string]chan struct{}
+	// configured namespaces were set by the user and aren't changed by
+	// Serialize
+	configured map[string]bool
+}
+
+func newConcurrencyLimits() *concurrencyLimits {
+	return &concurrencyLimits{
+		limits:     make(map[string]chan struct{}),
+		configured: make(map[string]bool),
+	}
+}
+
+// set limits a namespace to n concurrent calls; zero or less removes the
+// limit. Calls already running keep the slot they were given.
+func (l *concurrencyLimits) set(namespace string, n int, configured bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !configured && l.configured[namespace] {
+		return
+	}
+	if configured {
+		l.configured[namespace] = true
+	}
+	if n <= 0 {
+		delete(l.limits, namespace)
+		return
+	}
+	if sem, ok := l.limits[namespace]; ok && cap(sem) == n {
+		return
+	}
+	l.limits[namespace] = make(chan struct{}, n)
+}
+
+// limit returns the semaphore of the most specific limited namespace of a
+// method, or nil if there is none
+func (l *concurrencyLimits) limit(method string) chan struct{} {
+	resource := method
+	if i := strings.LastIndex(method, "."); i >= 0 {
+		resource = method[:i]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var sem chan struct{}
+	longest := -1
+	for namespace, s := range l.limits {
+		if (resource == namespace || strings.HasPrefix(resource, namespace+".")) && len(namespace) > longest {
+			sem, longest = s, len(namespace)
+		}
+	}
+	return sem
+}
+
+// acquire waits for a slot in the most specific limited namespace of a
+// method, and returns the function that frees it
+func (l *concurrencyLimits) acquire(ctx context.Context, method string) (func(), error) {
+	sem := l.limit(method)
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LimitConcurrency allows at most n calls that change something to run at
+// once in an API namespace, e.g. "sharing.nfs", which includes any namespace
+// below it. Read-only calls are not limited. Zero or less removes the limit.
+// Limits set this way take precedence over Serialize.
+func (c *Client) LimitConcurrency(namespace string, n int) {
+	c.concurrency.set(namespace, n, true)
+}
+
+// Serialize makes the calls that change something in an API namespace run
+// one at a time, for subsystems that misbehave under concurrent changes.
+// Resources call it for the namespaces they manage; it has no effect if the
+// namespace's limit was set with LimitConcurrency.
+func (c *Client) Serialize(namespace string) {
+	c.concurrency.set(namespace, 1, false)
+}
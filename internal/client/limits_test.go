@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 7; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two requests go at once, the other five are 20ms apart
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond || elapsed > time.Second {
+		t.Errorf("7 requests took %v, want about 100ms", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.wait(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() with a cancelled context = %v", err)
+	}
+
+	var unlimited *rateLimiter
+	if err := unlimited.wait(ctx); err != nil {
+		t.Errorf("wait() without a limit = %v", err)
+	}
+}
+
+func TestConcurrencyLimitMatching(t *testing.T) {
+	l := newConcurrencyLimits()
+	l.set("iscsi", 1, false)
+	l.set("iscsi.extent", 2, true)
+	l.set("vm", 3, true)
+
+	tests := []struct {
+		method string
+		want   int
+	}{
+		{"iscsi.target.create", 1},
+		{"iscsi.extent.update", 2},
+		{"vm.device.create", 3},
+		{"vmware.create", 0},
+		{"pool.dataset.create", 0},
+	}
+	for _, tt := range tests {
+		if got := cap(l.limit(tt.method)); got != tt.want {
+			t.Errorf("limit(%q) = %d, want %d", tt.method, got, tt.want)
+		}
+	}
+
+	// Serialize doesn't override a configured limit
+	l.set("vm", 1, false)
+	if n := cap(l.limits["vm"]); n != 3 {
+		t.Errorf("vm limit = %d after Serialize, want the configured 3", n)
+	}
+	l.set("iscsi", 0, true)
+	if _, ok := l.limits["iscsi"]; ok {
+		t.Error("iscsi limit not removed")
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+
+	var running, most int32
+	srv.Handle("sharing.nfs.create", func([]json.RawMessage) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return map[string]interface{}{"id": 1}, nil
+	})
+	srv.Handle("sharing.nfs.query", func([]json.RawMessage) (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return []interface{}{}, nil
+	})
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, MaxConnections: 4})
+	defer c.Close()
+	c.Serialize("sharing.nfs")
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- c.Create(ctx, "sharing.nfs", map[string]interface{}{"path": "/mnt/tank"}, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- c.Query(ctx, "sharing.nfs", nil, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := atomic.LoadInt32(&most); n != 1 {
+		t.Errorf("%d sharing.nfs.create calls ran at once, want 1", n)
+	}
+	if srv.Connections() < 2 {
+		t.Error("reads were not sent while a create was running")
+	}
+}
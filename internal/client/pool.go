@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// connSlot is one of the client's WebSocket connections. The middleware
+// answers the calls on a connection one at a time, so a slow call holds up
+// every call behind it; with Config.MaxConnections above one, calls are
+// spread over several connections instead.
+//
+// The first slot is the primary connection. Connect opens it, and the
+// client's connected state and job event subscription belong to it. The
+// other slots are opened when a call finds every open connection busy, and
+// reopened the same way after they fail, backing off if opening fails. Each
+// connection has its own reader and keepalive pings, so a connection that
+// stops answering is dropped without affecting the others.
+type connSlot struct {
+	// mu guards conn and serializes writes to it
+	mu   sync.Mutex
+	conn *websocket.Conn
+
+	// inflight counts the calls waiting for a response on this connection
+	inflight atomic.Int64
+	// up is set once a secondary connection has authenticated
+	up atomic.Bool
+
+	// opening is set while a secondary connection is being opened. retryAt
+	// and failures back off repeated failures and are only touched by the
+	// caller that set opening.
+	opening  atomic.Bool
+	retryAt  time.Time
+	failures int
+}
+
+func newConnSlots(maxConnections int) []*connSlot {
+	if maxConnections < 1 {
+		maxConnections = 1
+	}
+	slots := make([]*connSlot, maxConnections)
+	for i := range slots {
+		slots[i] = &connSlot{}
+	}
+	return slots
+}
+
+// slotKey is the context key of withSlot
+type slotKey struct{}
+
+// withSlot pins the calls made with ctx to one connection, e.g. to
+// authenticate it
+func withSlot(ctx context.Context, slot *connSlot) context.Context {
+	return context.WithValue(ctx, slotKey{}, slot)
+}
+
+// acquire picks the connection for a request and locks it for writing. The
+// caller must unlock slot.mu. conn is nil if the connection is down.
+func (c *Client) acquire(ctx context.Context) (*connSlot, *websocket.Conn) {
+	if slot, ok := ctx.Value(slotKey{}).(*connSlot); ok {
+		slot.mu.Lock()
+		return slot, slot.conn
+	}
+
+	primary := c.slots[0]
+	slot := c.pickSlot(ctx)
+	slot.mu.Lock()
+	if slot.conn == nil && slot != primary {
+		// It failed since it was picked
+		slot.mu.Unlock()
+		slot = primary
+		slot.mu.Lock()
+	}
+	return slot, slot.conn
+}
+
+// pickSlot returns the open connection with the fewest calls in flight,
+// preferring the primary. If every open connection is busy, it opens another
+// one instead.
+func (c *Client) pickSlot(ctx context.Context) *connSlot {
+	best := c.slots[0]
+	load := best.inflight.Load()
+	var closed *connSlot
+	for _, slot := range c.slots[1:] {
+		if !slot.up.Load() {
+			if closed == nil && !slot.opening.Load() {
+				closed = slot
+			}
+			continue
+		}
+		if n := slot.inflight.Load(); n < load {
+			best, load = slot, n
+		}
+	}
+	if load > 0 && closed != nil && c.openSlot(ctx, closed) {
+		return closed
+	}
+	return best
+}
+
+// openSlot opens and authenticates a secondary connection, unless another
+// call is already opening it or it failed too recently. It reports whether
+// the connection is up.
+func (c *Client) openSlot(ctx context.Context, slot *connSlot) bool {
+	if !slot.opening.CompareAndSwap(false, true) {
+		return false
+	}
+	defer slot.opening.Store(false)
+	if c.ctx.Err() != nil || time.Now().Before(slot.retryAt) {
+		return false
+	}
+
+	if err := c.connectSlot(ctx, slot); err != nil {
+		slot.retryAt = time.Now().Add(backoffDelay(slot.failures))
+		slot.failures++
+		return false
+	}
+	slot.failures = 0
+	return true
+}
+
+// connectSlot opens a secondary connection and authenticates it
+func (c *Client) connectSlot(ctx context.Context, slot *connSlot) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx, c.host())
+	if err != nil {
+		return err
+	}
+
+	slot.mu.Lock()
+	slot.conn = conn
+	slot.mu.Unlock()
+	c.startConn(conn)
+
+	if err := c.authenticate(withSlot(ctx, slot)); err != nil {
+		c.dropConnection(conn)
+		return err
+	}
+	if c.ctx.Err() != nil {
+		c.dropConnection(conn)
+		return errClientClosed
+	}
+	slot.up.Store(true)
+	return nil
+}
+
+// startConn starts the response reader and keepalive pings of a connection
+func (c *Client) startConn(conn *websocket.Conn) {
+	done := make(chan struct{})
+	c.wg.Add(2)
+	go c.readResponses(conn, done)
+	go c.keepalive(conn, done)
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+// blockingServer is a fake TrueNAS whose pool.dataset.create waits until
+// release is closed
+func blockingServer(t *testing.T) (*testserver.Server, chan struct{}) {
+	t.Helper()
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	release := make(chan struct{})
+	srv.Handle("pool.dataset.create", func([]json.RawMessage) (interface{}, error) {
+		<-release
+		return map[string]interface{}{"id": "tank/slow"}, nil
+	})
+	return srv, release
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConnectionPoolAvoidsSlowCalls(t *testing.T) {
+	srv, release := blockingServer(t)
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, MaxConnections: 3})
+	defer c.Close()
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	slow := make(chan error, 1)
+	go func() {
+		slow <- c.Create(ctx, "pool.dataset", map[string]interface{}{"name": "tank/slow"}, nil)
+	}()
+	waitFor(t, "the slow call", func() bool { return srv.CallCount("pool.dataset.create") == 1 })
+
+	// The slow call holds up the primary connection, so this one must be
+	// sent on another
+	done := make(chan error, 1)
+	go func() {
+		var groups []map[string]interface{}
+		done <- c.Query(ctx, "group", nil, &groups)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Query() waited for the slow call")
+	}
+	if n := srv.Connections(); n != 2 {
+		t.Errorf("%d connections open, want 2", n)
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Errorf("Create() error = %v", err)
+	}
+}
+
+func TestConnectionPoolLimit(t *testing.T) {
+	srv, release := blockingServer(t)
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, MaxConnections: 3})
+	defer c.Close()
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Create(ctx, "pool.dataset", map[string]interface{}{"name": "tank/slow"}, nil)
+		}()
+	}
+	waitFor(t, "three connections", func() bool { return srv.Connections() == 3 })
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := srv.Connections(); n != 3 {
+		t.Errorf("%d connections open, want 3", n)
+	}
+	if calls := srv.CallCount("pool.dataset.create"); calls != 10 {
+		t.Errorf("pool.dataset.create called %d times, want 10", calls)
+	}
+}
+
+func TestConnectionPoolReconnects(t *testing.T) {
+	srv, release := blockingServer(t)
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, MaxConnections: 2})
+	defer c.Close()
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Open the second connection, then lose both
+	slow := make(chan error, 1)
+	go func() {
+		slow <- c.Create(ctx, "pool.dataset", map[string]interface{}{"name": "tank/slow"}, nil)
+	}()
+	waitFor(t, "the slow call", func() bool { return srv.CallCount("pool.dataset.create") == 1 })
+	if err := c.Call(ctx, "core.ping", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	srv.DropConnections()
+	if err := <-slow; !IsConnectionLostError(err) {
+		t.Fatalf("Create() error = %v, want connection lost", err)
+	}
+	waitFor(t, "the client to notice", func() bool { return !c.isConnected() && !c.slots[1].up.Load() })
+
+	// Each connection comes back when it is needed
+	go func() {
+		slow <- c.Create(ctx, "pool.dataset", map[string]interface{}{"name": "tank/slow"}, nil)
+	}()
+	waitFor(t, "the slow call", func() bool { return srv.CallCount("pool.dataset.create") == 2 })
+	if err := c.Call(ctx, "core.ping", nil, nil); err != nil {
+		t.Fatalf("Call() after reconnect error = %v", err)
+	}
+	if !c.isConnected() || !c.slots[1].up.Load() {
+		t.Error("connections were not reopened")
+	}
+	close(release)
+	if err := <-slow; err != nil {
+		t.Errorf("Create() error = %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Filter operators TrueNAS query methods understand. Any operator except
+// "!=" can be negated with a "!" prefix, e.g. "!^", and the string operators
+// made case-insensitive with a "C" prefix, e.g. "C^".
+const (
+	OpEqual          = "="
+	OpNotEqual       = "!="
+	OpGreater        = ">"
+	OpGreaterOrEqual = ">="
+	OpLess           = "<"
+	OpLessOrEqual    = "<="
+	// OpRegex matches a string field against a regular expression
+	OpRegex = "~"
+	// OpIn matches if the field equals one of a list of values
+	OpIn = "in"
+	// OpNotIn matches if the field equals none of a list of values
+	OpNotIn = "nin"
+	// OpStartsWith matches a string field by prefix
+	OpStartsWith = "^"
+	// OpEndsWith matches a string field by suffix
+	OpEndsWith = "$"
+)
+
+// Filter is one entry of a query's filter list: a [field, operator, value]
+// condition made with Where, an OR made with Or, or a group made with And
+type Filter []interface{}
+
+// Where returns a condition on a field. Nested fields are written with dots,
+// e.g. "properties.used.parsed".
+func Where(field, operator string, value interface{}) Filter {
+	return Filter{field, operator, value}
+}
+
+// Or returns a filter that matches if any of the alternatives matches. Use
+// And for an alternative made of several conditions.
+func Or(alternatives ...Filter) Filter {
+	alts := make([]interface{}, len(alternatives))
+	for i, alt := range alternatives {
+		alts[i] = alt
+	}
+	return Filter{"OR", alts}
+}
+
+// And returns a group of filters that must all match, for use as an
+// alternative of Or
+func And(filters ...Filter) Filter {
+	group := make(Filter, len(filters))
+	for i, f := range filters {
+		group[i] = f
+	}
+	return group
+}
+
+// isGroup reports whether f was made by And
+func (f Filter) isGroup() bool {
+	if len(f) == 0 {
+		return false
+	}
+	_, ok := f[0].(Filter)
+	return ok
+}
+
+// queryArgs converts the parameters to the [filters, options] arguments of a
+// .query method
+func (q *QueryParams) queryArgs() []interface{} {
+	if q == nil {
+		return nil
+	}
+
+	queryOptions := map[string]interface{}{}
+	for k, v := range q.Options {
+		queryOptions[k] = v
+	}
+	if q.Limit > 0 {
+		queryOptions["limit"] = q.Limit
+	}
+	if q.Offset > 0 {
+		queryOptions["offset"] = q.Offset
+	}
+	if q.Count {
+		queryOptions["count"] = q.Count
+	}
+	if len(q.OrderBy) > 0 {
+		queryOptions["order_by"] = q.OrderBy
+	}
+	if len(q.Select) > 0 {
+		queryOptions["select"] = q.Select
+	}
+	if len(q.Extra) > 0 {
+		queryOptions["extra"] = q.Extra
+	}
+
+	var args []interface{}
+	if len(q.Filters) > 0 {
+		args = append(args, q.Filters)
+	} else {
+		args = append(args, []interface{}{})
+	}
+	if len(queryOptions) > 0 {
+		args = append(args, queryOptions)
+	}
+	return args
+}
+
+// DefaultPageSize is the number of records QueryAll fetches per call if the
+// query doesn't set a limit
+const DefaultPageSize = 100
+
+// QueryIterator pages through the results of a query:
+//
+//	it := c.QueryAll(ctx, "zfs.snapshot", params)
+//	for it.Next() {
+//		var snapshot api.SnapshotEntry
+//		if err := it.Decode(&snapshot); err != nil {
+//			return err
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type QueryIterator struct {
+	c        *Client
+	ctx      context.Context
+	resource string
+	params   QueryParams
+
+	page []json.RawMessage
+	pos  int
+	done bool
+	err  error
+}
+
+// QueryAll returns an iterator over every record matching a query, fetched
+// one page at a time with limit and offset. The query's Limit, if set, is
+// the page size and its Offset the first record. Records are ordered by id
+// unless the query sets an order, so pages don't overlap; records created or
+// deleted while iterating may still be skipped or seen twice.
+func (c *Client) QueryAll(ctx context.Context, resource string, params *QueryParams) *QueryIterator {
+	var p QueryParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageSize
+	}
+	if len(p.OrderBy) == 0 {
+		p.OrderBy = []string{"id"}
+	}
+	p.Count = false
+	return &QueryIterator{c: c, ctx: ctx, resource: resource, params: p}
+}
+
+// Next advances to the next record, fetching the next page when needed. It
+// returns false when there are no more records or a call failed; check Err.
+func (it *QueryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	if it.pos < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	var page []json.RawMessage
+	if err := it.c.Query(it.ctx, it.resource, &it.params, &page); err != nil {
+		it.err = err
+		return false
+	}
+	it.params.Offset += len(page)
+	it.done = len(page) < it.params.Limit
+	it.page, it.pos = page, 0
+	return len(page) > 0
+}
+
+// Decode unmarshals the current record into v
+func (it *QueryIterator) Decode(v interface{}) error {
+	if it.pos >= len(it.page) {
+		return errors.New("QueryIterator.Decode called without a current record")
+	}
+	return json.Unmarshal(it.page[it.pos], v)
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *QueryIterator) Err() error {
+	return it.err
+}
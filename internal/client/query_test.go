@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func TestFilterBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    string
+	}{
+		{
+			name:    "conditions",
+			filters: []Filter{Where("pool", OpEqual, "tank"), Where("name", OpStartsWith, "tank/vms")},
+			want:    `[["pool","=","tank"],["name","^","tank/vms"]]`,
+		},
+		{
+			name:    "or",
+			filters: []Filter{Or(Where("name", OpEndsWith, "@daily"), Where("name", OpRegex, "^auto-"))},
+			want:    `[["OR",[["name","$","@daily"],["name","~","^auto-"]]]]`,
+		},
+		{
+			name: "nested",
+			filters: []Filter{Or(
+				And(Where("type", OpEqual, "VOLUME"), Where("id", OpIn, []string{"tank/a", "tank/b"})),
+				Where("id", OpNotIn, []string{"tank"}),
+			)},
+			want: `[["OR",[[["type","=","VOLUME"],["id","in",["tank/a","tank/b"]]],["id","nin",["tank"]]]]]`,
+		},
+		{
+			name:    "top-level group is flattened",
+			filters: []Filter{And(Where("a", OpEqual, 1), Where("b", OpNotEqual, 2))},
+			want:    `[["a","=",1],["b","!=",2]]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := NewQueryParams().WithFilters(tt.filters...)
+			got, err := json.Marshal(params.Filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Filters = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryArgs(t *testing.T) {
+	params := NewQueryParams().
+		WithFilter("pool", "=", "tank").
+		WithOrderBy("-name").
+		WithExtra("retrieve_children", false).
+		WithExtra("properties", []string{"used"})
+	got, err := json.Marshal(params.queryArgs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[[["pool","=","tank"]],{"extra":{"properties":["used"],"retrieve_children":false},"order_by":["-name"]}]`
+	if string(got) != want {
+		t.Errorf("queryArgs() = %s, want %s", got, want)
+	}
+
+	if args := (*QueryParams)(nil).queryArgs(); args != nil {
+		t.Errorf("queryArgs() of nil params = %v, want none", args)
+	}
+}
+
+func TestQueryAll(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+	for i := 0; i < 250; i++ {
+		srv.Seed("group", map[string]interface{}{"group": fmt.Sprintf("group%03d", i), "gid": float64(1000 + i)})
+	}
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	ctx := context.Background()
+
+	t.Run("every page", func(t *testing.T) {
+		before := srv.CallCount("group.query")
+		it := c.QueryAll(ctx, "group", nil)
+		seen := map[string]bool{}
+		for it.Next() {
+			var group struct {
+				Group string `json:"group"`
+			}
+			if err := it.Decode(&group); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if seen[group.Group] {
+				t.Fatalf("%s returned twice", group.Group)
+			}
+			seen[group.Group] = true
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %v", err)
+		}
+		if len(seen) != 250 {
+			t.Errorf("got %d records, want 250", len(seen))
+		}
+		if calls := srv.CallCount("group.query") - before; calls != 3 {
+			t.Errorf("group.query called %d times, want 3 pages", calls)
+		}
+	})
+
+	t.Run("filtered with page size", func(t *testing.T) {
+		params := NewQueryParams().
+			WithFilters(Or(Where("group", OpEndsWith, "0"), Where("gid", OpLess, 1003))).
+			WithLimit(7)
+		it := c.QueryAll(ctx, "group", params)
+		n := 0
+		for it.Next() {
+			n++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %v", err)
+		}
+		// 25 names end in 0, plus group001 and group002
+		if n != 27 {
+			t.Errorf("got %d records, want 27", n)
+		}
+		if params.Offset != 0 {
+			t.Errorf("QueryAll() modified the caller's params: offset %d", params.Offset)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		srv.Handle("group.query", func([]json.RawMessage) (interface{}, error) {
+			return nil, testserver.CallError(13, "EACCES", "Not authorized")
+		})
+		it := c.QueryAll(ctx, "group", nil)
+		if it.Next() {
+			t.Fatal("Next() = true after a failed call")
+		}
+		var apiErr *APIError
+		if !errors.As(it.Err(), &apiErr) {
+			t.Errorf("Err() = %v, want an API error", it.Err())
+		}
+		if err := it.Decode(&struct{}{}); err == nil {
+			t.Error("Decode() without a record succeeded")
+		}
+	})
+}
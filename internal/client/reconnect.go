@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+const (
+	// minReconnectDelay is the backoff before the second reconnect attempt;
+	// it doubles on each further attempt up to maxReconnectDelay
+	minReconnectDelay = 500 * time.Millisecond
+
+	// maxReconnectAttempts bounds how long a call waits for the middleware
+	// to come back before giving up
+	maxReconnectAttempts = 8
+
+	// maxCallRetries is how many times a read-only call is re-sent after the
+	// connection drops mid-call
+	maxCallRetries = 3
+)
+
+// reconnect re-establishes and re-authenticates the connection, backing off
+// exponentially with jitter between attempts. Authentication failures are
+// not retried. With several hosts it keeps trying for failoverWindow, so an
+// HA failover can complete.
+func (c *Client) reconnect(ctx context.Context) error {
+	var err error
+	deadline := time.Now().Add(failoverWindow)
+	for attempt := 0; attempt < maxReconnectAttempts || (len(c.hosts) > 1 && time.Now().Before(deadline)); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.ctx.Done():
+				return errClientClosed
+			case <-time.After(backoffDelay(attempt - 1)):
+			}
+		}
+
+		err = c.Connect(ctx)
+		if err == nil || !isRetryableConnectError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// backoffDelay returns the jittered delay before reconnect attempt n+1. The
+// delay is drawn from [d/2, d) where d doubles each attempt up to
+// maxReconnectDelay.
+func backoffDelay(attempt int) time.Duration {
+	delay := maxReconnectDelay
+	if attempt < 16 {
+		if d := minReconnectDelay << attempt; d < maxReconnectDelay {
+			delay = d
+		}
+	}
+	half := delay / 2
+	return half + rand.N(half)
+}
+
+// isRetryableConnectError reports whether a Connect failure is transient
+func isRetryableConnectError(err error) bool {
+	var connErr *ConnectionError
+	var lostErr *ConnectionLostError
+	return errors.As(err, &connErr) || errors.As(err, &lostErr)
+}
+
+// isReadOnlyMethod reports whether a method has no side effects and can be
+// safely re-sent after the connection drops
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case "core.get_jobs", "core.ping":
+		return true
+	}
+	for _, suffix := range []string{".query", ".get_instance", ".config"} {
+		if strings.HasSuffix(method, suffix) {
+			return true
+		}
+	}
+	return false
+}
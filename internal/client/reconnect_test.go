@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := 0; attempt < 20; attempt++ {
+		want := minReconnectDelay << attempt
+		if attempt >= 16 || want > maxReconnectDelay {
+			want = maxReconnectDelay
+		}
+		for i := 0; i < 10; i++ {
+			got := backoffDelay(attempt)
+			if got < want/2 || got >= want {
+				t.Fatalf("backoffDelay(%d) = %v, want in [%v, %v)", attempt, got, want/2, want)
+			}
+		}
+	}
+}
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	tests := map[string]bool{
+		"pool.query":                true,
+		"pool.dataset.get_instance": true,
+		"docker.config":             true,
+		"core.get_jobs":             true,
+		"pool.create":               false,
+		"sharing.smb.update":        false,
+		"app.upgrade":               false,
+		"auth.login_ex":             false,
+	}
+	for method, want := range tests {
+		if got := isReadOnlyMethod(method); got != want {
+			t.Errorf("isReadOnlyMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestCallRetriesReadOnlyAfterConnectionLoss(t *testing.T) {
+	ts := newTestServer(t)
+
+	var queries int32
+	ts.handle("pool.query", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		if atomic.AddInt32(&queries, 1) == 1 {
+			ts.dropConnections()
+		}
+		return []map[string]interface{}{{"id": 1, "name": "tank"}}, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	var pools []map[string]interface{}
+	if err := c.Call(context.Background(), "pool.query", []interface{}{}, &pools); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if len(pools) != 1 || pools[0]["name"] != "tank" {
+		t.Errorf("Call() result = %v, want tank", pools)
+	}
+	if got := ts.callCount("auth.login_ex"); got != 2 {
+		t.Errorf("auth.login_ex called %d times, want 2 (re-authentication)", got)
+	}
+}
+
+func TestCallMutatingReturnsConnectionLost(t *testing.T) {
+	ts := newTestServer(t)
+	ts.handle("pool.create", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		ts.dropConnections()
+		return 1, nil
+	})
+	ts.handle("pool.query", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return []interface{}{}, nil
+	})
+
+	c := newTestClient(t, ts)
+
+	err := c.Call(context.Background(), "pool.create", []interface{}{map[string]interface{}{"name": "tank"}}, nil)
+	var lostErr *ConnectionLostError
+	if !errors.As(err, &lostErr) {
+		t.Fatalf("Call() error = %v, want *ConnectionLostError", err)
+	}
+	if lostErr.Method != "pool.create" {
+		t.Errorf("ConnectionLostError.Method = %q, want pool.create", lostErr.Method)
+	}
+	if got := ts.callCount("pool.create"); got != 1 {
+		t.Errorf("pool.create called %d times, want 1 (no retry)", got)
+	}
+
+	// The next call reconnects on its own
+	if err := c.Call(context.Background(), "pool.query", []interface{}{}, nil); err != nil {
+		t.Errorf("Call() after connection loss error = %v", err)
+	}
+}
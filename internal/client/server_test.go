@@ -0,0 +1,172 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// rpcHandler answers a single JSON-RPC method on the test server
+type rpcHandler func(params json.RawMessage) (interface{}, *JSONRPCError)
+
+// testServer is a minimal TrueNAS-like WebSocket JSON-RPC endpoint
+type testServer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]rpcHandler
+	calls    map[string]int
+	conns    []*websocket.Conn
+	writeMu  sync.Mutex
+
+	pings       int
+	ignorePings bool
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	ts := &testServer{
+		t:        t,
+		handlers: make(map[string]rpcHandler),
+		calls:    make(map[string]int),
+	}
+	ts.handle("auth.login_ex", func(json.RawMessage) (interface{}, *JSONRPCError) {
+		return map[string]interface{}{"response_type": "SUCCESS"}, nil
+	})
+
+	upgrader := websocket.Upgrader{}
+	ts.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		ts.mu.Lock()
+		ts.conns = append(ts.conns, conn)
+		ts.mu.Unlock()
+		ts.serve(conn)
+	}))
+	t.Cleanup(ts.server.Close)
+
+	return ts
+}
+
+// handle registers a handler for a method
+func (ts *testServer) handle(method string, h rpcHandler) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.handlers[method] = h
+}
+
+// callCount returns how many times a method has been called
+func (ts *testServer) callCount(method string) int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.calls[method]
+}
+
+// host returns the host:port clients should connect to
+func (ts *testServer) host() string {
+	return strings.TrimPrefix(ts.server.URL, "https://")
+}
+
+// notify pushes a notification to every connected client
+func (ts *testServer) notify(method string, params interface{}) {
+	ts.mu.Lock()
+	conns := append([]*websocket.Conn(nil), ts.conns...)
+	ts.mu.Unlock()
+
+	raw, _ := json.Marshal(params)
+	for _, conn := range conns {
+		ts.write(conn, &JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: raw})
+	}
+}
+
+// dropConnections closes every client connection, simulating a middleware
+// restart
+func (ts *testServer) dropConnections() {
+	ts.mu.Lock()
+	conns := ts.conns
+	ts.conns = nil
+	ts.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+func (ts *testServer) write(conn *websocket.Conn, v interface{}) {
+	ts.writeMu.Lock()
+	defer ts.writeMu.Unlock()
+	_ = conn.WriteJSON(v)
+}
+
+// pingCount returns how many WebSocket pings the server has received
+func (ts *testServer) pingCount() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.pings
+}
+
+func (ts *testServer) serve(conn *websocket.Conn) {
+	defer conn.Close()
+
+	conn.SetPingHandler(func(data string) error {
+		ts.mu.Lock()
+		ts.pings++
+		ignore := ts.ignorePings
+		ts.mu.Unlock()
+		if ignore {
+			return nil
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	for {
+		var req struct {
+			ID     int64           `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		ts.mu.Lock()
+		ts.calls[req.Method]++
+		h, ok := ts.handlers[req.Method]
+		ts.mu.Unlock()
+
+		resp := &JSONRPCResponse{JSONRPC: "2.0", ID: req.ID}
+		if !ok {
+			resp.Error = &JSONRPCError{Code: ErrCodeMethodNotFound, Message: "Method not found"}
+		} else {
+			result, rpcErr := h(req.Params)
+			if rpcErr != nil {
+				resp.Error = rpcErr
+			} else {
+				resp.Result, _ = json.Marshal(result)
+			}
+		}
+		ts.write(conn, resp)
+	}
+}
+
+// newTestClient returns a client connected to the test server
+func newTestClient(t *testing.T, ts *testServer) *Client {
+	t.Helper()
+
+	c := NewClient(&Config{
+		Host:    ts.host(),
+		APIKey:  "test-key",
+		Timeout: 5 * time.Second,
+	})
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// buildTLSConfig assembles the TLS settings for the WebSocket connection.
+//
+// With a fingerprint configured the certificate chain is not verified;
+// instead the server's leaf certificate must match the pinned SHA-256
+// fingerprint. This is intended for self-signed certificates.
+func (c *Client) buildTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: !c.verifySSL,
+		ServerName:         c.tlsServerName,
+	}
+
+	if c.caCertPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.caCertPEM)) {
+			return nil, errors.New("invalid CA certificate: no PEM certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.clientCertPEM != "" || c.clientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(c.clientCertPEM), []byte(c.clientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.tlsFingerprint != "" {
+		pin, err := ParseFingerprint(c.tlsFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			sum := sha256.Sum256(rawCerts[0])
+			if subtle.ConstantTimeCompare(sum[:], pin) != 1 {
+				return fmt.Errorf("server certificate fingerprint %s does not match the pinned fingerprint", hex.EncodeToString(sum[:]))
+			}
+			return nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// ParseFingerprint decodes a SHA-256 certificate fingerprint written as hex,
+// with or without colon separators (e.g. "AB:CD:..." or "abcd...")
+func ParseFingerprint(fingerprint string) ([]byte, error) {
+	cleaned := strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", "")
+	pin, err := hex.DecodeString(cleaned)
+	if err != nil || len(pin) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 fingerprint %q: expected %d hex-encoded bytes", fingerprint, sha256.Size)
+	}
+	return pin, nil
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFingerprint(t *testing.T) {
+	sum := sha256.Sum256([]byte("cert"))
+	plain := hex.EncodeToString(sum[:])
+
+	var pairs []string
+	for i := 0; i < len(plain); i += 2 {
+		pairs = append(pairs, strings.ToUpper(plain[i:i+2]))
+	}
+	colons := strings.Join(pairs, ":")
+
+	for _, in := range []string{plain, colons, " " + colons + " "} {
+		got, err := ParseFingerprint(in)
+		if err != nil {
+			t.Errorf("ParseFingerprint(%q) error = %v", in, err)
+			continue
+		}
+		if hex.EncodeToString(got) != plain {
+			t.Errorf("ParseFingerprint(%q) = %x, want %s", in, got, plain)
+		}
+	}
+
+	for _, in := range []string{"", "zz", plain[:10]} {
+		if _, err := ParseFingerprint(in); err == nil {
+			t.Errorf("ParseFingerprint(%q) expected error", in)
+		}
+	}
+}
+
+func TestConnectWithPinnedFingerprint(t *testing.T) {
+	ts := newTestServer(t)
+	sum := sha256.Sum256(ts.server.Certificate().Raw)
+
+	c := NewClient(&Config{
+		Host:           ts.host(),
+		APIKey:         "test-key",
+		VerifySSL:      true,
+		TLSFingerprint: hex.EncodeToString(sum[:]),
+	})
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() with matching fingerprint error = %v", err)
+	}
+
+	wrong := sha256.Sum256([]byte("other"))
+	c2 := NewClient(&Config{
+		Host:           ts.host(),
+		APIKey:         "test-key",
+		VerifySSL:      true,
+		TLSFingerprint: hex.EncodeToString(wrong[:]),
+	})
+	t.Cleanup(func() { _ = c2.Close() })
+
+	err := c2.Connect(context.Background())
+	if err == nil {
+		t.Fatal("Connect() with mismatched fingerprint expected error")
+	}
+	if !contains(err.Error(), "does not match the pinned fingerprint") {
+		t.Errorf("Connect() error = %v, want fingerprint mismatch", err)
+	}
+}
+
+func TestConnectWithCustomCA(t *testing.T) {
+	ts := newTestServer(t)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.server.Certificate().Raw})
+
+	c := NewClient(&Config{
+		Host:          ts.host(),
+		APIKey:        "test-key",
+		VerifySSL:     true,
+		CACertPEM:     string(caPEM),
+		TLSServerName: "example.com",
+	})
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() with custom CA error = %v", err)
+	}
+
+	// Without the CA the self-signed test certificate must be rejected
+	c2 := NewClient(&Config{
+		Host:      ts.host(),
+		APIKey:    "test-key",
+		VerifySSL: true,
+	})
+	t.Cleanup(func() { _ = c2.Close() })
+
+	if err := c2.Connect(context.Background()); err == nil {
+		t.Error("Connect() without CA expected certificate verification error")
+	}
+}
+
+func TestBuildTLSConfigClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateTestCertificate(t)
+
+	c := NewClient(&Config{
+		Host:          "truenas.local",
+		VerifySSL:     true,
+		ClientCertPEM: certPEM,
+		ClientKeyPEM:  keyPEM,
+	})
+	tlsConfig, err := c.buildTLSConfig()
+	if err != nil {
+		t.Fatalf("buildTLSConfig() error = %v", err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("buildTLSConfig() certificates = %d, want 1", len(tlsConfig.Certificates))
+	}
+
+	c = NewClient(&Config{
+		Host:          "truenas.local",
+		ClientCertPEM: certPEM,
+	})
+	if _, err := c.buildTLSConfig(); err == nil {
+		t.Error("buildTLSConfig() with certificate but no key expected error")
+	}
+
+	c = NewClient(&Config{
+		Host:      "truenas.local",
+		CACertPEM: "not a certificate",
+	})
+	if _, err := c.buildTLSConfig(); err == nil {
+		t.Error("buildTLSConfig() with invalid CA expected error")
+	}
+}
+
+// generateTestCertificate returns a self-signed certificate and key as PEM
+func generateTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "trueform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the client's spans
+const TracerName = "github.com/trueform/terraform-provider-trueform/internal/client"
+
+// Span attributes beyond the OpenTelemetry RPC conventions
+const (
+	attrRetries   = attribute.Key("trueform.rpc.retries")
+	attrCacheHit  = attribute.Key("trueform.cache.hit")
+	attrJobID     = attribute.Key("trueform.job.id")
+	attrJobState  = attribute.Key("trueform.job.state")
+	attrJobMethod = attribute.Key("trueform.job.method")
+)
+
+// newTracer returns the client's tracer from tp, or from the global tracer
+// provider if tp is nil. The global one does nothing unless the provider
+// set up an exporter.
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(TracerName)
+}
+
+// startCallSpan starts the span of a Call
+func (c *Client) startCallSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", method),
+		),
+	)
+}
+
+// startJobSpan starts the span of a WaitForJob
+func (c *Client) startJobSpan(ctx context.Context, jobID int64) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "WaitForJob", trace.WithAttributes(attrJobID.Int64(jobID)))
+}
+
+// endSpan records err, if any, and ends span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", apiErr.Code))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// jobAttributes describes a finished job on its span
+func jobAttributes(job map[string]interface{}) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if state, ok := job["state"].(string); ok {
+		attrs = append(attrs, attrJobState.String(state))
+	}
+	if method, ok := job["method"].(string); ok {
+		attrs = append(attrs, attrJobMethod.String(method))
+	}
+	return attrs
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+// spanAttr returns the value of a span attribute, or an empty value
+func spanAttr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, TracerProvider: tp})
+	defer c.Close()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "apply")
+	_, err := c.CreateWithJob(ctx, "pool", map[string]interface{}{
+		"name":     "tank",
+		"topology": map[string]interface{}{"data": []interface{}{map[string]interface{}{"type": "STRIPE", "disks": []string{"sdb"}}}},
+	}, 5*time.Second, nil)
+	if err != nil {
+		t.Fatalf("CreateWithJob() error = %v", err)
+	}
+	_ = c.GetInstance(ctx, "pool", 9999, nil)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	create, ok := byName["pool.create"]
+	if !ok {
+		t.Fatalf("no pool.create span in %d spans", len(spans))
+	}
+	if create.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("pool.create span is not a child of the caller's span")
+	}
+	if got := spanAttr(create, "rpc.method").AsString(); got != "pool.create" {
+		t.Errorf("rpc.method = %q", got)
+	}
+	if got := spanAttr(create, attrRetries); got.Type() != attribute.INT64 || got.AsInt64() != 0 {
+		t.Errorf("%s = %v, want 0", attrRetries, got.Emit())
+	}
+	if spanAttr(create, "rpc.jsonrpc.request_id").AsInt64() == 0 {
+		t.Error("pool.create span has no request ID")
+	}
+
+	job, ok := byName["WaitForJob"]
+	if !ok {
+		t.Fatal("no WaitForJob span")
+	}
+	if spanAttr(job, attrJobID).AsInt64() == 0 || spanAttr(job, attrJobState).AsString() != "SUCCESS" || spanAttr(job, attrJobMethod).AsString() != "pool.create" {
+		t.Errorf("WaitForJob attributes = %v", job.Attributes)
+	}
+	if job.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("WaitForJob span is not a child of the caller's span")
+	}
+	var polls int
+	for _, span := range spans {
+		if span.Name == "core.get_jobs" && span.Parent.SpanID() == job.SpanContext.SpanID() {
+			polls++
+		}
+	}
+	if polls == 0 {
+		t.Error("the job's core.get_jobs calls are not children of its span")
+	}
+
+	missing := byName["pool.get_instance"]
+	if missing.Status.Code != codes.Error || len(missing.Events) == 0 {
+		t.Errorf("pool.get_instance span status = %v, events = %v, want the error", missing.Status, missing.Events)
+	}
+}
+
+func TestTracingCacheHits(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, TracerProvider: tp, CacheTTL: time.Minute})
+	defer c.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := c.Query(ctx, "group", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var hits []bool
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "group.query" {
+			hits = append(hits, spanAttr(span, attrCacheHit).AsBool())
+		}
+	}
+	if len(hits) != 2 || hits[0] || !hits[1] {
+		t.Errorf("group.query cache hits = %v, want [false true]", hits)
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Version is a TrueNAS release number, e.g. 25.10.1. The zero Version means
+// the release couldn't be determined.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// Raw is the string TrueNAS reported, e.g. "TrueNAS-SCALE-25.04.2"
+	Raw string
+}
+
+// versionPattern finds the release number in strings such as
+// "TrueNAS-SCALE-25.04.2.1" or "TrueNAS-25.10-MASTER-20250801-123456"
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extracts the release number from a TrueNAS version string
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("unrecognized TrueNAS version %q", s)
+	}
+	v := Version{Raw: s}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// IsZero reports whether the version is unknown
+func (v Version) IsZero() bool {
+	return v.Major == 0 && v.Minor == 0 && v.Patch == 0
+}
+
+// Compare returns -1, 0 or 1 as v is older than, the same as or newer than
+// other. Raw is ignored.
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether v is major.minor or newer
+func (v Version) AtLeast(major, minor int) bool {
+	return v.Compare(Version{Major: major, Minor: minor}) >= 0
+}
+
+func (v Version) String() string {
+	if v.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capability names an API behaviour that differs between TrueNAS releases.
+// Resources check Client.Supports rather than comparing versions themselves.
+type Capability string
+
+const (
+	// CapabilityLoginEx is auth.login_ex, which replaces auth.login and
+	// auth.login_with_api_key
+	CapabilityLoginEx Capability = "auth.login_ex"
+	// CapabilitySMBShareOptions is the reworked sharing.smb entry: ro and
+	// abe are returned as readonly and access_based_share_enumeration, and
+	// hostsallow and hostsdeny moved under options
+	CapabilitySMBShareOptions Capability = "sharing.smb.options"
+	// CapabilitySMBFlagUpdate means sharing.smb.update accepts the share
+	// flags (ro, guestok, acl, ...). Later releases only take them on create.
+	CapabilitySMBFlagUpdate Capability = "sharing.smb.update.flags"
+)
+
+// versionRange is the releases a capability is available in. A zero bound
+// is open.
+type versionRange struct {
+	since Version
+	until Version // exclusive
+}
+
+// capabilities is the registry of version-dependent behaviour
+var capabilities = map[Capability]versionRange{
+	CapabilityLoginEx:         {since: Version{Major: 25, Minor: 4}},
+	CapabilitySMBShareOptions: {since: Version{Major: 25, Minor: 10}},
+	CapabilitySMBFlagUpdate:   {until: Version{Major: 25, Minor: 10}},
+}
+
+// supports reports whether a release has a capability. An unknown version is
+// treated as the newest release.
+func (r versionRange) supports(v Version) bool {
+	if v.IsZero() {
+		return r.until.IsZero()
+	}
+	if !r.since.IsZero() && v.Compare(r.since) < 0 {
+		return false
+	}
+	if !r.until.IsZero() && v.Compare(r.until) >= 0 {
+		return false
+	}
+	return true
+}
+
+// Version returns the TrueNAS release detected when the client connected
+func (c *Client) Version() Version {
+	c.versionMu.RLock()
+	defer c.versionMu.RUnlock()
+	return c.version
+}
+
+// Supports reports whether the connected TrueNAS has a capability. If the
+// version couldn't be detected the client assumes the newest release.
+func (c *Client) Supports(capability Capability) bool {
+	r, ok := capabilities[capability]
+	if !ok {
+		return false
+	}
+	return r.supports(c.Version())
+}
+
+// Capabilities returns the capabilities of the connected TrueNAS, sorted by
+// name
+func (c *Client) Capabilities() []Capability {
+	var supported []Capability
+	for capability := range capabilities {
+		if c.Supports(capability) {
+			supported = append(supported, capability)
+		}
+	}
+	sort.Slice(supported, func(i, j int) bool { return supported[i] < supported[j] })
+	return supported
+}
+
+// detectVersion asks TrueNAS for its release with system.version, falling
+// back to the version field of system.info. Failure isn't fatal: the client
+// keeps the version from an earlier connection, if any.
+func (c *Client) detectVersion(ctx context.Context) {
+	var raw string
+	if err := c.call(ctx, "system.version", []interface{}{}, &raw); err != nil {
+		var info struct {
+			Version string `json:"version"`
+		}
+		if err := c.call(ctx, "system.info", []interface{}{}, &info); err != nil {
+			return
+		}
+		raw = info.Version
+	}
+
+	v, err := ParseVersion(raw)
+	if err != nil {
+		return
+	}
+	c.versionMu.Lock()
+	c.version = v
+	c.versionMu.Unlock()
+}
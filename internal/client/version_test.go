@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Version
+		wantErr bool
+	}{
+		{raw: "TrueNAS-SCALE-25.04.2", want: Version{Major: 25, Minor: 4, Patch: 2}},
+		{raw: "TrueNAS-SCALE-24.10.2.1", want: Version{Major: 24, Minor: 10, Patch: 2}},
+		{raw: "TrueNAS-25.10.0", want: Version{Major: 25, Minor: 10}},
+		{raw: "TrueNAS-25.10-MASTER-20250801-123456", want: Version{Major: 25, Minor: 10}},
+		{raw: "25.04.0", want: Version{Major: 25, Minor: 4}},
+		{raw: "TrueNAS-SCALE-MASTER", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseVersion(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Compare(tt.want) != 0 || got.Raw != tt.raw {
+				t.Errorf("ParseVersion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := Version{Major: 25, Minor: 4, Patch: 2}
+	if !v.AtLeast(25, 4) || !v.AtLeast(24, 10) || v.AtLeast(25, 10) {
+		t.Errorf("AtLeast() wrong for %s", v)
+	}
+	if v.Compare(Version{Major: 25, Minor: 4, Patch: 3}) != -1 || v.Compare(Version{Major: 25, Minor: 4}) != 1 {
+		t.Errorf("Compare() wrong for %s", v)
+	}
+	if (Version{}).String() != "unknown" || v.String() != "25.4.2" {
+		t.Errorf("String() = %q, %q", Version{}.String(), v.String())
+	}
+}
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		version    Version
+		capability Capability
+		want       bool
+	}{
+		{Version{Major: 24, Minor: 10}, CapabilityLoginEx, false},
+		{Version{Major: 25, Minor: 4}, CapabilityLoginEx, true},
+		{Version{Major: 25, Minor: 4, Patch: 2}, CapabilitySMBShareOptions, false},
+		{Version{Major: 25, Minor: 10}, CapabilitySMBShareOptions, true},
+		{Version{Major: 25, Minor: 4, Patch: 2}, CapabilitySMBFlagUpdate, true},
+		{Version{Major: 25, Minor: 10, Patch: 1}, CapabilitySMBFlagUpdate, false},
+		{Version{Major: 26, Minor: 4}, Capability("no.such.capability"), false},
+		// An unknown version is treated as the newest release
+		{Version{}, CapabilityLoginEx, true},
+		{Version{}, CapabilitySMBShareOptions, true},
+		{Version{}, CapabilitySMBFlagUpdate, false},
+	}
+
+	for _, tt := range tests {
+		c := &Client{version: tt.version}
+		if got := c.Supports(tt.capability); got != tt.want {
+			t.Errorf("Supports(%s) on %s = %v, want %v", tt.capability, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestDetectVersion(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+	srv.SetVersion("TrueNAS-SCALE-24.10.2")
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if v := c.Version(); v.Compare(Version{Major: 24, Minor: 10, Patch: 2}) != 0 {
+		t.Fatalf("Version() = %s, want 24.10.2", v)
+	}
+	if c.Supports(CapabilityLoginEx) {
+		t.Error("Supports(CapabilityLoginEx) = true on 24.10")
+	}
+
+	// Once the version is known, a reconnect logs in with the legacy method
+	// straight away
+	srv.DropConnections()
+	if err := c.Query(ctx, "pool", nil, nil); err != nil {
+		t.Fatalf("Query() after reconnect error = %v", err)
+	}
+	if n := srv.CallCount("auth.login_ex"); n != 1 {
+		t.Errorf("auth.login_ex called %d times, want only on the first connection", n)
+	}
+	if n := srv.CallCount("auth.login_with_api_key"); n != 2 {
+		t.Errorf("auth.login_with_api_key called %d times, want 2", n)
+	}
+}
+
+func TestDetectVersionFallback(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+	srv.Handle("system.version", func([]json.RawMessage) (interface{}, error) {
+		return nil, errors.New("unavailable")
+	})
+
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if v := c.Version(); v.Raw != testserver.DefaultVersion {
+		t.Errorf("Version() = %+v, want %s from system.info", v, testserver.DefaultVersion)
+	}
+
+	// Without a version the client still connects and assumes the newest
+	// release
+	srv.Handle("system.info", func([]json.RawMessage) (interface{}, error) {
+		return nil, errors.New("unavailable")
+	})
+	c = NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() without version error = %v", err)
+	}
+	if !c.Version().IsZero() || !c.Supports(CapabilityLoginEx) {
+		t.Errorf("Version() = %s, Supports(CapabilityLoginEx) = %v", c.Version(), c.Supports(CapabilityLoginEx))
+	}
+}
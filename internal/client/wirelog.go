@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the tflog subsystem the client logs the JSON-RPC traffic
+// to at TRACE level. TF_LOG_PROVIDER_TRUEFORM_CLIENT sets its level apart
+// from the rest of the provider.
+const LogSubsystem = "trueform.client"
+
+// traceEnabled reports whether the environment asks for TRACE logs of the
+// client subsystem. Wire logging redacts every message, which is too costly
+// to do for logs that would be dropped.
+func traceEnabled() bool {
+	for _, name := range []string{"TF_LOG_PROVIDER_TRUEFORM_CLIENT", "TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := os.Getenv(name); level != "" {
+			return strings.EqualFold(level, "TRACE") || strings.EqualFold(level, "JSON")
+		}
+	}
+	return false
+}
+
+// WireLog writes a JSON Lines transcript of the API traffic, one line per
+// call and per notification, with secrets redacted like in cassettes. It is
+// meant to be attached to bug reports. The client it is given to closes it
+// when the client is closed.
+type WireLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closed bool
+}
+
+// WireLogEntry is one line of a wire log
+type WireLogEntry struct {
+	Time time.Time `json:"time"`
+	// ID is the JSON-RPC request ID; notifications have none
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *JSONRPCError   `json:"error,omitempty"`
+	// TransportError is set if no response arrived, e.g. on a timeout
+	TransportError string  `json:"transport_error,omitempty"`
+	DurationMS     float64 `json:"duration_ms,omitempty"`
+	Notification   bool    `json:"notification,omitempty"`
+}
+
+// NewWireLog returns a WireLog writing to w
+func NewWireLog(w io.Writer) *WireLog {
+	return &WireLog{w: w}
+}
+
+// OpenWireLog returns a WireLog appending to the file at path. The file is
+// readable only by its owner, since API results can hold more than secrets.
+func OpenWireLog(path string) (*WireLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wire log: %w", err)
+	}
+	return NewWireLog(f), nil
+}
+
+// write appends an entry; errors are ignored so logging never fails a call
+func (l *WireLog) write(entry *WireLogEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	_, _ = l.w.Write(append(line, '\n'))
+}
+
+// Close flushes the wire log to disk and closes its file, if it writes to
+// one. Entries written afterwards are dropped. Closing it again does nothing.
+func (l *WireLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	var err error
+	if f, ok := l.w.(*os.File); ok {
+		err = f.Sync()
+	}
+	if closer, ok := l.w.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// logRequest logs a request about to be sent
+func (c *Client) logRequest(ctx context.Context, req *JSONRPCRequest) {
+	if !c.traceWire {
+		return
+	}
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_TRUEFORM_CLIENT"))
+	tflog.SubsystemTrace(ctx, LogSubsystem, "JSON-RPC request", map[string]interface{}{
+		"rpc_id":     req.ID,
+		"rpc_method": req.Method,
+		"params":     string(redactParams(req.Method, req.Params)),
+	})
+}
+
+// logResponse logs the outcome of a call, to tflog and the wire log
+func (c *Client) logResponse(ctx context.Context, req *JSONRPCRequest, resp *JSONRPCResponse, err error, duration time.Duration) {
+	if !c.traceWire && c.wireLog == nil {
+		return
+	}
+
+	entry := &WireLogEntry{
+		Time:       time.Now().UTC(),
+		ID:         req.ID,
+		Method:     req.Method,
+		DurationMS: float64(duration.Microseconds()) / 1000,
+	}
+	if err != nil {
+		entry.TransportError = err.Error()
+	} else {
+		entry.Result = redactJSON(resp.Result)
+		if resp.Error != nil {
+			entry.Error = &JSONRPCError{
+				Code:    resp.Error.Code,
+				Message: resp.Error.Message,
+				Data:    redactJSON(resp.Error.Data),
+			}
+		}
+	}
+
+	if c.wireLog != nil {
+		entry.Params = redactParams(req.Method, req.Params)
+		c.wireLog.write(entry)
+	}
+	if !c.traceWire {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"rpc_id":      req.ID,
+		"rpc_method":  req.Method,
+		"duration_ms": entry.DurationMS,
+	}
+	switch {
+	case entry.TransportError != "":
+		fields["error"] = entry.TransportError
+	case entry.Error != nil:
+		data, _ := json.Marshal(entry.Error)
+		fields["error"] = string(data)
+	default:
+		fields["result"] = string(entry.Result)
+	}
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_TRUEFORM_CLIENT"))
+	tflog.SubsystemTrace(ctx, LogSubsystem, "JSON-RPC response", fields)
+}
+
+// logNotification adds a server-pushed notification to the wire log. It
+// isn't sent to tflog, since notifications arrive outside of any request's
+// context.
+func (c *Client) logNotification(msg *JSONRPCNotification) {
+	if c.wireLog == nil {
+		return
+	}
+	c.wireLog.write(&WireLogEntry{
+		Time:         time.Now().UTC(),
+		Method:       msg.Method,
+		Params:       redactJSON(msg.Params),
+		Notification: true,
+	})
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func TestWireLog(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+
+	var transcript bytes.Buffer
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, WireLog: NewWireLog(&transcript)})
+	defer c.Close()
+	ctx := context.Background()
+
+	err := c.Create(ctx, "user", map[string]interface{}{
+		"username":     "alice",
+		"full_name":    "Alice",
+		"password":     "hunter2",
+		"group_create": true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	_ = c.GetInstance(ctx, "user", 9999, nil)
+
+	if strings.Contains(transcript.String(), testserver.APIKey) || strings.Contains(transcript.String(), "hunter2") {
+		t.Fatalf("wire log contains a secret:\n%s", transcript.String())
+	}
+
+	var entries []WireLogEntry
+	scanner := bufio.NewScanner(&transcript)
+	for scanner.Scan() {
+		var entry WireLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid wire log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	byMethod := map[string]WireLogEntry{}
+	for _, entry := range entries {
+		byMethod[entry.Method] = entry
+	}
+
+	login, ok := byMethod["auth.login_ex"]
+	if !ok || !strings.Contains(string(login.Params), redacted) {
+		t.Errorf("auth.login_ex entry = %+v, want redacted params", login)
+	}
+	create, ok := byMethod["user.create"]
+	if !ok {
+		t.Fatal("no user.create entry")
+	}
+	if create.ID == 0 || create.DurationMS <= 0 || !strings.Contains(string(create.Params), `"full_name":"Alice"`) || len(create.Result) == 0 {
+		t.Errorf("user.create entry = %+v", create)
+	}
+	if missing := byMethod["user.get_instance"]; missing.Error == nil {
+		t.Errorf("user.get_instance entry = %+v, want the error", missing)
+	}
+}
+
+func TestWireLogClose(t *testing.T) {
+	srv := testserver.New()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "wire.jsonl")
+	wireLog, err := OpenWireLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second, WireLog: wireLog})
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, _ := wireLog.w.(*os.File)
+	if _, err := f.Write([]byte("\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("writing to the file after Close() = %v, want os.ErrClosed", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"method":"auth.login_ex"`) {
+		t.Errorf("wire log = %q, want the login", data)
+	}
+
+	// Later entries are dropped and closing again is harmless
+	wireLog.write(&WireLogEntry{Method: "system.info"})
+	if err := wireLog.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestWireTraceLogging(t *testing.T) {
+	t.Setenv("TF_LOG", "TRACE")
+	srv := testserver.New()
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+	c := NewClient(&Config{Host: srv.Host(), APIKey: testserver.APIKey, Timeout: 5 * time.Second})
+	defer c.Close()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	err := c.Create(ctx, "user", map[string]interface{}{
+		"username":     "bob",
+		"full_name":    "Bob",
+		"password":     "hunter2",
+		"group_create": true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	messages, err := tflogtest.MultilineJSONDecode(&logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests, responses int
+	for _, msg := range messages {
+		if msg["@module"] != "provider."+LogSubsystem {
+			continue
+		}
+		line, _ := json.Marshal(msg)
+		if strings.Contains(string(line), "hunter2") || strings.Contains(string(line), testserver.APIKey) {
+			t.Errorf("log message contains a secret: %s", line)
+		}
+		if msg["rpc_method"] != "user.create" {
+			continue
+		}
+		switch msg["@message"] {
+		case "JSON-RPC request":
+			requests++
+			if _, ok := msg["rpc_id"]; !ok {
+				t.Errorf("request has no rpc_id: %s", line)
+			}
+		case "JSON-RPC response":
+			responses++
+			if _, ok := msg["duration_ms"]; !ok {
+				t.Errorf("response has no duration_ms: %s", line)
+			}
+		}
+	}
+	if requests != 1 || responses != 1 {
+		t.Errorf("logged %d requests and %d responses for user.create, want 1 each", requests, responses)
+	}
+}
+
+func TestTraceEnabled(t *testing.T) {
+	tests := []struct {
+		tfLog, provider, subsystem string
+		want                       bool
+	}{
+		{"", "", "", false},
+		{"TRACE", "", "", true},
+		{"json", "", "", true},
+		{"DEBUG", "", "", false},
+		{"DEBUG", "", "TRACE", true},
+		{"TRACE", "INFO", "", false},
+		{"TRACE", "", "OFF", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("TF_LOG", tt.tfLog)
+		t.Setenv("TF_LOG_PROVIDER", tt.provider)
+		t.Setenv("TF_LOG_PROVIDER_TRUEFORM_CLIENT", tt.subsystem)
+		if got := traceEnabled(); got != tt.want {
+			t.Errorf("traceEnabled() with TF_LOG=%q TF_LOG_PROVIDER=%q TF_LOG_PROVIDER_TRUEFORM_CLIENT=%q = %v, want %v",
+				tt.tfLog, tt.provider, tt.subsystem, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -121,19 +122,19 @@ func (d *DatasetDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	var result map[string]interface{}
+	var result api.DatasetEntry
 	err := d.client.GetInstance(ctx, "pool.dataset", config.ID.ValueString(), &result)
 	if err != nil {
 		resp.Diagnostics.AddError("Error Reading Dataset", "Could not read dataset: "+err.Error())
 		return
 	}
 
-	config.ID = types.StringValue(result["id"].(string))
-	config.Name = types.StringValue(result["name"].(string))
-	config.Type = types.StringValue(result["type"].(string))
+	config.ID = types.StringValue(result.ID)
+	config.Name = types.StringValue(result.Name)
+	config.Type = types.StringValue(result.Type)
 
 	// Extract pool from name
-	name := result["name"].(string)
+	name := result.Name
 	for i, c := range name {
 		if c == '/' {
 			config.Pool = types.StringValue(name[:i])
@@ -144,44 +145,32 @@ func (d *DatasetDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		config.Pool = types.StringValue(name)
 	}
 
-	if compression, ok := result["compression"].(map[string]interface{}); ok {
-		if value, ok := compression["value"].(string); ok {
-			config.Compression = types.StringValue(value)
-		}
+	if value, ok := result.Compression.Text(); ok {
+		config.Compression = types.StringValue(value)
 	}
-	if atime, ok := result["atime"].(map[string]interface{}); ok {
-		if value, ok := atime["value"].(string); ok {
-			config.Atime = types.StringValue(value)
-		}
+	if value, ok := result.Atime.Text(); ok {
+		config.Atime = types.StringValue(value)
 	}
-	if dedup, ok := result["deduplication"].(map[string]interface{}); ok {
-		if value, ok := dedup["value"].(string); ok {
-			config.Deduplication = types.StringValue(value)
-		}
+	if value, ok := result.Deduplication.Text(); ok {
+		config.Deduplication = types.StringValue(value)
 	}
-	if quota, ok := result["quota"].(map[string]interface{}); ok {
-		if parsed, ok := quota["parsed"].(float64); ok {
-			config.Quota = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Quota.Int(); ok {
+		config.Quota = types.Int64Value(parsed)
 	}
-	if used, ok := result["used"].(map[string]interface{}); ok {
-		if parsed, ok := used["parsed"].(float64); ok {
-			config.Used = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Used.Int(); ok {
+		config.Used = types.Int64Value(parsed)
 	}
-	if available, ok := result["available"].(map[string]interface{}); ok {
-		if parsed, ok := available["parsed"].(float64); ok {
-			config.Available = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Available.Int(); ok {
+		config.Available = types.Int64Value(parsed)
 	}
-	if mountpoint, ok := result["mountpoint"].(string); ok {
-		config.Mountpoint = types.StringValue(mountpoint)
+	if result.Mountpoint != nil {
+		config.Mountpoint = types.StringValue(*result.Mountpoint)
 	}
-	if encrypted, ok := result["encrypted"].(bool); ok {
-		config.Encrypted = types.BoolValue(encrypted)
+	if result.Encrypted != nil {
+		config.Encrypted = types.BoolValue(*result.Encrypted)
 	}
-	if keyLoaded, ok := result["key_loaded"].(bool); ok {
-		config.KeyLoaded = types.BoolValue(keyLoaded)
+	if result.KeyLoaded != nil {
+		config.KeyLoaded = types.BoolValue(*result.KeyLoaded)
 	}
 
 	diags = resp.State.Set(ctx, &config)
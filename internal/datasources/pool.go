@@ -3,11 +3,13 @@ package datasources
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -103,7 +105,7 @@ func (d *PoolDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	var result map[string]interface{}
+	var result api.PoolEntry
 	var err error
 
 	if !config.ID.IsNull() {
@@ -111,7 +113,7 @@ func (d *PoolDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	} else if !config.Name.IsNull() {
 		// Query by name
 		params := client.NewQueryParams().WithFilter("name", "=", config.Name.ValueString())
-		var results []map[string]interface{}
+		var results []api.PoolEntry
 		err = d.client.Query(ctx, "pool", params, &results)
 		if err == nil && len(results) > 0 {
 			result = results[0]
@@ -129,23 +131,29 @@ func (d *PoolDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	config.ID = types.Int64Value(int64(result["id"].(float64)))
-	config.Name = types.StringValue(result["name"].(string))
-	config.Status = types.StringValue(result["status"].(string))
-	config.Healthy = types.BoolValue(result["healthy"].(bool))
-	config.Path = types.StringValue(result["path"].(string))
+	config.ID = types.Int64Value(result.ID)
+	config.Name = types.StringValue(result.Name)
+	config.Status = types.StringValue(result.Status)
+	config.Healthy = types.BoolValue(result.Healthy)
+	config.Path = types.StringValue(result.Path)
 
-	if size, ok := result["size"].(float64); ok {
-		config.Size = types.Int64Value(int64(size))
+	if result.Size != nil {
+		config.Size = types.Int64Value(*result.Size)
 	}
-	if free, ok := result["free"].(float64); ok {
-		config.Free = types.Int64Value(int64(free))
+	if result.Free != nil {
+		config.Free = types.Int64Value(*result.Free)
 	}
-	if allocated, ok := result["allocated"].(float64); ok {
-		config.Allocated = types.Int64Value(int64(allocated))
+	if result.Allocated != nil {
+		config.Allocated = types.Int64Value(*result.Allocated)
 	}
-	if fragmentation, ok := result["fragmentation"].(float64); ok {
+	// fragmentation is a percentage, returned as a string by recent releases
+	switch fragmentation := result.Fragmentation.(type) {
+	case float64:
 		config.Fragmentation = types.Int64Value(int64(fragmentation))
+	case string:
+		if parsed, err := strconv.ParseInt(fragmentation, 10, 64); err == nil {
+			config.Fragmentation = types.Int64Value(parsed)
+		}
 	}
 
 	diags = resp.State.Set(ctx, &config)
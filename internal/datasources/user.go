@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -119,14 +120,14 @@ func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	var result map[string]interface{}
+	var result api.UserEntry
 	var err error
 
 	if !config.ID.IsNull() {
 		err = d.client.GetInstance(ctx, "user", config.ID.ValueInt64(), &result)
 	} else if !config.Username.IsNull() {
 		params := client.NewQueryParams().WithFilter("username", "=", config.Username.ValueString())
-		var results []map[string]interface{}
+		var results []api.UserEntry
 		err = d.client.Query(ctx, "user", params, &results)
 		if err == nil && len(results) > 0 {
 			result = results[0]
@@ -136,7 +137,7 @@ func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		}
 	} else if !config.UID.IsNull() {
 		params := client.NewQueryParams().WithFilter("uid", "=", config.UID.ValueInt64())
-		var results []map[string]interface{}
+		var results []api.UserEntry
 		err = d.client.Query(ctx, "user", params, &results)
 		if err == nil && len(results) > 0 {
 			result = results[0]
@@ -154,38 +155,33 @@ func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	config.ID = types.Int64Value(int64(result["id"].(float64)))
-	config.UID = types.Int64Value(int64(result["uid"].(float64)))
-	config.Username = types.StringValue(result["username"].(string))
+	config.ID = types.Int64Value(result.ID)
+	config.UID = types.Int64Value(result.UID)
+	config.Username = types.StringValue(result.Username)
 
-	if fullName, ok := result["full_name"].(string); ok {
-		config.FullName = types.StringValue(fullName)
+	if result.FullName != nil {
+		config.FullName = types.StringValue(*result.FullName)
 	}
-	if email, ok := result["email"].(string); ok {
-		config.Email = types.StringValue(email)
+	if result.Email != nil {
+		config.Email = types.StringValue(*result.Email)
 	}
-	if group, ok := result["group"].(map[string]interface{}); ok {
-		if gid, ok := group["id"].(float64); ok {
-			config.Group = types.Int64Value(int64(gid))
-		}
-	}
-	if home, ok := result["home"].(string); ok {
-		config.Home = types.StringValue(home)
+	if result.Group != nil {
+		config.Group = types.Int64Value(result.Group.ID)
 	}
-	if shell, ok := result["shell"].(string); ok {
-		config.Shell = types.StringValue(shell)
+	if result.Home != nil {
+		config.Home = types.StringValue(*result.Home)
 	}
-	if locked, ok := result["locked"].(bool); ok {
-		config.Locked = types.BoolValue(locked)
+	if result.Shell != nil {
+		config.Shell = types.StringValue(*result.Shell)
 	}
-	if smb, ok := result["smb"].(bool); ok {
-		config.SMB = types.BoolValue(smb)
+	if result.Locked != nil {
+		config.Locked = types.BoolValue(*result.Locked)
 	}
-	if sudo, ok := result["sudo"].(bool); ok {
-		config.Sudo = types.BoolValue(sudo)
+	if result.SMB != nil {
+		config.SMB = types.BoolValue(*result.SMB)
 	}
-	if builtin, ok := result["builtin"].(bool); ok {
-		config.Builtin = types.BoolValue(builtin)
+	if result.Builtin != nil {
+		config.Builtin = types.BoolValue(*result.Builtin)
 	}
 
 	diags = resp.State.Set(ctx, &config)
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -113,14 +114,14 @@ func (d *VMDataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		return
 	}
 
-	var result map[string]interface{}
+	var result api.VMEntry
 	var err error
 
 	if !config.ID.IsNull() {
 		err = d.client.GetInstance(ctx, "vm", config.ID.ValueInt64(), &result)
 	} else if !config.Name.IsNull() {
 		params := client.NewQueryParams().WithFilter("name", "=", config.Name.ValueString())
-		var results []map[string]interface{}
+		var results []api.VMEntry
 		err = d.client.Query(ctx, "vm", params, &results)
 		if err == nil && len(results) > 0 {
 			result = results[0]
@@ -138,37 +139,35 @@ func (d *VMDataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		return
 	}
 
-	config.ID = types.Int64Value(int64(result["id"].(float64)))
-	config.Name = types.StringValue(result["name"].(string))
+	config.ID = types.Int64Value(result.ID)
+	config.Name = types.StringValue(result.Name)
 
-	if description, ok := result["description"].(string); ok {
-		config.Description = types.StringValue(description)
+	if result.Description != nil {
+		config.Description = types.StringValue(*result.Description)
 	}
-	if vcpus, ok := result["vcpus"].(float64); ok {
-		config.VCPUs = types.Int64Value(int64(vcpus))
+	if result.Vcpus != nil {
+		config.VCPUs = types.Int64Value(*result.Vcpus)
 	}
-	if cores, ok := result["cores"].(float64); ok {
-		config.Cores = types.Int64Value(int64(cores))
+	if result.Cores != nil {
+		config.Cores = types.Int64Value(*result.Cores)
 	}
-	if threads, ok := result["threads"].(float64); ok {
-		config.Threads = types.Int64Value(int64(threads))
+	if result.Threads != nil {
+		config.Threads = types.Int64Value(*result.Threads)
 	}
-	if memory, ok := result["memory"].(float64); ok {
-		config.Memory = types.Int64Value(int64(memory))
+	if result.Memory != nil {
+		config.Memory = types.Int64Value(*result.Memory)
 	}
-	if bootloader, ok := result["bootloader"].(string); ok {
-		config.Bootloader = types.StringValue(bootloader)
+	if result.Bootloader != nil {
+		config.Bootloader = types.StringValue(*result.Bootloader)
 	}
-	if autostart, ok := result["autostart"].(bool); ok {
-		config.Autostart = types.BoolValue(autostart)
+	if result.Autostart != nil {
+		config.Autostart = types.BoolValue(*result.Autostart)
 	}
-	if status, ok := result["status"].(map[string]interface{}); ok {
-		if state, ok := status["state"].(string); ok {
-			config.Status = types.StringValue(state)
-		}
+	if result.Status != nil {
+		config.Status = types.StringValue(result.Status.State)
 	}
-	if uuid, ok := result["uuid"].(string); ok {
-		config.UUID = types.StringValue(uuid)
+	if result.UUID != nil {
+		config.UUID = types.StringValue(*result.UUID)
 	}
 
 	diags = resp.State.Set(ctx, &config)
@@ -0,0 +1,169 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// cronSchedule is the schedule object of trueform_cronjob
+type cronSchedule struct {
+	Minute string `tfsdk:"minute"`
+	Hour   string `tfsdk:"hour"`
+	Dom    string `tfsdk:"dom"`
+	Month  string `tfsdk:"month"`
+	Dow    string `tfsdk:"dow"`
+}
+
+// cronScheduleTypes are the attributes of cronSchedule
+var cronScheduleTypes = map[string]attr.Type{
+	"minute": types.StringType,
+	"hour":   types.StringType,
+	"dom":    types.StringType,
+	"month":  types.StringType,
+	"dow":    types.StringType,
+}
+
+// cronMacros are the shorthands cron accepts for common schedules
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes the values a field of a cron expression takes
+type cronField struct {
+	name     string
+	min, max int
+	// names are the values that may be given by name, e.g. jan, starting
+	// at min
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is Sunday, as is 0
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// parseCron splits a five-field cron expression, or a macro such as @daily,
+// into a schedule. Each field is checked, so a typo fails at plan time
+// rather than when TrueNAS validates the cron job.
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%q has %d fields, want 5: minute, hour, day of month, month and day of week", expr, len(fields))
+	}
+	for i, field := range fields {
+		fields[i] = strings.ToLower(field)
+		if err := cronFields[i].check(fields[i]); err != nil {
+			return nil, fmt.Errorf("%q: %w", expr, err)
+		}
+	}
+	return &cronSchedule{Minute: fields[0], Hour: fields[1], Dom: fields[2], Month: fields[3], Dow: fields[4]}, nil
+}
+
+// check validates one field: a comma-separated list of *, values and
+// ranges, each optionally with a /step
+func (f cronField) check(field string) error {
+	for _, item := range strings.Split(field, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q in %s %q", step, f.name, field)
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rng, "-")
+		if !isRange {
+			hi = lo
+		}
+		from, err := f.value(lo)
+		if err != nil {
+			return err
+		}
+		to, err := f.value(hi)
+		if err != nil {
+			return err
+		}
+		if from > to {
+			return fmt.Errorf("%s range %q is backwards", f.name, rng)
+		}
+	}
+	return nil
+}
+
+// value parses a value of the field, by number or name
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if s == name {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s %q is not between %d and %d", f.name, s, f.min, f.max)
+	}
+	return n, nil
+}
+
+var _ function.Function = &ParseCronFunction{}
+
+func NewParseCronFunction() function.Function {
+	return &ParseCronFunction{}
+}
+
+// ParseCronFunction implements parse_cron
+type ParseCronFunction struct{}
+
+func (f *ParseCronFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_cron"
+}
+
+func (f *ParseCronFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Convert a cron expression to a schedule",
+		Description: "Converts a five-field cron expression such as \"0 3 * * 1\", or a macro such as \"@daily\", to the schedule object of trueform_cronjob, " +
+			"with the attributes minute, hour, dom, month and dow. Each field is checked, so mistakes fail at plan time.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "expression",
+				Description: "The cron expression: minute, hour, day of month, month and day of week, separated by spaces.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: cronScheduleTypes},
+	}
+}
+
+func (f *ParseCronFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var expr string
+	resp.Error = req.Arguments.Get(ctx, &expr)
+	if resp.Error != nil {
+		return
+	}
+
+	schedule, err := parseCron(expr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, schedule)
+}
@@ -0,0 +1,62 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    cronSchedule
+		wantErr bool
+	}{
+		{expr: "0 3 * * 1", want: cronSchedule{Minute: "0", Hour: "3", Dom: "*", Month: "*", Dow: "1"}},
+		{expr: "*/15 8-18 * * mon-fri", want: cronSchedule{Minute: "*/15", Hour: "8-18", Dom: "*", Month: "*", Dow: "mon-fri"}},
+		{expr: "30 2 1,15 JAN,jul 0", want: cronSchedule{Minute: "30", Hour: "2", Dom: "1,15", Month: "jan,jul", Dow: "0"}},
+		{expr: "0 0 * * 7", want: cronSchedule{Minute: "0", Hour: "0", Dom: "*", Month: "*", Dow: "7"}},
+		{expr: "@daily", want: cronSchedule{Minute: "0", Hour: "0", Dom: "*", Month: "*", Dow: "*"}},
+		{expr: " @Weekly ", want: cronSchedule{Minute: "0", Hour: "0", Dom: "*", Month: "*", Dow: "0"}},
+		{expr: "0 3 * *", wantErr: true},
+		{expr: "0 3 * * 1 2", wantErr: true},
+		{expr: "60 3 * * 1", wantErr: true},
+		{expr: "0 24 * * 1", wantErr: true},
+		{expr: "0 3 0 * *", wantErr: true},
+		{expr: "0 3 * 13 *", wantErr: true},
+		{expr: "0 3 * * 8", wantErr: true},
+		{expr: "0 18-8 * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "0 3 * * monday", wantErr: true},
+		{expr: "@reboot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCron(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCron(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && *got != tt.want {
+			t.Errorf("parseCron(%q) = %+v, want %+v", tt.expr, *got, tt.want)
+		}
+	}
+}
+
+func TestParseCronFunction(t *testing.T) {
+	result, err := runFunction(NewParseCronFunction(), types.StringValue("0 3 * * 1"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := types.ObjectValueMust(cronScheduleTypes, map[string]attr.Value{
+		"minute": types.StringValue("0"),
+		"hour":   types.StringValue("3"),
+		"dom":    types.StringValue("*"),
+		"month":  types.StringValue("*"),
+		"dow":    types.StringValue("1"),
+	})
+	if !result.Equal(want) {
+		t.Errorf("Run() = %v, want %v", result, want)
+	}
+}
@@ -0,0 +1,146 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// datasetPath joins a pool and a dataset name below it, which may itself
+// have several components, e.g. "tank" and "apps/nextcloud"
+func datasetPath(pool, name string) (string, error) {
+	pool = strings.Trim(pool, "/")
+	name = strings.Trim(name, "/")
+	if pool == "" || strings.Contains(pool, "/") {
+		return "", fmt.Errorf("%q is not a pool name", pool)
+	}
+	if name == "" {
+		return "", errors.New("the dataset name is empty")
+	}
+	for _, component := range strings.Split(name, "/") {
+		if component == "" {
+			return "", fmt.Errorf("%q has an empty component", name)
+		}
+		if strings.ContainsAny(component, "@#") {
+			return "", fmt.Errorf("%q contains @ or #, which name snapshots and bookmarks", name)
+		}
+	}
+	return pool + "/" + name, nil
+}
+
+// snapshotID is what parse_snapshot_id returns
+type snapshotID struct {
+	Pool    string `tfsdk:"pool"`
+	Dataset string `tfsdk:"dataset"`
+	Name    string `tfsdk:"name"`
+}
+
+// snapshotIDTypes are the attributes of snapshotID
+var snapshotIDTypes = map[string]attr.Type{
+	"pool":    types.StringType,
+	"dataset": types.StringType,
+	"name":    types.StringType,
+}
+
+// parseSnapshotID splits a snapshot ID such as "tank/apps@daily-2025-01-01"
+func parseSnapshotID(id string) (*snapshotID, error) {
+	dataset, name, ok := strings.Cut(id, "@")
+	if !ok || dataset == "" || name == "" || strings.Contains(name, "@") {
+		return nil, fmt.Errorf("%q is not a snapshot ID of the form pool/dataset@name", id)
+	}
+	pool, _, _ := strings.Cut(dataset, "/")
+	return &snapshotID{Pool: pool, Dataset: dataset, Name: name}, nil
+}
+
+var _ function.Function = &DatasetPathFunction{}
+
+func NewDatasetPathFunction() function.Function {
+	return &DatasetPathFunction{}
+}
+
+// DatasetPathFunction implements dataset_path
+type DatasetPathFunction struct{}
+
+func (f *DatasetPathFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dataset_path"
+}
+
+func (f *DatasetPathFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build the full name of a dataset",
+		Description: "Joins a pool name and a dataset name below it, e.g. \"tank\" and \"apps/nextcloud\" to \"tank/apps/nextcloud\". Leading and trailing slashes are ignored.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "pool",
+				Description: "The name of the pool.",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "The dataset's path below the pool, e.g. \"apps/nextcloud\".",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DatasetPathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var pool, name string
+	resp.Error = req.Arguments.Get(ctx, &pool, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	path, err := datasetPath(pool, name)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, path)
+}
+
+var _ function.Function = &ParseSnapshotIDFunction{}
+
+func NewParseSnapshotIDFunction() function.Function {
+	return &ParseSnapshotIDFunction{}
+}
+
+// ParseSnapshotIDFunction implements parse_snapshot_id
+type ParseSnapshotIDFunction struct{}
+
+func (f *ParseSnapshotIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_snapshot_id"
+}
+
+func (f *ParseSnapshotIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Split a snapshot ID into its parts",
+		Description: "Splits a snapshot ID such as \"tank/apps@daily\", e.g. the id of a trueform_snapshot, into an object of its pool (\"tank\"), dataset (\"tank/apps\") and name (\"daily\").",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "id",
+				Description: "The snapshot ID, of the form pool/dataset@name.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: snapshotIDTypes},
+	}
+}
+
+func (f *ParseSnapshotIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = req.Arguments.Get(ctx, &id)
+	if resp.Error != nil {
+		return
+	}
+
+	snapshot, err := parseSnapshotID(id)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, snapshot)
+}
@@ -0,0 +1,78 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDatasetPath(t *testing.T) {
+	tests := []struct {
+		pool, name string
+		want       string
+		wantErr    bool
+	}{
+		{pool: "tank", name: "apps", want: "tank/apps"},
+		{pool: "tank", name: "apps/nextcloud", want: "tank/apps/nextcloud"},
+		{pool: "/tank/", name: "/apps/", want: "tank/apps"},
+		{pool: "", name: "apps", wantErr: true},
+		{pool: "tank/apps", name: "nextcloud", wantErr: true},
+		{pool: "tank", name: "", wantErr: true},
+		{pool: "tank", name: "apps//nextcloud", wantErr: true},
+		{pool: "tank", name: "apps@daily", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := datasetPath(tt.pool, tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("datasetPath(%q, %q) error = %v, wantErr %v", tt.pool, tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("datasetPath(%q, %q) = %q, want %q", tt.pool, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSnapshotID(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    snapshotID
+		wantErr bool
+	}{
+		{id: "tank/apps@daily", want: snapshotID{Pool: "tank", Dataset: "tank/apps", Name: "daily"}},
+		{id: "tank@before-upgrade", want: snapshotID{Pool: "tank", Dataset: "tank", Name: "before-upgrade"}},
+		{id: "tank/a/b@auto-2025-01-01_00-00", want: snapshotID{Pool: "tank", Dataset: "tank/a/b", Name: "auto-2025-01-01_00-00"}},
+		{id: "tank/apps", wantErr: true},
+		{id: "@daily", wantErr: true},
+		{id: "tank/apps@", wantErr: true},
+		{id: "tank@a@b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSnapshotID(tt.id)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSnapshotID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && *got != tt.want {
+			t.Errorf("parseSnapshotID(%q) = %+v, want %+v", tt.id, *got, tt.want)
+		}
+	}
+}
+
+func TestParseSnapshotIDFunction(t *testing.T) {
+	result, err := runFunction(NewParseSnapshotIDFunction(), types.StringValue("tank/apps@daily"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := types.ObjectValueMust(snapshotIDTypes, map[string]attr.Value{
+		"pool":    types.StringValue("tank"),
+		"dataset": types.StringValue("tank/apps"),
+		"name":    types.StringValue("daily"),
+	})
+	if !result.Equal(want) {
+		t.Errorf("Run() = %v, want %v", result, want)
+	}
+}
@@ -0,0 +1,24 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// runFunction calls a function the way Terraform does and returns its
+// result
+func runFunction(f function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	ctx := context.Background()
+	def := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, def)
+
+	result, err := def.Definition.Return.NewResultData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	return resp.Result.Value(), resp.Error
+}
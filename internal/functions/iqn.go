@@ -0,0 +1,77 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	// iqnBasenamePattern matches an iSCSI qualified name without a target:
+	// iqn., the year and month the naming authority registered its domain,
+	// and the domain reversed
+	iqnBasenamePattern = regexp.MustCompile(`^iqn\.[0-9]{4}-(0[1-9]|1[0-2])\.[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[a-z0-9.:-]+)?$`)
+
+	// iqnTargetPattern matches the names TrueNAS accepts for targets
+	iqnTargetPattern = regexp.MustCompile(`^[a-z0-9.:-]+$`)
+)
+
+// iqn returns the IQN of a target below a basename, as initiators see it
+func iqn(basename, target string) (string, error) {
+	if !iqnBasenamePattern.MatchString(basename) {
+		return "", fmt.Errorf("%q is not an IQN basename such as iqn.2005-10.org.freenas.ctl", basename)
+	}
+	if !iqnTargetPattern.MatchString(target) {
+		return "", fmt.Errorf("%q is not a target name: use lowercase letters, digits, dots, dashes and colons", target)
+	}
+	return basename + ":" + target, nil
+}
+
+var _ function.Function = &IQNFunction{}
+
+func NewIQNFunction() function.Function {
+	return &IQNFunction{}
+}
+
+// IQNFunction implements iqn
+type IQNFunction struct{}
+
+func (f *IQNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "iqn"
+}
+
+func (f *IQNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the IQN of an iSCSI target",
+		Description: "Joins the iSCSI basename of the TrueNAS system and the name of a trueform_iscsi_target into the target's iSCSI qualified name, " +
+			"e.g. \"iqn.2005-10.org.freenas.ctl\" and \"vm-disks\" to \"iqn.2005-10.org.freenas.ctl:vm-disks\", as initiators connect to it.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "basename",
+				Description: "The basename of the iSCSI service, iqn.2005-10.org.freenas.ctl unless changed.",
+			},
+			function.StringParameter{
+				Name:        "target",
+				Description: "The name of the target.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *IQNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var basename, target string
+	resp.Error = req.Arguments.Get(ctx, &basename, &target)
+	if resp.Error != nil {
+		return
+	}
+
+	name, err := iqn(basename, target)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, name)
+}
@@ -0,0 +1,31 @@
+package functions
+
+import "testing"
+
+func TestIQN(t *testing.T) {
+	tests := []struct {
+		basename, target string
+		want             string
+		wantErr          bool
+	}{
+		{basename: "iqn.2005-10.org.freenas.ctl", target: "vm-disks", want: "iqn.2005-10.org.freenas.ctl:vm-disks"},
+		{basename: "iqn.2024-01.com.example:storage", target: "db.lun0", want: "iqn.2024-01.com.example:storage:db.lun0"},
+		{basename: "iqn.2005-10.org.freenas.ctl", target: "VM-Disks", wantErr: true},
+		{basename: "iqn.2005-10.org.freenas.ctl", target: "", wantErr: true},
+		{basename: "iqn.2005-10.org.freenas.ctl", target: "vm disks", wantErr: true},
+		{basename: "org.freenas.ctl", target: "vm-disks", wantErr: true},
+		{basename: "iqn.2005-13.org.freenas.ctl", target: "vm-disks", wantErr: true},
+		{basename: "iqn.2005-10.", target: "vm-disks", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := iqn(tt.basename, tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("iqn(%q, %q) error = %v, wantErr %v", tt.basename, tt.target, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("iqn(%q, %q) = %q, want %q", tt.basename, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package functions implements the provider-defined functions, e.g.
+// provider::trueform::parse_size, for values TrueNAS expects in a shape
+// that is tedious to write by hand.
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// sizeUnits are the units parse_size accepts and their powers of 1024. Like
+// ZFS and the TrueNAS UI, every unit is binary: 1G and 1GB are both 1GiB.
+var sizeUnits = map[string]int{
+	"":  0,
+	"B": 0,
+	"K": 1, "KB": 1, "KIB": 1,
+	"M": 2, "MB": 2, "MIB": 2,
+	"G": 3, "GB": 3, "GIB": 3,
+	"T": 4, "TB": 4, "TIB": 4,
+	"P": 5, "PB": 5, "PIB": 5,
+	"E": 6, "EB": 6, "EIB": 6,
+}
+
+// formatUnits are the units format_size writes, by power of 1024
+var formatUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// parseSize parses a size such as "1.5TiB", "500G" or "4096" into bytes
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.ToUpper(strings.TrimSpace(s[i:]))
+	if number == "" {
+		return 0, fmt.Errorf("%q is not a size such as 1.5TiB or 500G", s)
+	}
+	power, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("%q has an unknown unit %q, use B, K, M, G, T, P or E, optionally followed by iB or B", s, s[i:])
+	}
+
+	value, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("%q is not a size such as 1.5TiB or 500G", s)
+	}
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(10*power))))
+	if !value.IsInt() {
+		return 0, fmt.Errorf("%q is not a whole number of bytes", s)
+	}
+	if !value.Num().IsInt64() {
+		return 0, fmt.Errorf("%q is too large", s)
+	}
+	return value.Num().Int64(), nil
+}
+
+// formatSize writes bytes in the largest binary unit it is at least one of,
+// rounded to two decimals, e.g. "1.5 TiB"
+func formatSize(bytes int64) (string, error) {
+	if bytes < 0 {
+		return "", errors.New("size must not be negative")
+	}
+	power := 0
+	for power < len(formatUnits)-1 && bytes >= int64(1)<<(10*(power+1)) {
+		power++
+	}
+	value := float64(bytes) / math.Pow(1024, float64(power))
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64) + " " + formatUnits[power], nil
+}
+
+var _ function.Function = &ParseSizeFunction{}
+
+func NewParseSizeFunction() function.Function {
+	return &ParseSizeFunction{}
+}
+
+// ParseSizeFunction implements parse_size
+type ParseSizeFunction struct{}
+
+func (f *ParseSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_size"
+}
+
+func (f *ParseSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Convert a human-readable size to bytes",
+		Description: "Converts a size such as \"1.5TiB\", \"500G\" or \"4096\" to a number of bytes, e.g. for the quota, refquota and volsize of a dataset. " +
+			"Like in ZFS and the TrueNAS UI, all units are binary: 1G, 1GB and 1GiB are all 1073741824 bytes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "size",
+				Description: "The size: a number, optionally with a fraction, followed by an optional unit of B, K, M, G, T, P or E, each optionally followed by iB or B.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *ParseSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size string
+	resp.Error = req.Arguments.Get(ctx, &size)
+	if resp.Error != nil {
+		return
+	}
+
+	bytes, err := parseSize(size)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, bytes)
+}
+
+var _ function.Function = &FormatSizeFunction{}
+
+func NewFormatSizeFunction() function.Function {
+	return &FormatSizeFunction{}
+}
+
+// FormatSizeFunction implements format_size
+type FormatSizeFunction struct{}
+
+func (f *FormatSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_size"
+}
+
+func (f *FormatSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Convert bytes to a human-readable size",
+		Description: "Converts a number of bytes to the largest binary unit it is at least one of, rounded to two decimals, e.g. 1649267441664 to \"1.5 TiB\".",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "bytes",
+				Description: "The size in bytes.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bytes int64
+	resp.Error = req.Arguments.Get(ctx, &bytes)
+	if resp.Error != nil {
+		return
+	}
+
+	size, err := formatSize(bytes)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, size)
+}
@@ -0,0 +1,91 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "4096", want: 4096},
+		{size: "512B", want: 512},
+		{size: "1K", want: 1024},
+		{size: "10 MiB", want: 10 << 20},
+		{size: "500G", want: 500 << 30},
+		{size: "500GB", want: 500 << 30},
+		{size: "1.5TiB", want: 3 << 39},
+		{size: "1.5tib", want: 3 << 39},
+		{size: "0.5M", want: 512 << 10},
+		{size: "7E", want: 7 << 60},
+		{size: "", wantErr: true},
+		{size: "GiB", wantErr: true},
+		{size: "1.5", wantErr: true},
+		{size: "1.1K", wantErr: true},
+		{size: "1..5G", wantErr: true},
+		{size: "-1G", wantErr: true},
+		{size: "10 GBytes", wantErr: true},
+		{size: "8E", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSize(tt.size)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSize(%q) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseSize(%q) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{bytes: 0, want: "0 B"},
+		{bytes: 1023, want: "1023 B"},
+		{bytes: 1024, want: "1 KiB"},
+		{bytes: 1500, want: "1.46 KiB"},
+		{bytes: 500 << 30, want: "500 GiB"},
+		{bytes: 3 << 39, want: "1.5 TiB"},
+		{bytes: 1 << 62, want: "4 EiB"},
+	}
+
+	for _, tt := range tests {
+		got, err := formatSize(tt.bytes)
+		if err != nil || got != tt.want {
+			t.Errorf("formatSize(%d) = %q, %v, want %q", tt.bytes, got, err, tt.want)
+		}
+		if err == nil && tt.bytes%1024 == 0 {
+			// Whole units survive a round trip
+			if back, err := parseSize(got); err != nil || back != tt.bytes {
+				t.Errorf("parseSize(formatSize(%d)) = %d, %v", tt.bytes, back, err)
+			}
+		}
+	}
+	if _, err := formatSize(-1); err == nil {
+		t.Error("formatSize(-1) succeeded")
+	}
+}
+
+func TestParseSizeFunction(t *testing.T) {
+	result, err := runFunction(NewParseSizeFunction(), types.StringValue("1.5TiB"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Equal(types.Int64Value(3 << 39)) {
+		t.Errorf("Run() = %v", result)
+	}
+
+	_, err = runFunction(NewParseSizeFunction(), types.StringValue("lots"))
+	if err == nil || err.FunctionArgument == nil || *err.FunctionArgument != 0 {
+		t.Errorf("Run() error = %v, want an error for the argument", err)
+	}
+}
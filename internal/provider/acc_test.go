@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+// testAccProtoV6ProviderFactories starts the provider in-process for
+// acceptance tests
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"trueform": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccServer starts a fake TrueNAS for one test. The acceptance tests run
+// against it rather than a real system, so they only need TF_ACC and a
+// terraform binary.
+func testAccServer(t *testing.T) *testserver.Server {
+	t.Helper()
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testAccCassette sets up a test that replays the API session recorded in
+// testdata/cassettes/<name>.json, and returns the provider factories and
+// provider block to use. The test is skipped if the cassette doesn't exist.
+//
+// With TRUEFORM_RECORD set, the test instead runs against the TrueNAS given
+// by TRUENAS_HOST and TRUENAS_API_KEY and records the cassette. The value of
+// TRUEFORM_RECORD is saved as the cassette's comment and should name the
+// TrueNAS version, e.g. TRUEFORM_RECORD="TrueNAS 25.10.0".
+func testAccCassette(t *testing.T, name string) (map[string]func() (tfprotov6.ProviderServer, error), string) {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", name+".json")
+
+	if comment := os.Getenv("TRUEFORM_RECORD"); comment != "" {
+		recorder := client.NewRecorder(comment)
+		t.Cleanup(func() {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Errorf("failed to create cassette directory: %v", err)
+				return
+			}
+			if err := recorder.Save(path); err != nil {
+				t.Errorf("failed to save cassette: %v", err)
+			}
+		})
+		return testAccFactories(&TrueformProvider{version: "test", recorder: recorder}), `provider "trueform" {}`
+	}
+
+	cassette, err := client.LoadCassette(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("no cassette at %s; record one with TRUEFORM_RECORD", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := client.NewReplayer(cassette)
+	t.Cleanup(func() {
+		if unused := replayer.Unused(); len(unused) > 0 && !t.Failed() && !t.Skipped() {
+			t.Errorf("%d recorded calls were not replayed, starting with %s; re-record the cassette", len(unused), unused[0].Method)
+		}
+	})
+	return testAccFactories(&TrueformProvider{version: "test", transport: replayer}), `
+provider "trueform" {
+  host    = "replay"
+  api_key = "replay"
+}
+`
+}
+
+// testAccFactories serves a configured provider instance
+func testAccFactories(p *TrueformProvider) map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"trueform": providerserver.NewProtocol6WithError(p),
+	}
+}
+
+// testAccPoolConfig creates the pool most resources depend on
+const testAccPoolConfig = `
+resource "trueform_pool" "test" {
+  name = "tank"
+
+  topology = [
+    {
+      type  = "data"
+      disks = ["sdb", "sdc"]
+    }
+  ]
+}
+`
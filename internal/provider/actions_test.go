@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccActions(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_cronjob" "test" {
+  user        = "root"
+  command     = "echo hello"
+  description = "created by test"
+  enabled     = false
+  schedule    = provider::trueform::parse_cron("@daily")
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.trueform_cronjob_run.test, action.trueform_service_restart.nfs]
+    }
+  }
+}
+
+action "trueform_cronjob_run" "test" {
+  config {
+    cronjob_id = trueform_cronjob.test.id
+  }
+}
+
+action "trueform_service_restart" "nfs" {
+  config {
+    service = "nfs"
+  }
+}
+`,
+				Check: func(*terraform.State) error {
+					if n := srv.CallCount("cronjob.run"); n != 1 {
+						return fmt.Errorf("cronjob.run called %d times, want 1", n)
+					}
+					if n := srv.CallCount("service.restart"); n != 1 {
+						return fmt.Errorf("service.restart called %d times, want 1", n)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,390 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// credentialProcessTimeout bounds how long a credential_process may run,
+// e.g. while a password manager waits for the user to unlock it
+const credentialProcessTimeout = 2 * time.Minute
+
+// credentials are the secrets the provider authenticates with, as
+// configured. api_key_file and credential_process are turned into an API key
+// or a username and password by resolve.
+type credentials struct {
+	APIKey            string
+	APIKeyFile        string
+	CredentialProcess string
+	Username          string
+	Password          string
+}
+
+// resolve reads api_key_file or runs credential_process, whichever is set.
+// Only one source of credentials may be set.
+func (c *credentials) resolve(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var sources []string
+	if c.APIKey != "" {
+		sources = append(sources, "api_key")
+	}
+	if c.APIKeyFile != "" {
+		sources = append(sources, "api_key_file")
+	}
+	if c.CredentialProcess != "" {
+		sources = append(sources, "credential_process")
+	}
+	if (c.APIKeyFile != "" || c.CredentialProcess != "") && (c.Username != "" || c.Password != "") {
+		sources = append(sources, "username and password")
+	}
+	if len(sources) > 1 {
+		diags.AddAttributeError(
+			path.Root(sources[1]),
+			"Conflicting TrueNAS Credential Sources",
+			fmt.Sprintf("Set only one of api_key, api_key_file, credential_process or username and password, got %s.", strings.Join(sources, " and ")),
+		)
+		return diags
+	}
+
+	switch {
+	case c.APIKeyFile != "":
+		data, err := os.ReadFile(expandHome(c.APIKeyFile))
+		if err == nil && strings.TrimSpace(string(data)) == "" {
+			err = errors.New("the file is empty")
+		}
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("api_key_file"),
+				"Unable to Read TrueNAS API Key",
+				"Could not read api_key_file: "+err.Error(),
+			)
+			return diags
+		}
+		c.APIKey = strings.TrimSpace(string(data))
+
+	case c.CredentialProcess != "":
+		out, err := runCredentialProcess(ctx, c.CredentialProcess)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("credential_process"),
+				"TrueNAS Credential Process Failed",
+				err.Error(),
+			)
+			return diags
+		}
+		c.APIKey, c.Username, c.Password = out.APIKey, out.Username, out.Password
+	}
+	return diags
+}
+
+// processCredentials is the JSON a credential_process prints: an API key, or
+// a username and password
+type processCredentials struct {
+	APIKey   string `json:"api_key"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// runCredentialProcess runs a credential_process command line and parses
+// what it prints. The command is split into words like a shell would, but
+// isn't run by one.
+func runCredentialProcess(ctx context.Context, command string) (*processCredentials, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential_process %q: %w", command, err)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("credential_process is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	var out processCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		// The output holds secrets, so it isn't quoted
+		return nil, fmt.Errorf("%s did not print a JSON object: %w", args[0], err)
+	}
+	switch {
+	case out.APIKey != "" && (out.Username != "" || out.Password != ""):
+		return nil, fmt.Errorf("%s printed both an api_key and a username or password", args[0])
+	case out.APIKey == "" && (out.Username == "" || out.Password == ""):
+		return nil, fmt.Errorf("%s printed neither an api_key nor a username and password", args[0])
+	}
+	return &out, nil
+}
+
+// splitCommand splits a command line into words. Words are separated by
+// whitespace; single quotes keep everything up to the next one, double
+// quotes keep everything but backslash escapes of " and \.
+func splitCommand(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// profile is a named section of the credentials file. It holds the
+// connection settings of one TrueNAS system.
+type profile struct {
+	Host              string
+	Hosts             []string
+	APIKey            string
+	APIKeyFile        string
+	CredentialProcess string
+	Username          string
+	Password          string
+	VerifySSL         *bool
+	CACertFile        string
+	TLSServerName     string
+	TLSFingerprint    string
+}
+
+// credentials returns the profile's credentials
+func (p *profile) credentials() credentials {
+	return credentials{
+		APIKey:            p.APIKey,
+		APIKeyFile:        p.APIKeyFile,
+		CredentialProcess: p.CredentialProcess,
+		Username:          p.Username,
+		Password:          p.Password,
+	}
+}
+
+// credentialsFilePath returns where the profiles are kept:
+// TRUEFORM_CREDENTIALS_FILE, or trueform/credentials under XDG_CONFIG_HOME,
+// which defaults to ~/.config
+func credentialsFilePath() (string, error) {
+	if path := os.Getenv("TRUEFORM_CREDENTIALS_FILE"); path != "" {
+		return expandHome(path), nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "trueform", "credentials"), nil
+}
+
+// loadProfile reads a profile from the credentials file. The file holds
+// secrets, so it is reported if others may read it.
+func loadProfile(name string) (*profile, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	file, err := credentialsFilePath()
+	if err != nil {
+		diags.AddAttributeError(path.Root("profile"), "Unable to Find TrueNAS Credentials File", err.Error())
+		return nil, diags
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Read TrueNAS Credentials File",
+			fmt.Sprintf("Could not read the profiles in %s: %s", file, err),
+		)
+		return nil, diags
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		diags.AddAttributeWarning(
+			path.Root("profile"),
+			"Insecure TrueNAS Credentials File",
+			fmt.Sprintf("%s can be read by other users. Restrict it with: chmod 600 %s", file, file),
+		)
+	}
+
+	profiles, err := parseProfiles(f)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Invalid TrueNAS Credentials File",
+			fmt.Sprintf("%s: %s", file, err),
+		)
+		return nil, diags
+	}
+	p, ok := profiles[name]
+	if !ok {
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unknown TrueNAS Profile",
+			fmt.Sprintf("%s has no [%s] profile.", file, name),
+		)
+		return nil, diags
+	}
+	return p, diags
+}
+
+// parseProfiles parses a credentials file. Like the AWS CLI's, it is an INI
+// file with one [section] per profile of key = value lines; lines starting
+// with # or ; are comments.
+func parseProfiles(r io.Reader) (map[string]*profile, error) {
+	profiles := make(map[string]*profile)
+	var current *profile
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", lineNo)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := profiles[name]; ok || name == "" {
+				return nil, fmt.Errorf("line %d: duplicate or empty profile name %q", lineNo, name)
+			}
+			current = &profile{}
+			profiles[name] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a [profile] section", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		if err := current.set(key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// set assigns a setting of the credentials file
+func (p *profile) set(key, value string) error {
+	fields := map[string]*string{
+		"host":                   &p.Host,
+		"api_key":                &p.APIKey,
+		"api_key_file":           &p.APIKeyFile,
+		"credential_process":     &p.CredentialProcess,
+		"username":               &p.Username,
+		"password":               &p.Password,
+		"ca_cert_file":           &p.CACertFile,
+		"tls_server_name":        &p.TLSServerName,
+		"tls_fingerprint_sha256": &p.TLSFingerprint,
+	}
+	if field, ok := fields[key]; ok {
+		*field = value
+		return nil
+	}
+	if key == "hosts" {
+		p.Hosts = nil
+		for _, host := range strings.Split(value, ",") {
+			if host = strings.TrimSpace(host); host == "" {
+				return errors.New("hosts must not contain empty entries")
+			}
+			p.Hosts = append(p.Hosts, host)
+		}
+		return nil
+	}
+	if key == "verify_ssl" {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("verify_ssl must be true or false, got %q", value)
+		}
+		p.VerifySSL = &v
+		return nil
+	}
+	return fmt.Errorf("unknown setting %q", key)
+}
+
+// expandHome replaces a leading ~/ with the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
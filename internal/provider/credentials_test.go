@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{command: "op read op://vault/truenas/key", want: []string{"op", "read", "op://vault/truenas/key"}},
+		{command: "  pass  show\ttruenas ", want: []string{"pass", "show", "truenas"}},
+		{command: `get-key --item 'TrueNAS API key'`, want: []string{"get-key", "--item", "TrueNAS API key"}},
+		{command: `get-key "say \"hi\"" a\ b`, want: []string{"get-key", `say "hi"`, "a b"}},
+		{command: `get-key ''`, want: []string{"get-key", ""}},
+		{command: "", want: nil},
+		{command: `get-key 'open`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitCommand(tt.command)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitCommand(%q) error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	profiles, err := parseProfiles(strings.NewReader(`
+# lab systems
+[lab]
+host       = nas.lab.example.com
+api_key    = "1-abc"
+verify_ssl = false
+
+; production
+[prod]
+hosts                  = 10.0.0.5, 10.0.0.6
+credential_process     = op read op://infra/truenas/key
+tls_server_name        = nas.example.com
+tls_fingerprint_sha256 = AB:CD
+`))
+	if err != nil {
+		t.Fatalf("parseProfiles() error = %v", err)
+	}
+
+	lab := profiles["lab"]
+	if lab == nil || lab.Host != "nas.lab.example.com" || lab.APIKey != "1-abc" || lab.VerifySSL == nil || *lab.VerifySSL {
+		t.Errorf("lab profile = %+v", lab)
+	}
+	prod := profiles["prod"]
+	if prod == nil || !reflect.DeepEqual(prod.Hosts, []string{"10.0.0.5", "10.0.0.6"}) || prod.CredentialProcess != "op read op://infra/truenas/key" || prod.TLSServerName != "nas.example.com" || prod.VerifySSL != nil {
+		t.Errorf("prod profile = %+v", prod)
+	}
+
+	for _, invalid := range []string{
+		"host = nas",
+		"[lab]\nhost",
+		"[lab]\nhostname = nas",
+		"[lab]\nverify_ssl = maybe",
+		"[lab]\nhosts = a,,b",
+		"[lab]\n[lab]",
+		"[lab",
+	} {
+		if _, err := parseProfiles(strings.NewReader(invalid)); err == nil {
+			t.Errorf("parseProfiles(%q) succeeded, want an error", invalid)
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TRUEFORM_CREDENTIALS_FILE", "")
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "trueform"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "trueform", "credentials")
+	if err := os.WriteFile(file, []byte("[lab]\nhost = nas\napi_key = 1-abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, diags := loadProfile("lab")
+	if diags.HasError() || len(diags) != 0 || p.Host != "nas" {
+		t.Fatalf("loadProfile() = %+v, %v", p, diags)
+	}
+	if _, diags := loadProfile("prod"); !diags.HasError() {
+		t.Error("loadProfile() of a missing profile succeeded")
+	}
+
+	if err := os.Chmod(file, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := loadProfile("lab"); diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("loadProfile() of a world-readable file diagnostics = %v, want a warning", diags)
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("1-fromfile\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	script := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	apiKeyProcess := script("api-key", `echo "{\"api_key\": \"1-from$1\"}"`)
+	passwordProcess := script("password", `echo '{"username": "admin", "password": "secret"}'`)
+	failingProcess := script("failing", `echo "vault is locked" >&2; exit 1`)
+	garbageProcess := script("garbage", `echo hunter2`)
+	emptyProcess := script("empty-object", `echo '{}'`)
+
+	tests := []struct {
+		name    string
+		creds   credentials
+		want    credentials
+		wantErr string
+	}{
+		{name: "api key", creds: credentials{APIKey: "1-abc"}, want: credentials{APIKey: "1-abc"}},
+		{name: "api key file", creds: credentials{APIKeyFile: keyFile}, want: credentials{APIKey: "1-fromfile"}},
+		{name: "missing api key file", creds: credentials{APIKeyFile: filepath.Join(dir, "missing")}, wantErr: "no such file"},
+		{name: "empty api key file", creds: credentials{APIKeyFile: emptyFile}, wantErr: "empty"},
+		{name: "api key process", creds: credentials{CredentialProcess: apiKeyProcess + " process"}, want: credentials{APIKey: "1-fromprocess"}},
+		{name: "password process", creds: credentials{CredentialProcess: passwordProcess}, want: credentials{Username: "admin", Password: "secret"}},
+		{name: "failing process", creds: credentials{CredentialProcess: failingProcess}, wantErr: "vault is locked"},
+		{name: "process printing garbage", creds: credentials{CredentialProcess: garbageProcess}, wantErr: "did not print a JSON object"},
+		{name: "process printing nothing", creds: credentials{CredentialProcess: emptyProcess}, wantErr: "neither"},
+		{name: "api key and file", creds: credentials{APIKey: "1-abc", APIKeyFile: keyFile}, wantErr: "api_key and api_key_file"},
+		{name: "file and process", creds: credentials{APIKeyFile: keyFile, CredentialProcess: apiKeyProcess}, wantErr: "api_key_file and credential_process"},
+		{name: "process and password", creds: credentials{CredentialProcess: apiKeyProcess, Password: "secret"}, wantErr: "credential_process and username and password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := tt.creds
+			diags := creds.resolve(context.Background())
+			if tt.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), tt.wantErr) {
+					t.Fatalf("resolve() diagnostics = %v, want an error containing %q", diags, tt.wantErr)
+				}
+				if strings.Contains(diags.Errors()[0].Detail(), "hunter2") {
+					t.Errorf("resolve() error discloses the process output: %v", diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("resolve() diagnostics = %v", diags)
+			}
+			if creds.APIKey != tt.want.APIKey || creds.Username != tt.want.Username || creds.Password != tt.want.Password {
+				t.Errorf("resolve() = %+v, want %+v", creds, tt.want)
+			}
+		})
+	}
+}
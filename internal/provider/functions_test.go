@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccFunctions(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+output "quota" {
+  value = provider::trueform::parse_size("1.5 lots")
+}
+`,
+				ExpectError: regexp.MustCompile(`unknown unit`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_cronjob" "test" {
+  user        = "root"
+  command     = "echo hello"
+  description = "created by test"
+  enabled     = false
+  schedule    = provider::trueform::parse_cron("0 3 * * 1")
+}
+
+output "quota" {
+  value = provider::trueform::parse_size("1.5TiB")
+}
+
+output "size" {
+  value = provider::trueform::format_size(536870912000)
+}
+
+output "dataset" {
+  value = provider::trueform::dataset_path("tank", "apps/nextcloud")
+}
+
+output "snapshot_dataset" {
+  value = provider::trueform::parse_snapshot_id("tank/apps@daily").dataset
+}
+
+output "iqn" {
+  value = provider::trueform::iqn("iqn.2005-10.org.freenas.ctl", "vm-disks")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_cronjob.test", "schedule.hour", "3"),
+					resource.TestCheckResourceAttr("trueform_cronjob.test", "schedule.dow", "1"),
+					resource.TestCheckOutput("quota", "1649267441664"),
+					resource.TestCheckOutput("size", "500 GiB"),
+					resource.TestCheckOutput("dataset", "tank/apps/nextcloud"),
+					resource.TestCheckOutput("snapshot_dataset", "tank/apps"),
+					resource.TestCheckOutput("iqn", "iqn.2005-10.org.freenas.ctl:vm-disks"),
+				),
+			},
+		},
+	})
+}
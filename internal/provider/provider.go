@@ -2,34 +2,102 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/trueform/terraform-provider-trueform/internal/actions"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 	"github.com/trueform/terraform-provider-trueform/internal/datasources"
+	"github.com/trueform/terraform-provider-trueform/internal/functions"
 	"github.com/trueform/terraform-provider-trueform/internal/resources"
 )
 
+// maxConnectionsLimit bounds max_connections, since every connection is a
+// separate session on TrueNAS
+const maxConnectionsLimit = 32
+
 // Ensure TrueformProvider satisfies various provider interfaces.
-var _ provider.Provider = &TrueformProvider{}
+var (
+	_ provider.Provider              = &TrueformProvider{}
+	_ provider.ProviderWithFunctions = &TrueformProvider{}
+	_ provider.ProviderWithActions   = &TrueformProvider{}
+)
 
 // TrueformProvider defines the provider implementation.
 type TrueformProvider struct {
 	version string
+
+	// transport and recorder are set by tests to replay or record API
+	// sessions
+	transport client.Transport
+	recorder  *client.Recorder
+	// tracerProvider is set by tests to collect the client's spans; the
+	// global one is used otherwise
+	tracerProvider trace.TracerProvider
 }
 
 // TrueformProviderModel describes the provider data model.
 type TrueformProviderModel struct {
 	Host      types.String `tfsdk:"host"`
+	Hosts     types.List   `tfsdk:"hosts"`
 	APIKey    types.String `tfsdk:"api_key"`
+	Username  types.String `tfsdk:"username"`
+	Password  types.String `tfsdk:"password"`
+	OTPToken  types.String `tfsdk:"otp_token"`
 	VerifySSL types.Bool   `tfsdk:"verify_ssl"`
+
+	APIKeyFile        types.String `tfsdk:"api_key_file"`
+	CredentialProcess types.String `tfsdk:"credential_process"`
+	Profile           types.String `tfsdk:"profile"`
+
+	CACertFile     types.String `tfsdk:"ca_cert_file"`
+	CACertPEM      types.String `tfsdk:"ca_cert_pem"`
+	TLSServerName  types.String `tfsdk:"tls_server_name"`
+	TLSFingerprint types.String `tfsdk:"tls_fingerprint_sha256"`
+	ClientCertPEM  types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM   types.String `tfsdk:"client_key_pem"`
+
+	ProxyURL  types.String    `tfsdk:"proxy_url"`
+	SSHTunnel *SSHTunnelModel `tfsdk:"ssh_tunnel"`
+
+	MaxConnections types.Int64     `tfsdk:"max_connections"`
+	ReadCacheTTL   types.String    `tfsdk:"read_cache_ttl"`
+	RateLimit      *RateLimitModel `tfsdk:"rate_limit"`
+
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+	JobTimeout     types.String `tfsdk:"job_timeout"`
+}
+
+// RateLimitModel describes the rate_limit block.
+type RateLimitModel struct {
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
+	Concurrency       types.Map     `tfsdk:"concurrency"`
+}
+
+// SSHTunnelModel describes the ssh_tunnel block.
+type SSHTunnelModel struct {
+	Host       types.String `tfsdk:"host"`
+	User       types.String `tfsdk:"user"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	HostKey    types.String `tfsdk:"host_key"`
 }
 
 func New(version string) func() provider.Provider {
@@ -53,8 +121,39 @@ func (p *TrueformProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Description: "The hostname or IP address of the TrueNAS server. Can also be set via the TRUENAS_HOST environment variable.",
 				Optional:    true,
 			},
+			"hosts": schema.ListAttribute{
+				Description: "The endpoints of a TrueNAS Enterprise HA pair, e.g. the virtual IP and both controllers, as an alternative to host. They are tried in order and the active controller is used; after a failover the provider reconnects to the new one. Conflicts with host. Can also be set via the TRUENAS_HOSTS environment variable, separated by commas.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 			"api_key": schema.StringAttribute{
-				Description: "The API key for authenticating with TrueNAS. Can also be set via the TRUENAS_API_KEY environment variable.",
+				Description: "The API key for authenticating with TrueNAS. Conflicts with username and password. Can also be set via the TRUENAS_API_KEY environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"api_key_file": schema.StringAttribute{
+				Description: "Path to a file holding the API key, e.g. a secret mounted by the CI system. Conflicts with api_key and credential_process. Can also be set via the TRUENAS_API_KEY_FILE environment variable.",
+				Optional:    true,
+			},
+			"credential_process": schema.StringAttribute{
+				Description: "A command printing the credentials as JSON, either {\"api_key\": \"...\"} or {\"username\": \"...\", \"password\": \"...\"}, e.g. a password manager's CLI. It is split into words like a shell would, but not run by one. Conflicts with api_key and api_key_file. Can also be set via the TRUENAS_CREDENTIAL_PROCESS environment variable.",
+				Optional:    true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "A profile of the credentials file, ~/.config/trueform/credentials, holding the host, credentials and TLS settings. Settings made in the configuration or the environment take precedence. Can also be set via the TRUENAS_PROFILE environment variable.",
+				Optional:    true,
+			},
+			"username": schema.StringAttribute{
+				Description: "The username for password authentication, as an alternative to api_key. Can also be set via the TRUENAS_USERNAME environment variable.",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "The password for password authentication. Can also be set via the TRUENAS_PASSWORD environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"otp_token": schema.StringAttribute{
+				Description: "The one-time password for accounts with two-factor authentication enabled. Only used with username and password. Can also be set via the TRUENAS_OTP_TOKEN environment variable.",
 				Optional:    true,
 				Sensitive:   true,
 			},
@@ -62,6 +161,93 @@ func (p *TrueformProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Description: "Whether to verify SSL certificates. Defaults to true. Can also be set via the TRUENAS_VERIFY_SSL environment variable.",
 				Optional:    true,
 			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM file of CA certificates used to verify the TrueNAS certificate, e.g. an internal CA. Conflicts with ca_cert_pem. Can also be set via the TRUENAS_CA_CERT_FILE environment variable.",
+				Optional:    true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM-encoded CA certificates used to verify the TrueNAS certificate. Conflicts with ca_cert_file.",
+				Optional:    true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				Description: "Server name used to verify the TrueNAS certificate when it differs from host, e.g. when connecting by IP. Can also be set via the TRUENAS_TLS_SERVER_NAME environment variable.",
+				Optional:    true,
+			},
+			"tls_fingerprint_sha256": schema.StringAttribute{
+				Description: "SHA-256 fingerprint of the TrueNAS certificate, as hex with or without colons. When set, the certificate is pinned instead of verified against a CA, which allows self-signed certificates without disabling verification. Can also be set via the TRUENAS_TLS_FINGERPRINT_SHA256 environment variable.",
+				Optional:    true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				Description: "PEM-encoded client certificate for mutual TLS. Requires client_key_pem.",
+				Optional:    true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				Description: "PEM-encoded private key for client_cert_pem.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of an http, https or socks5 proxy to connect through, e.g. socks5://bastion:1080. Defaults to the HTTPS_PROXY and NO_PROXY environment variables. Conflicts with ssh_tunnel.",
+				Optional:    true,
+			},
+			"max_connections": schema.Int64Attribute{
+				Description: "The most WebSocket connections to open to TrueNAS. TrueNAS answers the calls on one connection in turn, so with more than one connection slow operations such as creating an encrypted dataset don't hold up the rest of a parallel apply. Additional connections are only opened while all open ones are busy. Defaults to 1. Can also be set with the TRUENAS_MAX_CONNECTIONS environment variable.",
+				Optional:    true,
+			},
+			"read_cache_ttl": schema.StringAttribute{
+				Description: "How long to reuse API read results, as a duration such as 30s. When set, resources of one kind are refreshed from a single bulk query instead of one call each, which speeds up plans of large estates. Any change made through the provider invalidates the cached reads it affects. Disabled by default. Can also be set with the TRUENAS_READ_CACHE_TTL environment variable.",
+				Optional:    true,
+			},
+			"request_timeout": schema.StringAttribute{
+				Description: "How long to wait for the answer to an API call, as a duration such as 30s. A resource's timeouts block extends it for the calls of that operation. Defaults to 10s. Can also be set with the TRUENAS_REQUEST_TIMEOUT environment variable.",
+				Optional:    true,
+			},
+			"job_timeout": schema.StringAttribute{
+				Description: "How long to wait for a TrueNAS job, such as creating a pool or installing an app, as a duration such as 30m. It is the default of the timeouts blocks of resources that run jobs. Defaults to 10m. Can also be set with the TRUENAS_JOB_TIMEOUT environment variable.",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"ssh_tunnel": schema.SingleNestedBlock{
+				Description: "Reach TrueNAS through an SSH jump host. The provider opens the tunnel itself, so no manual port forwarding is needed. Conflicts with proxy_url.",
+				Attributes: map[string]schema.Attribute{
+					"host": schema.StringAttribute{
+						Description: "The jump host as host or host:port. The port defaults to 22.",
+						Optional:    true,
+					},
+					"user": schema.StringAttribute{
+						Description: "The SSH user on the jump host.",
+						Optional:    true,
+					},
+					"private_key": schema.StringAttribute{
+						Description: "The PEM-encoded private key used to authenticate to the jump host.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"host_key": schema.StringAttribute{
+						Description: "The jump host's public key in authorized_keys format. If not set, the key is checked against ~/.ssh/known_hosts.",
+						Optional:    true,
+					},
+				},
+			},
+			"rate_limit": schema.SingleNestedBlock{
+				Description: "Limit the load the provider puts on TrueNAS. The NFS share, iSCSI and VM device resources always make their changes one at a time, since TrueNAS fails concurrent changes to them.",
+				Attributes: map[string]schema.Attribute{
+					"requests_per_second": schema.Float64Attribute{
+						Description: "The most API requests to send per second. Unlimited if not set.",
+						Optional:    true,
+					},
+					"burst": schema.Int64Attribute{
+						Description: "How many requests may be sent at once before requests_per_second applies. Defaults to 1.",
+						Optional:    true,
+					},
+					"concurrency": schema.MapAttribute{
+						Description: "The most changes to run at once per API namespace, e.g. { \"pool.dataset\" = 2 }. A namespace includes the namespaces below it. Read-only calls are not limited. Overrides the built-in limits.",
+						ElementType: types.Int64Type,
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -82,52 +268,362 @@ func (p *TrueformProvider) Configure(ctx context.Context, req provider.Configure
 		host = config.Host.ValueString()
 	}
 
+	var hosts []string
+	if envVal := os.Getenv("TRUENAS_HOSTS"); envVal != "" && config.Host.IsNull() {
+		for _, h := range strings.Split(envVal, ",") {
+			hosts = append(hosts, strings.TrimSpace(h))
+		}
+	}
+	if !config.Hosts.IsNull() {
+		hosts = nil
+		resp.Diagnostics.Append(config.Hosts.ElementsAs(ctx, &hosts, false)...)
+		if !config.Host.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("hosts"),
+				"Conflicting TrueNAS Host Settings",
+				"Set either host or hosts, not both.",
+			)
+		}
+	}
+	for _, h := range hosts {
+		if h == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("hosts"),
+				"Invalid TrueNAS Hosts",
+				"hosts (TRUENAS_HOSTS) must not contain empty entries.",
+			)
+			break
+		}
+	}
+	if len(hosts) > 0 {
+		host = hosts[0]
+	}
+
 	apiKey := os.Getenv("TRUENAS_API_KEY")
 	if !config.APIKey.IsNull() {
 		apiKey = config.APIKey.ValueString()
 	}
 
+	username := os.Getenv("TRUENAS_USERNAME")
+	if !config.Username.IsNull() {
+		username = config.Username.ValueString()
+	}
+
+	password := os.Getenv("TRUENAS_PASSWORD")
+	if !config.Password.IsNull() {
+		password = config.Password.ValueString()
+	}
+
+	otpToken := os.Getenv("TRUENAS_OTP_TOKEN")
+	if !config.OTPToken.IsNull() {
+		otpToken = config.OTPToken.ValueString()
+	}
+
+	apiKeyFile := os.Getenv("TRUENAS_API_KEY_FILE")
+	if !config.APIKeyFile.IsNull() {
+		apiKeyFile = config.APIKeyFile.ValueString()
+	}
+
+	credentialProcess := os.Getenv("TRUENAS_CREDENTIAL_PROCESS")
+	if !config.CredentialProcess.IsNull() {
+		credentialProcess = config.CredentialProcess.ValueString()
+	}
+
+	profileName := os.Getenv("TRUENAS_PROFILE")
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
+	}
+
 	verifySSL := true
-	if envVal := os.Getenv("TRUENAS_VERIFY_SSL"); envVal == "false" {
-		verifySSL = false
+	verifySSLSet := !config.VerifySSL.IsNull()
+	if envVal := os.Getenv("TRUENAS_VERIFY_SSL"); envVal != "" {
+		verifySSL = envVal != "false"
+		verifySSLSet = true
 	}
 	if !config.VerifySSL.IsNull() {
 		verifySSL = config.VerifySSL.ValueBool()
 	}
 
+	caCertFile := os.Getenv("TRUENAS_CA_CERT_FILE")
+	if !config.CACertFile.IsNull() {
+		caCertFile = config.CACertFile.ValueString()
+	}
+
+	tlsServerName := os.Getenv("TRUENAS_TLS_SERVER_NAME")
+	if !config.TLSServerName.IsNull() {
+		tlsServerName = config.TLSServerName.ValueString()
+	}
+
+	tlsFingerprint := os.Getenv("TRUENAS_TLS_FINGERPRINT_SHA256")
+	if !config.TLSFingerprint.IsNull() {
+		tlsFingerprint = config.TLSFingerprint.ValueString()
+	}
+
+	maxConnections := int64(1)
+	if envVal := os.Getenv("TRUENAS_MAX_CONNECTIONS"); envVal != "" {
+		n, err := strconv.ParseInt(envVal, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_connections"),
+				"Invalid TrueNAS Max Connections",
+				"TRUENAS_MAX_CONNECTIONS must be a whole number: "+err.Error(),
+			)
+		} else {
+			maxConnections = n
+		}
+	}
+	if !config.MaxConnections.IsNull() {
+		maxConnections = config.MaxConnections.ValueInt64()
+	}
+
+	readCacheTTL := os.Getenv("TRUENAS_READ_CACHE_TTL")
+	if !config.ReadCacheTTL.IsNull() {
+		readCacheTTL = config.ReadCacheTTL.ValueString()
+	}
+
+	requestTimeout := os.Getenv("TRUENAS_REQUEST_TIMEOUT")
+	if !config.RequestTimeout.IsNull() {
+		requestTimeout = config.RequestTimeout.ValueString()
+	}
+	jobTimeout := os.Getenv("TRUENAS_JOB_TIMEOUT")
+	if !config.JobTimeout.IsNull() {
+		jobTimeout = config.JobTimeout.ValueString()
+	}
+
+	caCertPEM := config.CACertPEM.ValueString()
+	clientCertPEM := config.ClientCertPEM.ValueString()
+	clientKeyPEM := config.ClientKeyPEM.ValueString()
+
+	creds := credentials{
+		APIKey:            apiKey,
+		APIKeyFile:        apiKeyFile,
+		CredentialProcess: credentialProcess,
+		Username:          username,
+		Password:          password,
+	}
+
+	// A profile only fills in what the configuration and environment leave
+	// unset. Its credentials are taken as a whole, so they are never mixed
+	// with ones set elsewhere.
+	if profileName != "" {
+		prof, diags := loadProfile(profileName)
+		resp.Diagnostics.Append(diags...)
+		if prof != nil {
+			if host == "" {
+				host, hosts = prof.Host, prof.Hosts
+				if len(hosts) > 0 {
+					host = hosts[0]
+				}
+			}
+			if creds == (credentials{}) {
+				creds = prof.credentials()
+			}
+			if !verifySSLSet && prof.VerifySSL != nil {
+				verifySSL = *prof.VerifySSL
+			}
+			if caCertFile == "" && caCertPEM == "" {
+				caCertFile = expandHome(prof.CACertFile)
+			}
+			if tlsServerName == "" {
+				tlsServerName = prof.TLSServerName
+			}
+			if tlsFingerprint == "" {
+				tlsFingerprint = prof.TLSFingerprint
+			}
+		}
+	}
+
 	// Validate required configuration
 	if host == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
 			"Missing TrueNAS Host",
 			"The provider cannot create the TrueNAS API client without a host. "+
-				"Set the host value in the configuration or use the TRUENAS_HOST environment variable.",
+				"Set the host value in the configuration, use the TRUENAS_HOST environment variable or select a profile.",
 		)
 	}
 
-	if apiKey == "" {
+	if credsDiags := creds.resolve(ctx); credsDiags.HasError() {
+		resp.Diagnostics.Append(credsDiags...)
+	} else {
+		apiKey, username, password = creds.APIKey, creds.Username, creds.Password
+		resp.Diagnostics.Append(validateAuthConfig(apiKey, username, password, otpToken)...)
+	}
+
+	if caCertFile != "" && caCertPEM != "" {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("api_key"),
-			"Missing TrueNAS API Key",
-			"The provider cannot create the TrueNAS API client without an API key. "+
-				"Set the api_key value in the configuration or use the TRUENAS_API_KEY environment variable.",
+			path.Root("ca_cert_pem"),
+			"Conflicting TrueNAS CA Certificate Settings",
+			"Set either ca_cert_file (TRUENAS_CA_CERT_FILE) or ca_cert_pem, not both.",
+		)
+	} else if caCertFile != "" {
+		data, err := os.ReadFile(caCertFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Unable to Read TrueNAS CA Certificate",
+				"Could not read ca_cert_file: "+err.Error(),
+			)
+		}
+		caCertPEM = string(data)
+	}
+
+	if (clientCertPEM == "") != (clientKeyPEM == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert_pem"),
+			"Incomplete TrueNAS Client Certificate",
+			"Mutual TLS requires both client_cert_pem and client_key_pem.",
+		)
+	}
+
+	var sshTunnel *client.SSHTunnelConfig
+	if config.SSHTunnel != nil {
+		sshTunnel = &client.SSHTunnelConfig{
+			Host:       config.SSHTunnel.Host.ValueString(),
+			User:       config.SSHTunnel.User.ValueString(),
+			PrivateKey: config.SSHTunnel.PrivateKey.ValueString(),
+			HostKey:    config.SSHTunnel.HostKey.ValueString(),
+		}
+		resp.Diagnostics.Append(validateSSHTunnelConfig(sshTunnel)...)
+
+		if !config.ProxyURL.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Conflicting TrueNAS Connection Settings",
+				"Set either proxy_url or an ssh_tunnel block, not both.",
+			)
+		}
+	}
+
+	if tlsFingerprint != "" {
+		if _, err := client.ParseFingerprint(tlsFingerprint); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("tls_fingerprint_sha256"),
+				"Invalid TrueNAS Certificate Fingerprint",
+				err.Error(),
+			)
+		}
+	}
+
+	if maxConnections < 1 || maxConnections > maxConnectionsLimit {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_connections"),
+			"Invalid TrueNAS Max Connections",
+			fmt.Sprintf("max_connections must be between 1 and %d, got %d.", maxConnectionsLimit, maxConnections),
 		)
 	}
 
+	var rateLimit float64
+	var rateBurst int
+	var concurrency map[string]int
+	if config.RateLimit != nil {
+		rateLimit = config.RateLimit.RequestsPerSecond.ValueFloat64()
+		rateBurst = int(config.RateLimit.Burst.ValueInt64())
+		if rateLimit < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rate_limit").AtName("requests_per_second"),
+				"Invalid TrueNAS Rate Limit",
+				"requests_per_second must not be negative.",
+			)
+		}
+		if !config.RateLimit.Burst.IsNull() && rateBurst < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rate_limit").AtName("burst"),
+				"Invalid TrueNAS Rate Limit",
+				"burst must be at least 1.",
+			)
+		}
+
+		var limits map[string]int64
+		resp.Diagnostics.Append(config.RateLimit.Concurrency.ElementsAs(ctx, &limits, false)...)
+		concurrency = make(map[string]int, len(limits))
+		for namespace, n := range limits {
+			if n < 1 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("rate_limit").AtName("concurrency").AtMapKey(namespace),
+					"Invalid TrueNAS Concurrency Limit",
+					fmt.Sprintf("The concurrency limit of %s must be at least 1, got %d.", namespace, n),
+				)
+			}
+			concurrency[namespace] = int(n)
+		}
+	}
+
+	var wireLog *client.WireLog
+	if wireLogPath := os.Getenv("TRUEFORM_WIRE_LOG"); wireLogPath != "" {
+		var err error
+		wireLog, err = client.OpenWireLog(wireLogPath)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Open TrueNAS Wire Log",
+				"Could not open the file set in TRUEFORM_WIRE_LOG: "+err.Error(),
+			)
+		}
+	}
+
+	cacheTTL := parseDuration(&resp.Diagnostics, "read_cache_ttl", "Invalid TrueNAS Read Cache TTL", readCacheTTL, "30s")
+	requestTimeoutDuration := parseDuration(&resp.Diagnostics, "request_timeout", "Invalid TrueNAS Request Timeout", requestTimeout, "30s")
+	jobTimeoutDuration := parseDuration(&resp.Diagnostics, "job_timeout", "Invalid TrueNAS Job Timeout", jobTimeout, "30m")
+
 	if resp.Diagnostics.HasError() {
+		if wireLog != nil {
+			_ = wireLog.Close()
+		}
 		return
 	}
 
 	// Create API client
 	tflog.Debug(ctx, "Creating TrueNAS API client", map[string]interface{}{
-		"host":       host,
-		"verify_ssl": verifySSL,
+		"host":            host,
+		"hosts":           hosts,
+		"verify_ssl":      verifySSL,
+		"username":        username,
+		"tls_server_name": tlsServerName,
+		"tls_pinned":      tlsFingerprint != "",
+		"tls_custom_ca":   caCertPEM != "",
+		"tls_client_cert": clientCertPEM != "",
+		"proxy_url":       config.ProxyURL.ValueString(),
+		"ssh_tunnel":      sshTunnel != nil,
+		"max_connections": maxConnections,
+		"rate_limit":      rateLimit,
+		"read_cache_ttl":  cacheTTL.String(),
+		"request_timeout": requestTimeoutDuration.String(),
+		"job_timeout":     jobTimeoutDuration.String(),
+		"wire_log":        os.Getenv("TRUEFORM_WIRE_LOG"),
 	})
 
 	apiClient := client.NewClient(&client.Config{
 		Host:      host,
+		Hosts:     hosts,
 		APIKey:    apiKey,
+		Username:  username,
+		Password:  password,
+		OTPToken:  otpToken,
 		VerifySSL: verifySSL,
+
+		Timeout:    requestTimeoutDuration,
+		JobTimeout: jobTimeoutDuration,
+
+		CACertPEM:      caCertPEM,
+		TLSServerName:  tlsServerName,
+		TLSFingerprint: tlsFingerprint,
+		ClientCertPEM:  clientCertPEM,
+		ClientKeyPEM:   clientKeyPEM,
+
+		ProxyURL:  config.ProxyURL.ValueString(),
+		SSHTunnel: sshTunnel,
+
+		Transport: p.transport,
+		Recorder:  p.recorder,
+		WireLog:   wireLog,
+
+		MaxConnections: int(maxConnections),
+		RateLimit:      rateLimit,
+		RateBurst:      rateBurst,
+		Concurrency:    concurrency,
+		CacheTTL:       cacheTTL,
+
+		TracerProvider: p.tracerProvider,
 	})
 
 	// Test connection
@@ -137,14 +633,145 @@ func (p *TrueformProvider) Configure(ctx context.Context, req provider.Configure
 			"An unexpected error occurred when creating the TrueNAS API client. "+
 				"Error: "+err.Error(),
 		)
+		_ = apiClient.Close()
 		return
 	}
+	trackClient(apiClient)
 
-	tflog.Info(ctx, "Successfully connected to TrueNAS")
+	tflog.Info(ctx, "Successfully connected to TrueNAS", map[string]interface{}{
+		"version":      apiClient.Version().String(),
+		"capabilities": apiClient.Capabilities(),
+	})
 
-	// Make the client available to resources and data sources
+	// Make the client available to resources, data sources and actions
 	resp.DataSourceData = apiClient
 	resp.ResourceData = apiClient
+	resp.ActionData = apiClient
+}
+
+// clients are the API clients configured in this process, which Shutdown
+// closes
+var (
+	clientsMu sync.Mutex
+	clients   []*client.Client
+)
+
+func trackClient(c *client.Client) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	clients = append(clients, c)
+}
+
+// Shutdown closes the API clients configured in this process, which flushes
+// and closes their wire logs. It must be called once the provider server has
+// stopped.
+func Shutdown() error {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	var errs []error
+	for _, c := range clients {
+		errs = append(errs, c.Close())
+	}
+	clients = nil
+	return errors.Join(errs...)
+}
+
+// validateAuthConfig checks that exactly one authentication method is
+// configured: an API key, or a username and password (optionally with an OTP
+// token).
+func validateAuthConfig(apiKey, username, password, otpToken string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	usePassword := username != "" || password != ""
+
+	switch {
+	case apiKey != "" && usePassword:
+		diags.AddAttributeError(
+			path.Root("api_key"),
+			"Conflicting TrueNAS Authentication Methods",
+			"Both an API key and a username/password were configured. "+
+				"Set either api_key (TRUENAS_API_KEY) or username and password (TRUENAS_USERNAME, TRUENAS_PASSWORD), not both.",
+		)
+	case apiKey == "" && !usePassword:
+		diags.AddAttributeError(
+			path.Root("api_key"),
+			"Missing TrueNAS Credentials",
+			"The provider cannot create the TrueNAS API client without credentials. "+
+				"Set the api_key value in the configuration or use the TRUENAS_API_KEY environment variable, "+
+				"set api_key_file or credential_process, select a profile of the credentials file, "+
+				"or set username and password (TRUENAS_USERNAME, TRUENAS_PASSWORD).",
+		)
+	case usePassword && username == "":
+		diags.AddAttributeError(
+			path.Root("username"),
+			"Missing TrueNAS Username",
+			"A password was configured without a username. "+
+				"Set the username value in the configuration or use the TRUENAS_USERNAME environment variable.",
+		)
+	case usePassword && password == "":
+		diags.AddAttributeError(
+			path.Root("password"),
+			"Missing TrueNAS Password",
+			"A username was configured without a password. "+
+				"Set the password value in the configuration or use the TRUENAS_PASSWORD environment variable.",
+		)
+	}
+
+	if otpToken != "" && apiKey != "" && !usePassword {
+		diags.AddAttributeError(
+			path.Root("otp_token"),
+			"Unexpected TrueNAS OTP Token",
+			"otp_token is only used with username and password authentication. Remove it when authenticating with an API key.",
+		)
+	}
+
+	return diags
+}
+
+// validateSSHTunnelConfig checks that an ssh_tunnel block has everything
+// needed to open the tunnel.
+func validateSSHTunnelConfig(tunnel *client.SSHTunnelConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"host", tunnel.Host},
+		{"user", tunnel.User},
+		{"private_key", tunnel.PrivateKey},
+	}
+	for _, attr := range required {
+		if attr.value == "" {
+			diags.AddAttributeError(
+				path.Root("ssh_tunnel").AtName(attr.name),
+				"Missing SSH Tunnel Setting",
+				fmt.Sprintf("The ssh_tunnel block requires %s.", attr.name),
+			)
+		}
+	}
+
+	return diags
+}
+
+// parseDuration parses the duration setting of an attribute, adding an
+// error to diags if it is invalid or negative. An empty value is zero.
+func parseDuration(diags *diag.Diagnostics, name, summary, value, example string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && d < 0 {
+		err = fmt.Errorf("duration %q is negative", value)
+	}
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(name),
+			summary,
+			fmt.Sprintf("%s must be a duration such as %s: %s", name, example, err),
+		)
+	}
+	return d
 }
 
 func (p *TrueformProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -178,3 +805,25 @@ func (p *TrueformProvider) DataSources(ctx context.Context) []func() datasource.
 		datasources.NewVMDataSource,
 	}
 }
+
+func (p *TrueformProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		functions.NewParseSizeFunction,
+		functions.NewFormatSizeFunction,
+		functions.NewDatasetPathFunction,
+		functions.NewParseSnapshotIDFunction,
+		functions.NewParseCronFunction,
+		functions.NewIQNFunction,
+	}
+}
+
+func (p *TrueformProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		actions.NewVMPowerAction,
+		actions.NewPoolScrubAction,
+		actions.NewCronjobRunAction,
+		actions.NewServiceRestartAction,
+		actions.NewSnapshotRollbackAction,
+		actions.NewAppRedeployAction,
+	}
+}
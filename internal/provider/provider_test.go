@@ -3,7 +3,11 @@ package provider
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 )
 
@@ -47,6 +51,65 @@ func TestProviderSchema(t *testing.T) {
 	if _, ok := schema.Attributes["verify_ssl"]; !ok {
 		t.Error("Schema missing 'verify_ssl' attribute")
 	}
+	for _, name := range []string{"username", "password", "otp_token"} {
+		if _, ok := schema.Attributes[name]; !ok {
+			t.Errorf("Schema missing '%s' attribute", name)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "30s", want: 30 * time.Second},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "30", wantErr: true},
+		{value: "-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var diags diag.Diagnostics
+		got := parseDuration(&diags, "job_timeout", "Invalid TrueNAS Job Timeout", tt.value, "30m")
+		if diags.HasError() != tt.wantErr {
+			t.Errorf("parseDuration(%q) diagnostics = %v, wantErr %v", tt.value, diags, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAuthConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiKey   string
+		username string
+		password string
+		otpToken string
+		wantErr  bool
+	}{
+		{name: "api key", apiKey: "1-abc"},
+		{name: "username and password", username: "admin", password: "secret"},
+		{name: "username, password and otp", username: "admin", password: "secret", otpToken: "123456"},
+		{name: "no credentials", wantErr: true},
+		{name: "api key and password", apiKey: "1-abc", username: "admin", password: "secret", wantErr: true},
+		{name: "username without password", username: "admin", wantErr: true},
+		{name: "password without username", password: "secret", wantErr: true},
+		{name: "otp with api key", apiKey: "1-abc", otpToken: "123456", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateAuthConfig(tt.apiKey, tt.username, tt.password, tt.otpToken)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("validateAuthConfig() errors = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
 }
 
 func TestProviderResources(t *testing.T) {
@@ -112,3 +175,55 @@ func TestProviderDataSources(t *testing.T) {
 		}
 	}
 }
+
+func TestProviderFunctions(t *testing.T) {
+	p := New("test")().(provider.ProviderWithFunctions)
+
+	expectedFunctions := []string{
+		"parse_size",
+		"format_size",
+		"dataset_path",
+		"parse_snapshot_id",
+		"parse_cron",
+		"iqn",
+	}
+
+	funcs := p.Functions(context.Background())
+	if len(funcs) != len(expectedFunctions) {
+		t.Fatalf("Expected %d functions, got %d", len(expectedFunctions), len(funcs))
+	}
+
+	for i, funcFunc := range funcs {
+		resp := &function.MetadataResponse{}
+		funcFunc().Metadata(context.Background(), function.MetadataRequest{}, resp)
+		if resp.Name != expectedFunctions[i] {
+			t.Errorf("Function %d is named %q, want %q", i, resp.Name, expectedFunctions[i])
+		}
+	}
+}
+
+func TestProviderActions(t *testing.T) {
+	p := New("test")().(provider.ProviderWithActions)
+
+	expectedActions := []string{
+		"trueform_vm_power",
+		"trueform_pool_scrub",
+		"trueform_cronjob_run",
+		"trueform_service_restart",
+		"trueform_snapshot_rollback",
+		"trueform_app_redeploy",
+	}
+
+	actions := p.Actions(context.Background())
+	if len(actions) != len(expectedActions) {
+		t.Fatalf("Expected %d actions, got %d", len(expectedActions), len(actions))
+	}
+
+	for i, actionFunc := range actions {
+		resp := &action.MetadataResponse{}
+		actionFunc().Metadata(context.Background(), action.MetadataRequest{ProviderTypeName: "trueform"}, resp)
+		if resp.TypeName != expectedActions[i] {
+			t.Errorf("Action %d is named %q, want %q", i, resp.TypeName, expectedActions[i])
+		}
+	}
+}
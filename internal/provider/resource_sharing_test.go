@@ -0,0 +1,396 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+// testAccShareDatasetConfig creates tank/share for the share tests
+const testAccShareDatasetConfig = testAccPoolConfig + `
+resource "trueform_dataset" "share" {
+  pool = trueform_pool.test.name
+  name = "share"
+}
+`
+
+func TestAccShareSMBResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccShareDatasetConfig + `
+resource "trueform_share_smb" "test" {
+  name    = "media"
+  path    = trueform_dataset.share.mountpoint
+  comment = "created by test"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "path", "/mnt/tank/share"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "ro", "false"),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + testAccShareDatasetConfig + `
+resource "trueform_share_smb" "test" {
+  name    = "media"
+  path    = trueform_dataset.share.mountpoint
+  comment = "updated by test"
+  enabled = false
+  ro      = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "comment", "updated by test"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "ro", "true"),
+				),
+			},
+			{
+				ResourceName:      "trueform_share_smb.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccShareSMBResourceOptions manages a share on 25.10, which only
+// accepts ro and abe under their new names and the host lists, path_suffix,
+// auxsmbconf and the other flags under options. It still updates ro and
+// abe, but a flag it only accepts on create is rejected at plan time
+// instead of silently dropped.
+func TestAccShareSMBResourceOptions(t *testing.T) {
+	srv := testAccServer(t)
+	srv.SetVersion("TrueNAS-25.10.0")
+
+	config := func(ro bool, hosts, aux string, guestok bool) string {
+		return srv.ProviderConfig() + testAccShareDatasetConfig + fmt.Sprintf(`
+resource "trueform_share_smb" "test" {
+  name        = "media"
+  path        = trueform_dataset.share.mountpoint
+  path_suffix = "%%U"
+  ro          = %t
+  abe         = %t
+  hostsallow  = [%q]
+  auxsmbconf  = %q
+  guestok     = %t
+}
+`, ro, ro, hosts, aux, guestok)
+	}
+	// stored checks a field of the share as 25.10 returns it, given as
+	// options.<name> for the fields under options
+	stored := func(field string, want interface{}) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			shares := srv.Records("sharing.smb")
+			if len(shares) != 1 {
+				return fmt.Errorf("%d shares, want 1", len(shares))
+			}
+			got := shares[0][field]
+			if name, ok := strings.CutPrefix(field, "options."); ok {
+				options, _ := shares[0]["options"].(map[string]interface{})
+				got = options[name]
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				return fmt.Errorf("share %s = %v, want %v", field, got, want)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(false, "10.0.0.0/8", "veto files = /.DS_Store/", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "ro", "false"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "path_suffix", "%U"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "auxsmbconf", "veto files = /.DS_Store/"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "hostsallow.0", "10.0.0.0/8"),
+					stored("options.path_suffix", "%U"),
+					stored("options.auxsmbconf", "veto files = /.DS_Store/"),
+				),
+			},
+			{
+				Config: config(true, "192.168.1.0/24", "hide dot files = yes", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "ro", "true"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "abe", "true"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "auxsmbconf", "hide dot files = yes"),
+					resource.TestCheckResourceAttr("trueform_share_smb.test", "hostsallow.0", "192.168.1.0/24"),
+					stored("readonly", true),
+					stored("access_based_share_enumeration", true),
+					stored("options.hostsallow", []interface{}{"192.168.1.0/24"}),
+				),
+			},
+			{
+				ResourceName:      "trueform_share_smb.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:      config(true, "192.168.1.0/24", "hide dot files = yes", true),
+				ExpectError: regexp.MustCompile(`(?s)Unsupported by TrueNAS 25\.10\.0.*"guestok" can only be set when an SMB share`),
+			},
+		},
+	})
+}
+
+// TestAccShareSMBResourceVersionUnknown imports a share in the pre-25.10
+// shape from a TrueNAS whose version can't be detected, which the client
+// takes for the newest release
+func TestAccShareSMBResourceVersionUnknown(t *testing.T) {
+	srv := testAccServer(t)
+	unavailable := func([]json.RawMessage) (interface{}, error) {
+		return nil, &testserver.Error{Code: testserver.CodeMethodNotFound, Message: "Method does not exist"}
+	}
+	srv.Handle("system.version", unavailable)
+	srv.Handle("system.info", unavailable)
+	srv.Seed("sharing.smb", map[string]interface{}{
+		"id":         float64(1),
+		"name":       "media",
+		"path":       "/mnt/tank/media",
+		"enabled":    true,
+		"purpose":    "DEFAULT_SHARE",
+		"browsable":  true,
+		"ro":         true,
+		"abe":        true,
+		"hostsallow": []interface{}{"192.168.1.0/24"},
+		"hostsdeny":  []interface{}{"ALL"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_share_smb" "test" {
+  name = "media"
+  path = "/mnt/tank/media"
+}
+`,
+				ResourceName:  "trueform_share_smb.test",
+				ImportState:   true,
+				ImportStateId: "1",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					want := map[string]string{
+						"ro":           "true",
+						"abe":          "true",
+						"hostsallow.#": "1",
+						"hostsallow.0": "192.168.1.0/24",
+						"hostsdeny.0":  "ALL",
+					}
+					for key, value := range want {
+						if got := states[0].Attributes[key]; got != value {
+							return fmt.Errorf("%s = %q, want %q", key, got, value)
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccShareSMBResourceInvalidPath(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_share_smb" "test" {
+  name = "media"
+  path = "/mnt/tank/missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`Path does not exist`),
+			},
+		},
+	})
+}
+
+func TestAccShareNFSResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccShareDatasetConfig + `
+resource "trueform_share_nfs" "test" {
+  path     = trueform_dataset.share.mountpoint
+  networks = ["192.168.1.0/24"]
+  comment  = "created by test"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_nfs.test", "networks.#", "1"),
+					resource.TestCheckResourceAttr("trueform_share_nfs.test", "networks.0", "192.168.1.0/24"),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + testAccShareDatasetConfig + `
+resource "trueform_share_nfs" "test" {
+  path     = trueform_dataset.share.mountpoint
+  networks = ["192.168.1.0/24", "10.0.0.0/8"]
+  ro       = true
+  comment  = "created by test"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_nfs.test", "networks.#", "2"),
+					resource.TestCheckResourceAttr("trueform_share_nfs.test", "ro", "true"),
+				),
+			},
+			{
+				ResourceName:      "trueform_share_nfs.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccShareNFSResourceRateLimit creates NFS shares in parallel through a
+// rate-limited provider with several connections
+func TestAccShareNFSResourceRateLimit(t *testing.T) {
+	srv := testAccServer(t)
+	providerConfig := func(concurrency int) string {
+		return fmt.Sprintf(`
+provider "trueform" {
+  host            = %q
+  api_key         = %q
+  verify_ssl      = false
+  max_connections = 4
+
+  rate_limit {
+    requests_per_second = 50
+    burst               = 10
+    concurrency         = { "pool.dataset" = %d }
+  }
+}
+`, srv.Host(), testserver.APIKey, concurrency)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig(0) + testAccPoolConfig,
+				ExpectError: regexp.MustCompile(`Invalid TrueNAS Concurrency Limit`),
+			},
+			{
+				Config: providerConfig(2) + testAccPoolConfig + `
+resource "trueform_dataset" "share" {
+  count = 4
+  pool  = trueform_pool.test.name
+  name  = "share${count.index}"
+}
+
+resource "trueform_share_nfs" "test" {
+  count = 4
+  path  = trueform_dataset.share[count.index].mountpoint
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_share_nfs.test.0", "path", "/mnt/tank/share0"),
+					resource.TestCheckResourceAttr("trueform_share_nfs.test.3", "path", "/mnt/tank/share3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccISCSIResources(t *testing.T) {
+	srv := testAccServer(t)
+
+	config := srv.ProviderConfig() + testAccShareDatasetConfig + `
+resource "trueform_iscsi_portal" "test" {
+  comment              = "test portal"
+  discovery_authmethod = "NONE"
+  listen = [
+    {
+      ip = "0.0.0.0"
+    }
+  ]
+}
+
+resource "trueform_iscsi_initiator" "test" {
+  comment      = "test initiator"
+  initiators   = ["iqn.2024-01.com.example:test"]
+  auth_network = []
+}
+
+resource "trueform_iscsi_target" "test" {
+  name  = "test-target"
+  alias = "Test Target"
+}
+
+resource "trueform_iscsi_extent" "test" {
+  name     = "test-extent"
+  type     = "FILE"
+  path     = "${trueform_dataset.share.mountpoint}/extent.img"
+  filesize = 10485760
+  comment  = "test extent"
+}
+
+resource "trueform_iscsi_targetextent" "test" {
+  target = trueform_iscsi_target.test.id
+  extent = trueform_iscsi_extent.test.id
+  lunid  = 0
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_iscsi_portal.test", "listen.0.port", "3260"),
+					resource.TestCheckResourceAttr("trueform_iscsi_target.test", "name", "test-target"),
+					resource.TestCheckResourceAttrSet("trueform_iscsi_extent.test", "serial"),
+					resource.TestCheckResourceAttrPair("trueform_iscsi_targetextent.test", "target", "trueform_iscsi_target.test", "id"),
+				),
+			},
+			{
+				ResourceName:      "trueform_iscsi_portal.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "trueform_iscsi_initiator.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "trueform_iscsi_target.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "trueform_iscsi_extent.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "trueform_iscsi_targetextent.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,426 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAccPoolResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccPoolConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_pool.test", "name", "tank"),
+					resource.TestCheckResourceAttr("trueform_pool.test", "status", "ONLINE"),
+					resource.TestCheckResourceAttr("trueform_pool.test", "path", "/mnt/tank"),
+					resource.TestCheckResourceAttr("trueform_pool.test", "topology.0.disks.#", "2"),
+				),
+			},
+			{
+				ResourceName:            "trueform_pool.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"encryption_options"},
+			},
+		},
+	})
+}
+
+func TestAccDatasetResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccPoolConfig + `
+resource "trueform_dataset" "test" {
+  pool        = trueform_pool.test.name
+  name        = "data"
+  comments    = "created by test"
+  compression = "LZ4"
+  atime       = "OFF"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_dataset.test", "id", "tank/data"),
+					resource.TestCheckResourceAttr("trueform_dataset.test", "mountpoint", "/mnt/tank/data"),
+					resource.TestCheckResourceAttr("trueform_dataset.test", "comments", "created by test"),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + testAccPoolConfig + `
+resource "trueform_dataset" "test" {
+  pool        = trueform_pool.test.name
+  name        = "data"
+  comments    = "updated by test"
+  compression = "ZSTD"
+  atime       = "OFF"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_dataset.test", "comments", "updated by test"),
+					resource.TestCheckResourceAttr("trueform_dataset.test", "compression", "ZSTD"),
+				),
+			},
+			{
+				ResourceName:      "trueform_dataset.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccDatasetResourceReadCache refreshes several datasets with the read
+// cache enabled, which must serve them all from bulk queries and still see
+// the provider's own updates.
+func TestAccDatasetResourceReadCache(t *testing.T) {
+	srv := testAccServer(t)
+	t.Setenv("TRUENAS_READ_CACHE_TTL", "1m")
+
+	config := func(comments string) string {
+		return srv.ProviderConfig() + testAccPoolConfig + fmt.Sprintf(`
+resource "trueform_dataset" "test" {
+  count    = 5
+  pool     = trueform_pool.test.name
+  name     = "data${count.index}"
+  comments = %q
+}
+`, comments)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("created by test"),
+				Check:  resource.TestCheckResourceAttr("trueform_dataset.test.4", "comments", "created by test"),
+			},
+			{
+				Config: config("updated by test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_dataset.test.0", "comments", "updated by test"),
+					resource.TestCheckResourceAttr("trueform_dataset.test.4", "comments", "updated by test"),
+					func(*terraform.State) error {
+						if calls := srv.CallCount("pool.dataset.get_instance"); calls != 0 {
+							return fmt.Errorf("pool.dataset.get_instance called %d times, want 0", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccPoolResourceTimeouts(t *testing.T) {
+	srv := testAccServer(t)
+	t.Setenv("TRUENAS_JOB_TIMEOUT", "2m")
+
+	config := func(create string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "trueform_pool" "test" {
+  name = "tank"
+
+  topology = [
+    {
+      type  = "data"
+      disks = ["sdb", "sdc"]
+    }
+  ]
+
+  timeouts {
+    create = %q
+    delete = "5m"
+  }
+}
+`, create)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("soon"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Time Duration`),
+			},
+			{
+				Config: config("30m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_pool.test", "timeouts.create", "30m"),
+					resource.TestCheckResourceAttr("trueform_pool.test", "status", "ONLINE"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccPoolResourceDeleteJob destroys a pool whose pool.export job hangs,
+// then fails, then succeeds. Delete must wait for the job, abort it once
+// the delete timeout passes, and report a failed export.
+func TestAccPoolResourceDeleteJob(t *testing.T) {
+	srv := testAccServer(t)
+
+	config := srv.ProviderConfig() + `
+resource "trueform_pool" "test" {
+  name = "tank"
+
+  topology = [
+    {
+      type  = "data"
+      disks = ["sdb", "sdc"]
+    }
+  ]
+
+  timeouts {
+    delete = "1s"
+  }
+}
+`
+	var hung int64
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("trueform_pool.test", "timeouts.delete", "1s"),
+			},
+			{
+				PreConfig: func() {
+					srv.Handle("pool.export", func(params []json.RawMessage) (interface{}, error) {
+						hung, _ = srv.StartJob("pool.export", params)
+						return hung, nil
+					})
+				},
+				Config:      srv.ProviderConfig(),
+				ExpectError: regexp.MustCompile(`(?s)Error Deleting Pool.*timeout waiting for job.*was\s+aborted\s+on\s+TrueNAS`),
+			},
+			{
+				PreConfig: func() {
+					if job, _ := srv.Job(hung); job["state"] != "ABORTED" {
+						t.Errorf("pool.export job state = %v, want ABORTED", job["state"])
+					}
+					if len(srv.Records("pool")) != 1 {
+						t.Error("pool removed by an aborted export")
+					}
+					srv.Handle("pool.export", func(params []json.RawMessage) (interface{}, error) {
+						id, finish := srv.StartJob("pool.export", params)
+						go func() {
+							time.Sleep(100 * time.Millisecond)
+							finish(nil, errors.New("[EBUSY] pool is busy"))
+						}()
+						return id, nil
+					})
+				},
+				Config:      srv.ProviderConfig(),
+				ExpectError: regexp.MustCompile(`(?s)Error Deleting Pool.*pool\s+is\s+busy`),
+			},
+			{
+				PreConfig: func() { srv.Handle("pool.export", nil) },
+				Config:    srv.ProviderConfig(),
+				Check: func(*terraform.State) error {
+					if n := len(srv.Records("pool")); n != 0 {
+						return fmt.Errorf("%d pools left after delete, want 0", n)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccPoolResourceTracing(t *testing.T) {
+	srv := testAccServer(t)
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	p := &TrueformProvider{version: "test", tracerProvider: tp}
+
+	// checkSpan checks that an operation's span exists and has a child span
+	// named child
+	checkSpan := func(name, child string) error {
+		spans := exporter.GetSpans()
+		for _, span := range spans {
+			if span.Name != name {
+				continue
+			}
+			for _, c := range spans {
+				if c.Name == child && c.Parent.SpanID() == span.SpanContext.SpanID() {
+					return nil
+				}
+			}
+			return fmt.Errorf("span %q has no %q child", name, child)
+		}
+		return fmt.Errorf("no %q span in %d spans", name, len(spans))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"trueform": func() (tfprotov6.ProviderServer, error) {
+				return NewTracingServer(providerserver.NewProtocol6(p)(), tp), nil
+			},
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccPoolConfig,
+				Check: func(*terraform.State) error {
+					if err := checkSpan("Create trueform_pool", "pool.create"); err != nil {
+						return err
+					}
+					return checkSpan("Create trueform_pool", "WaitForJob")
+				},
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if err := checkSpan("Read trueform_pool", "pool.get_instance"); err != nil {
+				return err
+			}
+			return checkSpan("Delete trueform_pool", "pool.export")
+		},
+	})
+}
+
+func TestAccPoolResourceWireLog(t *testing.T) {
+	srv := testAccServer(t)
+	path := filepath.Join(t.TempDir(), "wire.jsonl")
+	t.Setenv("TRUEFORM_WIRE_LOG", path)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccPoolConfig,
+			},
+		},
+	})
+
+	clientsMu.Lock()
+	configured := len(clients)
+	clientsMu.Unlock()
+	if configured == 0 {
+		t.Fatal("no clients to close")
+	}
+	if err := Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if len(clients) != 0 {
+		t.Errorf("%d clients left after Shutdown()", len(clients))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"pool.create", "pool.export"} {
+		if !strings.Contains(string(data), `"method":"`+method+`"`) {
+			t.Errorf("wire log has no %s call", method)
+		}
+	}
+}
+
+func TestAccSnapshotResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccPoolConfig + `
+resource "trueform_dataset" "test" {
+  pool = trueform_pool.test.name
+  name = "data"
+}
+
+resource "trueform_snapshot" "test" {
+  dataset = trueform_dataset.test.id
+  name    = "before-upgrade"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_snapshot.test", "id", "tank/data@before-upgrade"),
+					resource.TestCheckResourceAttrSet("trueform_snapshot.test", "creation_time"),
+				),
+			},
+			{
+				ResourceName:            "trueform_snapshot.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"recursive", "vmware_sync"},
+			},
+		},
+	})
+}
+
+// TestAccDatasetCassette replays a dataset lifecycle, shared over SMB, from
+// each release, to catch regressions in how read handles the shapes
+// different versions return (e.g. comments under user_properties). 25.10
+// returns ro, abe and the host lists under new names and no longer echoes
+// flags such as timemachine, which must still plan and import cleanly.
+// Recording needs an existing pool named tank.
+func TestAccDatasetCassette(t *testing.T) {
+	for _, release := range []string{"25.04", "25.10"} {
+		t.Run(release, func(t *testing.T) {
+			factories, providerConfig := testAccCassette(t, "dataset-"+release)
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: factories,
+				Steps: []resource.TestStep{
+					{
+						Config: providerConfig + `
+resource "trueform_dataset" "test" {
+  pool     = "tank"
+  name     = "trueform-cassette"
+  comments = "recorded"
+}
+
+resource "trueform_share_smb" "test" {
+  name       = "trueform-cassette"
+  path       = trueform_dataset.test.mountpoint
+  ro         = true
+  abe        = true
+  hostsallow = ["192.168.1.0/24"]
+}
+`,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("trueform_dataset.test", "comments", "recorded"),
+							resource.TestCheckResourceAttr("trueform_share_smb.test", "ro", "true"),
+							resource.TestCheckResourceAttr("trueform_share_smb.test", "abe", "true"),
+							resource.TestCheckResourceAttr("trueform_share_smb.test", "hostsallow.#", "1"),
+							resource.TestCheckResourceAttr("trueform_share_smb.test", "hostsallow.0", "192.168.1.0/24"),
+							resource.TestCheckResourceAttr("trueform_share_smb.test", "timemachine", "false"),
+							resource.TestCheckResourceAttr("trueform_share_smb.test", "streams", "true"),
+						),
+					},
+					{
+						ResourceName:      "trueform_dataset.test",
+						ImportState:       true,
+						ImportStateVerify: true,
+					},
+					{
+						ResourceName:      "trueform_share_smb.test",
+						ImportState:       true,
+						ImportStateVerify: true,
+					},
+				},
+			})
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func TestAccUserResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_user" "test" {
+  username  = "alice"
+  full_name = "Alice"
+  password  = "correct-horse"
+  email     = "alice@example.com"
+  shell     = "/usr/sbin/nologin"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_user.test", "uid", "3000"),
+					resource.TestCheckResourceAttrSet("trueform_user.test", "group"),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_user" "test" {
+  username  = "alice"
+  full_name = "Alice Liddell"
+  password  = "correct-horse"
+  email     = "alice@example.com"
+  shell     = "/usr/bin/bash"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_user.test", "full_name", "Alice Liddell"),
+					resource.TestCheckResourceAttr("trueform_user.test", "shell", "/usr/bin/bash"),
+				),
+			},
+			{
+				ResourceName:            "trueform_user.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func TestAccCronjobResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_cronjob" "test" {
+  user        = "root"
+  command     = "echo hello"
+  description = "created by test"
+  enabled     = false
+  schedule = {
+    minute = "0"
+    hour   = "3"
+    dom    = "*"
+    month  = "*"
+    dow    = "*"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_cronjob.test", "schedule.hour", "3"),
+				),
+			},
+			{
+				ResourceName:      "trueform_cronjob.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCertificateResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_certificate" "test" {
+  name        = "web"
+  type        = "CERTIFICATE_CREATE_INTERNAL"
+  key_length  = 2048
+  lifetime    = 365
+  common_name = "truenas.example.com"
+  san         = ["truenas.example.com"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_certificate.test", "common_name", "truenas.example.com"),
+					resource.TestCheckResourceAttrSet("trueform_certificate.test", "fingerprint"),
+					resource.TestCheckResourceAttrSet("trueform_certificate.test", "not_after"),
+					resource.TestCheckNoResourceAttr("trueform_certificate.test", "csr"),
+					// Key type and digest algorithm are left to TrueNAS
+					resource.TestCheckResourceAttr("trueform_certificate.test", "key_type", "RSA"),
+					resource.TestCheckResourceAttr("trueform_certificate.test", "digest_algorithm", "SHA256"),
+					func(*terraform.State) error {
+						// The test server gives certificates 128-bit serials
+						if rec, _ := srv.Record("certificate", 1); len(fmt.Sprint(rec["serial"])) < 39 {
+							return fmt.Errorf("serial = %v, want a 128-bit serial", rec["serial"])
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "trueform_certificate.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccCertificateResourceImportLargeSerial imports a certificate whose
+// serial doesn't fit an int64
+func TestAccCertificateResourceImportLargeSerial(t *testing.T) {
+	srv := testAccServer(t)
+	srv.Seed("certificate", map[string]interface{}{
+		"id":          float64(7),
+		"name":        "ca",
+		"type":        float64(8),
+		"certificate": "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n",
+		"common":      "Example CA",
+		"key_length":  float64(4096),
+		"key_type":    "RSA",
+		"lifetime":    float64(3650),
+		"serial":      json.Number("255041209017519066185843963227393618491"),
+		"fingerprint": "12:34:56:78",
+		"from":        "Mon Jan  5 10:00:00 2026",
+		"until":       "Thu Jan  3 10:00:00 2036",
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_certificate" "ca" {
+  name        = "ca"
+  type        = "CERTIFICATE_CREATE_IMPORTED"
+  certificate = "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"
+}
+`,
+				ResourceName:  "trueform_certificate.ca",
+				ImportState:   true,
+				ImportStateId: "7",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					attrs := states[0].Attributes
+					if attrs["common_name"] != "Example CA" || attrs["fingerprint"] != "12:34:56:78" {
+						return fmt.Errorf("imported certificate = %v", attrs)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// TestAccCertificateResourceImportType imports a certificate made on
+// TrueNAS, whose type the API reports as flags
+func TestAccCertificateResourceImportType(t *testing.T) {
+	srv := testAccServer(t)
+	srv.Seed("certificate", map[string]interface{}{
+		"id":          float64(3),
+		"name":        "web",
+		"type":        float64(16),
+		"common":      "truenas.example.com",
+		"key_length":  float64(2048),
+		"key_type":    "RSA",
+		"lifetime":    float64(365),
+		"serial":      float64(3),
+		"fingerprint": "AA:BB:CC:03",
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_certificate" "web" {
+  name = "web"
+  type = "CERTIFICATE_CREATE_INTERNAL"
+}
+`,
+				ResourceName:  "trueform_certificate.web",
+				ImportState:   true,
+				ImportStateId: "3",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if got := states[0].Attributes["type"]; got != "CERTIFICATE_CREATE_INTERNAL" {
+						return fmt.Errorf("imported type = %q, want CERTIFICATE_CREATE_INTERNAL", got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccStaticRouteResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "trueform_static_route" "test" {
+  destination = "10.10.0.0/16"
+  gateway     = "192.168.1.1"
+  description = "created by test"
+}
+`,
+				Check: resource.TestCheckResourceAttr("trueform_static_route.test", "gateway", "192.168.1.1"),
+			},
+			{
+				ResourceName:      "trueform_static_route.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccProviderProfile(t *testing.T) {
+	srv := testAccServer(t)
+
+	// The profile's key is read from a file, and its host and TLS settings
+	// stand in for the provider block's
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "api-key")
+	if err := os.WriteFile(keyFile, []byte(testserver.APIKey+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	credentialsFile := filepath.Join(dir, "credentials")
+	profiles := fmt.Sprintf("[other]\nhost = 192.0.2.1\napi_key = 1-wrong\n\n[lab]\nhost = %s\napi_key_file = %s\nverify_ssl = false\n", srv.Host(), keyFile)
+	if err := os.WriteFile(credentialsFile, []byte(profiles), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"TRUENAS_HOST", "TRUENAS_API_KEY", "TRUENAS_USERNAME", "TRUENAS_PASSWORD", "TRUENAS_VERIFY_SSL", "TRUENAS_PROFILE"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("TRUEFORM_CREDENTIALS_FILE", credentialsFile)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "trueform" {
+  profile = "lab"
+}
+
+resource "trueform_static_route" "test" {
+  destination = "10.20.0.0/16"
+  gateway     = "192.168.1.1"
+  description = "created by test"
+}
+`,
+				Check: resource.TestCheckResourceAttr("trueform_static_route.test", "gateway", "192.168.1.1"),
+			},
+		},
+	})
+}
+
+func TestAccProviderHosts(t *testing.T) {
+	standby := testAccServer(t)
+	standby.SetFailoverStatus("BACKUP")
+	active := testAccServer(t)
+	active.SetFailoverStatus("MASTER")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "trueform" {
+  hosts      = [%q, %q]
+  api_key    = %q
+  verify_ssl = false
+}
+
+resource "trueform_static_route" "test" {
+  destination = "10.30.0.0/16"
+  gateway     = "192.168.1.1"
+  description = "created by test"
+}
+`, standby.Host(), active.Host(), testserver.APIKey),
+				Check: func(*terraform.State) error {
+					if active.CallCount("staticroute.create") != 1 || standby.CallCount("staticroute.create") != 0 {
+						return fmt.Errorf("the route was not created on the active controller")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccVMResources(t *testing.T) {
+	srv := testAccServer(t)
+
+	config := srv.ProviderConfig() + `
+resource "trueform_vm" "test" {
+  name            = "test"
+  description     = "created by test"
+  memory          = 1024
+  vcpus           = 2
+  bootloader_ovmf = "OVMF_CODE.fd"
+}
+
+resource "trueform_vm_device" "nic" {
+  vm       = trueform_vm.test.id
+  dtype    = "NIC"
+  nic_type = "VIRTIO"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_vm.test", "status", "STOPPED"),
+					resource.TestCheckResourceAttrSet("trueform_vm.test", "uuid"),
+					resource.TestCheckResourceAttr("trueform_vm_device.nic", "nic_type", "VIRTIO"),
+				),
+			},
+			{
+				ResourceName:      "trueform_vm.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "trueform_vm_device.nic",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccAppResource(t *testing.T) {
+	srv := testAccServer(t)
+
+	config := srv.ProviderConfig() + testAccPoolConfig + `
+resource "trueform_service_docker" "test" {
+  pool = trueform_pool.test.name
+}
+
+resource "trueform_app" "test" {
+  name        = "web"
+  catalog_app = "nginx"
+  train       = "community"
+  version     = "1.0.0"
+
+  depends_on = [trueform_service_docker.test]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("trueform_service_docker.test", "status", "RUNNING"),
+					resource.TestCheckResourceAttr("trueform_app.test", "state", "RUNNING"),
+					resource.TestCheckResourceAttr("trueform_app.test", "metadata.name", "nginx"),
+				),
+			},
+			{
+				ResourceName:            "trueform_app.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"values"},
+			},
+		},
+	})
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans of Terraform
+// operations; the client's spans are their children
+const tracerName = "github.com/trueform/terraform-provider-trueform/internal/provider"
+
+// StartTracing exports the provider's spans over OTLP if
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set,
+// by installing the global tracer provider. The exporter is configured from
+// the standard OTEL_EXPORTER_OTLP_* variables and speaks http/protobuf unless
+// the protocol is grpc. The returned function flushes the remaining spans and
+// must be called before exiting.
+func StartTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	var exporter *otlptrace.Exporter
+	var err error
+	switch protocol {
+	case "", "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q, use grpc or http/protobuf", protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName("terraform-provider-trueform"),
+			semconv.ServiceVersion(version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the tracing resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+// tracingServer wraps a provider server with a span for every resource and
+// data source operation, so the client's spans of the API calls made for it
+// are grouped together
+type tracingServer struct {
+	tfprotov6.ProviderServer
+	tracer trace.Tracer
+	// parent is the span context from TRACEPARENT, linking the provider's
+	// spans to the run that started Terraform, e.g. a CI job
+	parent context.Context
+}
+
+// NewTracingServer wraps server with a span for every resource CRUD
+// operation and data source read, from tp or the global tracer provider if
+// tp is nil
+func NewTracingServer(server tfprotov6.ProviderServer, tp trace.TracerProvider) tfprotov6.ProviderServer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	carrier := propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	}
+	return &tracingServer{
+		ProviderServer: server,
+		tracer:         tp.Tracer(tracerName),
+		parent:         propagation.TraceContext{}.Extract(context.Background(), carrier),
+	}
+}
+
+// start starts the span of an operation on a resource or data source type
+func (s *tracingServer) start(ctx context.Context, operation, typeName string) (context.Context, trace.Span) {
+	if parent := trace.SpanContextFromContext(s.parent); parent.IsValid() && !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+	return s.tracer.Start(ctx, operation+" "+typeName, trace.WithAttributes(
+		attribute.String("terraform.operation", strings.ToLower(operation)),
+		attribute.String("terraform.type", typeName),
+	))
+}
+
+// end marks span failed if the operation returned an error diagnostic, and
+// ends it
+func end(span trace.Span, diags []*tfprotov6.Diagnostic, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	for _, diag := range diags {
+		if diag != nil && diag.Severity == tfprotov6.DiagnosticSeverityError {
+			span.SetStatus(codes.Error, diag.Summary)
+			break
+		}
+	}
+	span.End()
+}
+
+func (s *tracingServer) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	ctx, span := s.start(ctx, "Configure", "provider")
+	resp, err := s.ProviderServer.ConfigureProvider(ctx, req)
+	if resp != nil {
+		end(span, resp.Diagnostics, err)
+	} else {
+		end(span, nil, err)
+	}
+	return resp, err
+}
+
+func (s *tracingServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	operation := "Update"
+	if isNull(req.PriorState) {
+		operation = "Create"
+	} else if isNull(req.PlannedState) {
+		operation = "Delete"
+	}
+	ctx, span := s.start(ctx, operation, req.TypeName)
+	resp, err := s.ProviderServer.ApplyResourceChange(ctx, req)
+	if resp != nil {
+		end(span, resp.Diagnostics, err)
+	} else {
+		end(span, nil, err)
+	}
+	return resp, err
+}
+
+func (s *tracingServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	ctx, span := s.start(ctx, "Read", req.TypeName)
+	resp, err := s.ProviderServer.ReadResource(ctx, req)
+	if resp != nil {
+		end(span, resp.Diagnostics, err)
+	} else {
+		end(span, nil, err)
+	}
+	return resp, err
+}
+
+func (s *tracingServer) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	ctx, span := s.start(ctx, "Import", req.TypeName)
+	resp, err := s.ProviderServer.ImportResourceState(ctx, req)
+	if resp != nil {
+		end(span, resp.Diagnostics, err)
+	} else {
+		end(span, nil, err)
+	}
+	return resp, err
+}
+
+func (s *tracingServer) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	ctx, span := s.start(ctx, "Read", "data."+req.TypeName)
+	resp, err := s.ProviderServer.ReadDataSource(ctx, req)
+	if resp != nil {
+		end(span, resp.Diagnostics, err)
+	} else {
+		end(span, nil, err)
+	}
+	return resp, err
+}
+
+// isNull reports whether a state Terraform sent is absent or null
+func isNull(v *tfprotov6.DynamicValue) bool {
+	if v == nil {
+		return true
+	}
+	null, err := v.IsNull()
+	return err == nil && null
+}
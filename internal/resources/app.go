@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -41,6 +42,8 @@ type AppResourceModel struct {
 	Values      types.String `tfsdk:"values"`
 	State       types.String `tfsdk:"state"`
 	Metadata    types.Map    `tfsdk:"metadata"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *AppResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -96,6 +99,9 @@ func (r *AppResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				ElementType: types.StringType,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -119,6 +125,14 @@ func (r *AppResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Creating app", map[string]interface{}{
 		"name":        plan.Name.ValueString(),
 		"catalog_app": plan.CatalogApp.ValueString(),
@@ -145,9 +159,9 @@ func (r *AppResource) Create(ctx context.Context, req resource.CreateRequest, re
 		createData["values"] = values
 	}
 
-	_, err := r.client.CreateWithJob(ctx, "app", createData, 5*time.Minute)
+	_, err := r.client.CreateWithJob(ctx, "app", createData, createTimeout, logJobProgress(ctx, "Creating app"))
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating App", "Could not create app: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating App", "Could not create app", err)
 		return
 	}
 
@@ -168,6 +182,14 @@ func (r *AppResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	if err := r.readApp(ctx, state.ID.ValueString(), &state); err != nil {
 		if client.IsNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
@@ -196,6 +218,14 @@ func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Updating app", map[string]interface{}{
 		"name": state.ID.ValueString(),
 	})
@@ -212,9 +242,9 @@ func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	}
 
 	if len(updateData) > 0 {
-		_, err := r.client.UpdateWithJob(ctx, "app", state.ID.ValueString(), updateData, 5*time.Minute)
+		_, err := r.client.UpdateWithJob(ctx, "app", state.ID.ValueString(), updateData, updateTimeout, logJobProgress(ctx, "Updating app"))
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating App", "Could not update app: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating App", "Could not update app", err)
 			return
 		}
 	}
@@ -232,7 +262,7 @@ func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, re
 			resp.Diagnostics.AddError("Error Upgrading App", "Could not upgrade app: "+err.Error())
 			return
 		}
-		if _, err := r.client.WaitForJob(ctx, int64(jobID), 5*time.Minute); err != nil {
+		if _, err := r.client.WaitForJob(ctx, int64(jobID), updateTimeout, logJobProgress(ctx, "Upgrading app")); err != nil {
 			resp.Diagnostics.AddError("Error Upgrading App", "App upgrade job failed: "+err.Error())
 			return
 		}
@@ -255,6 +285,14 @@ func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	appName := state.ID.ValueString()
 
 	tflog.Debug(ctx, "Deleting app", map[string]interface{}{
@@ -274,7 +312,7 @@ func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 			tflog.Debug(ctx, "Could not stop app (may already be stopped), proceeding to delete", map[string]interface{}{
 				"error": err.Error(),
 			})
-		} else if _, err := r.client.WaitForJob(ctx, int64(stopJobID), 5*time.Minute); err != nil {
+		} else if _, err := r.client.WaitForJob(ctx, int64(stopJobID), deleteTimeout, logJobProgress(ctx, "Stopping app")); err != nil {
 			tflog.Debug(ctx, "App stop job failed (may already be stopped), proceeding to delete", map[string]interface{}{
 				"error": err.Error(),
 			})
@@ -300,7 +338,7 @@ func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		resp.Diagnostics.AddError("Error Deleting App", "Could not delete app: "+err.Error())
 		return
 	}
-	if _, err := r.client.WaitForJob(ctx, int64(jobID), 5*time.Minute); err != nil {
+	if _, err := r.client.WaitForJob(ctx, int64(jobID), deleteTimeout, logJobProgress(ctx, "Deleting app")); err != nil {
 		// If the job failed because the app no longer exists, treat as success
 		if isAppNotFoundError(err) {
 			tflog.Debug(ctx, "App already removed during delete job", map[string]interface{}{
@@ -328,7 +366,7 @@ func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 			resp.Diagnostics.AddError("Error Deleting App", "Could not force delete app: "+forceErr.Error())
 			return
 		}
-		if _, forceErr := r.client.WaitForJob(ctx, int64(forceJobID), 5*time.Minute); forceErr != nil {
+		if _, forceErr := r.client.WaitForJob(ctx, int64(forceJobID), deleteTimeout, logJobProgress(ctx, "Force deleting app")); forceErr != nil {
 			if isAppNotFoundError(forceErr) {
 				return
 			}
@@ -343,41 +381,41 @@ func (r *AppResource) ImportState(ctx context.Context, req resource.ImportStateR
 }
 
 func (r *AppResource) readApp(ctx context.Context, name string, model *AppResourceModel) error {
-	var result map[string]interface{}
+	var result api.AppEntry
 	err := r.client.GetInstance(ctx, "app", name, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.StringValue(result["id"].(string))
-	model.Name = types.StringValue(result["name"].(string))
+	model.ID = types.StringValue(result.ID)
+	model.Name = types.StringValue(result.Name)
 
 	// Read version from top-level field (chart/package version)
-	if version, ok := result["version"].(string); ok {
-		model.Version = types.StringValue(version)
+	if result.Version != nil {
+		model.Version = types.StringValue(*result.Version)
 	}
 
 	// Build metadata map for state
 	metadataMap := map[string]attr.Value{}
 
-	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
-		if appVersion, ok := metadata["app_version"].(string); ok {
-			metadataMap["app_version"] = types.StringValue(appVersion)
+	if metadata := result.Metadata; metadata != nil {
+		if metadata.AppVersion != nil {
+			metadataMap["app_version"] = types.StringValue(*metadata.AppVersion)
 		}
-		if train, ok := metadata["train"].(string); ok {
-			model.Train = types.StringValue(train)
-			metadataMap["train"] = types.StringValue(train)
+		if metadata.Train != nil {
+			model.Train = types.StringValue(*metadata.Train)
+			metadataMap["train"] = types.StringValue(*metadata.Train)
 		}
-		if name, ok := metadata["name"].(string); ok {
-			metadataMap["name"] = types.StringValue(name)
-			model.CatalogApp = types.StringValue(name)
+		if metadata.Name != nil {
+			metadataMap["name"] = types.StringValue(*metadata.Name)
+			model.CatalogApp = types.StringValue(*metadata.Name)
 		}
 	}
 
 	model.Metadata, _ = types.MapValue(types.StringType, metadataMap)
 
-	if state, ok := result["state"].(string); ok {
-		model.State = types.StringValue(state)
+	if result.State != nil {
+		model.State = types.StringValue(*result.State)
 	}
 
 	return nil
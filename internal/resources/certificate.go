@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -54,6 +56,8 @@ type CertificateResourceModel struct {
 	Fingerprint      types.String `tfsdk:"fingerprint"`
 	NotBefore        types.String `tfsdk:"not_before"`
 	NotAfter         types.String `tfsdk:"not_after"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *CertificateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -109,10 +113,12 @@ func (r *CertificateResource) Schema(ctx context.Context, req resource.SchemaReq
 			"key_type": schema.StringAttribute{
 				Description: "Key type (RSA, EC).",
 				Optional:    true,
+				Computed:    true,
 			},
 			"digest_algorithm": schema.StringAttribute{
 				Description: "Digest algorithm (SHA256, SHA384, SHA512).",
 				Optional:    true,
+				Computed:    true,
 			},
 			"lifetime": schema.Int64Attribute{
 				Description: "Certificate lifetime in days.",
@@ -170,6 +176,9 @@ func (r *CertificateResource) Schema(ctx context.Context, req resource.SchemaReq
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -193,6 +202,14 @@ func (r *CertificateResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Creating certificate", map[string]interface{}{
 		"name": plan.Name.ValueString(),
 		"type": plan.Type.ValueString(),
@@ -215,10 +232,10 @@ func (r *CertificateResource) Create(ctx context.Context, req resource.CreateReq
 	if !plan.KeyLength.IsNull() {
 		createData["key_length"] = plan.KeyLength.ValueInt64()
 	}
-	if !plan.KeyType.IsNull() {
+	if !plan.KeyType.IsNull() && !plan.KeyType.IsUnknown() {
 		createData["key_type"] = plan.KeyType.ValueString()
 	}
-	if !plan.DigestAlgorithm.IsNull() {
+	if !plan.DigestAlgorithm.IsNull() && !plan.DigestAlgorithm.IsUnknown() {
 		createData["digest_algorithm"] = plan.DigestAlgorithm.ValueString()
 	}
 	if !plan.Lifetime.IsNull() {
@@ -254,14 +271,18 @@ func (r *CertificateResource) Create(ctx context.Context, req resource.CreateReq
 		}
 	}
 
-	var result map[string]interface{}
-	err := r.client.Create(ctx, "certificate", createData, &result)
+	// Certificates are created by a job, which returns the new entry
+	result, err := r.client.CreateWithJob(ctx, "certificate", createData, createTimeout, logJobProgress(ctx, "Creating certificate"))
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Certificate", "Could not create certificate: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating Certificate", "Could not create certificate", err)
 		return
 	}
-
-	certID := int64(result["id"].(float64))
+	id, ok := result["id"].(float64)
+	if !ok {
+		resp.Diagnostics.AddError("Error Creating Certificate", fmt.Sprintf("The certificate.create job returned no certificate ID: %v", result))
+		return
+	}
+	certID := int64(id)
 	if err := r.readCertificate(ctx, certID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading Certificate", "Could not read certificate after creation: "+err.Error())
 		return
@@ -279,6 +300,14 @@ func (r *CertificateResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	if err := r.readCertificate(ctx, state.ID.ValueInt64(), &state); err != nil {
 		if client.IsNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
@@ -307,15 +336,23 @@ func (r *CertificateResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Certificates have very limited update capability
 	// Most fields require recreation
 	updateData := map[string]interface{}{}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.CertificateEntry
 		err := r.client.Update(ctx, "certificate", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating Certificate", "Could not update certificate: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating Certificate", "Could not update certificate", err)
 			return
 		}
 	}
@@ -337,6 +374,14 @@ func (r *CertificateResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.Delete(ctx, "certificate", state.ID.ValueInt64())
 	if err != nil {
 		resp.Diagnostics.AddError("Error Deleting Certificate", "Could not delete certificate: "+err.Error())
@@ -356,77 +401,91 @@ func (r *CertificateResource) ImportState(ctx context.Context, req resource.Impo
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
+// certificateCreateTypes maps the type flags certificate.query reports to
+// the create_type that makes such a certificate
+var certificateCreateTypes = map[int64]string{
+	0x08: "CERTIFICATE_CREATE_IMPORTED",
+	0x10: "CERTIFICATE_CREATE_INTERNAL",
+	0x20: "CERTIFICATE_CREATE_CSR",
+}
+
 func (r *CertificateResource) readCertificate(ctx context.Context, id int64, model *CertificateResourceModel) error {
-	var result map[string]interface{}
+	var result api.CertificateEntry
 	err := r.client.GetInstance(ctx, "certificate", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Name = types.StringValue(result["name"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
 
-	if certType, ok := result["type"].(float64); ok {
-		// Map numeric type back to string
-		model.Type = types.StringValue(fmt.Sprintf("%d", int(certType)))
+	// The API reports the certificate's type as flags rather than the
+	// create_type it was made with. Keep the configured type, and only
+	// derive it when importing.
+	if result.Type != nil && (model.Type.IsNull() || model.Type.IsUnknown()) {
+		if createType, ok := certificateCreateTypes[*result.Type]; ok {
+			model.Type = types.StringValue(createType)
+		}
 	}
-	if csr, ok := result["CSR"].(string); ok {
-		model.CSR = types.StringValue(csr)
+	if result.CSR != nil {
+		model.CSR = types.StringValue(*result.CSR)
+	} else {
+		model.CSR = types.StringNull()
 	}
-	if signedBy, ok := result["signedby"].(float64); ok {
+	if signedBy, ok := result.Signedby.(float64); ok {
 		model.SignedBy = types.Int64Value(int64(signedBy))
 	}
-	if keyLength, ok := result["key_length"].(float64); ok {
-		model.KeyLength = types.Int64Value(int64(keyLength))
+	if result.KeyLength != nil {
+		model.KeyLength = types.Int64Value(*result.KeyLength)
 	}
-	if keyType, ok := result["key_type"].(string); ok {
-		model.KeyType = types.StringValue(keyType)
+	if result.KeyType != nil {
+		model.KeyType = types.StringValue(*result.KeyType)
+	} else if model.KeyType.IsUnknown() {
+		model.KeyType = types.StringNull()
 	}
-	if digestAlgorithm, ok := result["digest_algorithm"].(string); ok {
-		model.DigestAlgorithm = types.StringValue(digestAlgorithm)
+	if result.DigestAlgorithm != nil {
+		model.DigestAlgorithm = types.StringValue(*result.DigestAlgorithm)
+	} else if model.DigestAlgorithm.IsUnknown() {
+		model.DigestAlgorithm = types.StringNull()
 	}
-	if lifetime, ok := result["lifetime"].(float64); ok {
-		model.Lifetime = types.Int64Value(int64(lifetime))
+	if result.Lifetime != nil {
+		model.Lifetime = types.Int64Value(*result.Lifetime)
 	}
-	if country, ok := result["country"].(string); ok {
-		model.Country = types.StringValue(country)
+	if result.Country != nil {
+		model.Country = types.StringValue(*result.Country)
 	}
-	if state, ok := result["state"].(string); ok {
-		model.State = types.StringValue(state)
+	if result.State != nil {
+		model.State = types.StringValue(*result.State)
 	}
-	if city, ok := result["city"].(string); ok {
-		model.City = types.StringValue(city)
+	if result.City != nil {
+		model.City = types.StringValue(*result.City)
 	}
-	if organization, ok := result["organization"].(string); ok {
-		model.Organization = types.StringValue(organization)
+	if result.Organization != nil {
+		model.Organization = types.StringValue(*result.Organization)
 	}
-	if ou, ok := result["organizational_unit"].(string); ok {
-		model.OrganizationalUnit = types.StringValue(ou)
+	if result.OrganizationalUnit != nil {
+		model.OrganizationalUnit = types.StringValue(*result.OrganizationalUnit)
 	}
-	if email, ok := result["email"].(string); ok {
-		model.Email = types.StringValue(email)
+	if result.Email != nil {
+		model.Email = types.StringValue(*result.Email)
 	}
-	if commonName, ok := result["common"].(string); ok {
-		model.CommonName = types.StringValue(commonName)
+	if result.Common != nil {
+		model.CommonName = types.StringValue(*result.Common)
 	}
-	if san, ok := result["san"].([]interface{}); ok {
-		sanList := make([]string, len(san))
-		for i, s := range san {
-			sanList[i] = s.(string)
-		}
-		sanValues, diags := types.ListValueFrom(ctx, types.StringType, sanList)
+	if result.SAN != nil {
+		sanValues, diags := types.ListValueFrom(ctx, types.StringType, result.SAN)
 		if !diags.HasError() {
 			model.San = sanValues
 		}
 	}
-	if fingerprint, ok := result["fingerprint"].(string); ok {
-		model.Fingerprint = types.StringValue(fingerprint)
+	if result.Fingerprint != nil {
+		model.Fingerprint = types.StringValue(*result.Fingerprint)
 	}
-	if notBefore, ok := result["from"].(string); ok {
-		model.NotBefore = types.StringValue(notBefore)
+	if result.From != nil {
+		model.NotBefore = types.StringValue(*result.From)
 	}
-	if notAfter, ok := result["until"].(string); ok {
-		model.NotAfter = types.StringValue(notAfter)
+	if result.Until != nil {
+		model.NotAfter = types.StringValue(*result.Until)
 	}
 
 	return nil
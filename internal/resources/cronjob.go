@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -183,14 +184,14 @@ func (r *CronjobResource) Create(ctx context.Context, req resource.CreateRequest
 		createData["description"] = plan.Description.ValueString()
 	}
 
-	var result map[string]interface{}
+	var result api.CronjobEntry
 	err := r.client.Create(ctx, "cronjob", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Cron Job", "Could not create cron job: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating Cron Job", "Could not create cron job", err)
 		return
 	}
 
-	jobID := int64(result["id"].(float64))
+	jobID := result.ID
 	if err := r.readCronjob(ctx, jobID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading Cron Job", "Could not read cron job after creation: "+err.Error())
 		return
@@ -262,10 +263,10 @@ func (r *CronjobResource) Update(ctx context.Context, req resource.UpdateRequest
 		updateData["description"] = plan.Description.ValueString()
 	}
 
-	var result map[string]interface{}
+	var result api.CronjobEntry
 	err := r.client.Update(ctx, "cronjob", state.ID.ValueInt64(), updateData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Updating Cron Job", "Could not update cron job: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating Cron Job", "Could not update cron job", err)
 		return
 	}
 
@@ -306,30 +307,30 @@ func (r *CronjobResource) ImportState(ctx context.Context, req resource.ImportSt
 }
 
 func (r *CronjobResource) readCronjob(ctx context.Context, id int64, model *CronjobResourceModel) error {
-	var result map[string]interface{}
+	var result api.CronjobEntry
 	err := r.client.GetInstance(ctx, "cronjob", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.User = types.StringValue(result["user"].(string))
-	model.Command = types.StringValue(result["command"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.User = types.StringValue(result.User)
+	model.Command = types.StringValue(result.Command)
 
-	if description, ok := result["description"].(string); ok {
-		model.Description = types.StringValue(description)
+	if result.Description != nil {
+		model.Description = types.StringValue(*result.Description)
 	}
-	if enabled, ok := result["enabled"].(bool); ok {
-		model.Enabled = types.BoolValue(enabled)
+	if result.Enabled != nil {
+		model.Enabled = types.BoolValue(*result.Enabled)
 	}
-	if stdout, ok := result["stdout"].(bool); ok {
-		model.StdOut = types.BoolValue(stdout)
+	if result.Stdout != nil {
+		model.StdOut = types.BoolValue(*result.Stdout)
 	}
-	if stderr, ok := result["stderr"].(bool); ok {
-		model.StdErr = types.BoolValue(stderr)
+	if result.Stderr != nil {
+		model.StdErr = types.BoolValue(*result.Stderr)
 	}
 
-	if sched, ok := result["schedule"].(map[string]interface{}); ok {
+	if sched := result.Schedule; sched != nil {
 		scheduleObj, d := types.ObjectValue(
 			map[string]attr.Type{
 				"minute": types.StringType,
@@ -339,11 +340,11 @@ func (r *CronjobResource) readCronjob(ctx context.Context, id int64, model *Cron
 				"dow":    types.StringType,
 			},
 			map[string]attr.Value{
-				"minute": types.StringValue(sched["minute"].(string)),
-				"hour":   types.StringValue(sched["hour"].(string)),
-				"dom":    types.StringValue(sched["dom"].(string)),
-				"month":  types.StringValue(sched["month"].(string)),
-				"dow":    types.StringValue(sched["dow"].(string)),
+				"minute": types.StringValue(sched.Minute),
+				"hour":   types.StringValue(sched.Hour),
+				"dom":    types.StringValue(sched.Dom),
+				"month":  types.StringValue(sched.Month),
+				"dow":    types.StringValue(sched.Dow),
 			},
 		)
 		if !d.HasError() {
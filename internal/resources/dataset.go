@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -61,6 +63,8 @@ type DatasetResourceModel struct {
 	KeyLoaded       types.Bool   `tfsdk:"key_loaded"`
 	Used            types.Int64  `tfsdk:"used"`
 	Available       types.Int64  `tfsdk:"available"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *DatasetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -223,6 +227,9 @@ func (r *DatasetResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -251,6 +258,14 @@ func (r *DatasetResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Build full dataset path
 	datasetPath := plan.Pool.ValueString() + "/" + plan.Name.ValueString()
 
@@ -318,13 +333,10 @@ func (r *DatasetResource) Create(ctx context.Context, req resource.CreateRequest
 		createData["share_type"] = plan.ShareType.ValueString()
 	}
 
-	var result map[string]interface{}
+	var result api.DatasetEntry
 	err := r.client.Create(ctx, "pool.dataset", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating Dataset",
-			"Could not create dataset: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating Dataset", "Could not create dataset", err)
 		return
 	}
 
@@ -349,6 +361,14 @@ func (r *DatasetResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	if err := r.readDataset(ctx, state.ID.ValueString(), &state); err != nil {
 		if client.IsNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
@@ -380,6 +400,14 @@ func (r *DatasetResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Updating dataset", map[string]interface{}{
 		"id": state.ID.ValueString(),
 	})
@@ -448,13 +476,10 @@ func (r *DatasetResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.DatasetEntry
 		err := r.client.Update(ctx, "pool.dataset", state.ID.ValueString(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error Updating Dataset",
-				"Could not update dataset: "+err.Error(),
-			)
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating Dataset", "Could not update dataset", err)
 			return
 		}
 	}
@@ -480,6 +505,14 @@ func (r *DatasetResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Deleting dataset", map[string]interface{}{
 		"id": state.ID.ValueString(),
 	})
@@ -499,17 +532,16 @@ func (r *DatasetResource) ImportState(ctx context.Context, req resource.ImportSt
 }
 
 func (r *DatasetResource) readDataset(ctx context.Context, id string, model *DatasetResourceModel) error {
-	var result map[string]interface{}
+	var result api.DatasetEntry
 	err := r.client.GetInstance(ctx, "pool.dataset", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.StringValue(result["id"].(string))
+	model.ID = types.StringValue(result.ID)
 
 	// Extract pool and name from the full path
-	fullName := result["name"].(string)
-	parts := strings.SplitN(fullName, "/", 2)
+	parts := strings.SplitN(result.Name, "/", 2)
 	model.Pool = types.StringValue(parts[0])
 	if len(parts) > 1 {
 		model.Name = types.StringValue(parts[1])
@@ -517,133 +549,95 @@ func (r *DatasetResource) readDataset(ctx context.Context, id string, model *Dat
 		model.Name = types.StringValue("")
 	}
 
-	model.Type = types.StringValue(result["type"].(string))
+	model.Type = types.StringValue(result.Type)
 
 	// `comments` is a user-defined ZFS property. On TrueNAS 25.10 it lives
 	// under user_properties.comments.{value}; older TrueNAS versions returned
 	// it at the result root (either as a {value: "..."} object or a flat
 	// string). Handle all three shapes.
-	if userProps, ok := result["user_properties"].(map[string]interface{}); ok {
-		if comments, ok := userProps["comments"].(map[string]interface{}); ok {
-			if value, ok := comments["value"].(string); ok && value != "-" {
-				model.Comments = types.StringValue(value)
-			}
+	if comments, ok := result.UserProperties["comments"]; ok {
+		if value, ok := comments.Text(); ok && value != "-" {
+			model.Comments = types.StringValue(value)
 		}
 	}
 	if model.Comments.IsNull() || model.Comments.IsUnknown() {
-		if comments, ok := result["comments"].(map[string]interface{}); ok {
+		if comments, ok := result.Comments.(map[string]interface{}); ok {
 			if value, ok := comments["value"].(string); ok && value != "-" {
 				model.Comments = types.StringValue(value)
 			}
-		} else if comments, ok := result["comments"].(string); ok {
+		} else if comments, ok := result.Comments.(string); ok {
 			model.Comments = types.StringValue(comments)
 		}
 	}
-	if compression, ok := result["compression"].(map[string]interface{}); ok {
-		if value, ok := compression["value"].(string); ok {
-			model.Compression = types.StringValue(value)
-		}
+	if value, ok := result.Compression.Text(); ok {
+		model.Compression = types.StringValue(value)
 	}
-	if atime, ok := result["atime"].(map[string]interface{}); ok {
-		if value, ok := atime["value"].(string); ok {
-			model.Atime = types.StringValue(value)
-		}
+	if value, ok := result.Atime.Text(); ok {
+		model.Atime = types.StringValue(value)
 	}
-	if dedup, ok := result["deduplication"].(map[string]interface{}); ok {
-		if value, ok := dedup["value"].(string); ok {
-			model.Deduplication = types.StringValue(value)
-		}
+	if value, ok := result.Deduplication.Text(); ok {
+		model.Deduplication = types.StringValue(value)
 	}
-	if quota, ok := result["quota"].(map[string]interface{}); ok {
-		if parsed, ok := quota["parsed"].(float64); ok {
-			model.Quota = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Quota.Int(); ok {
+		model.Quota = types.Int64Value(parsed)
 	}
-	if refquota, ok := result["refquota"].(map[string]interface{}); ok {
-		if parsed, ok := refquota["parsed"].(float64); ok {
-			model.Refquota = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Refquota.Int(); ok {
+		model.Refquota = types.Int64Value(parsed)
 	}
-	if reservation, ok := result["reservation"].(map[string]interface{}); ok {
-		if parsed, ok := reservation["parsed"].(float64); ok {
-			model.Reservation = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Reservation.Int(); ok {
+		model.Reservation = types.Int64Value(parsed)
 	}
-	if refreservation, ok := result["refreservation"].(map[string]interface{}); ok {
-		if parsed, ok := refreservation["parsed"].(float64); ok {
-			model.Refreservation = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Refreservation.Int(); ok {
+		model.Refreservation = types.Int64Value(parsed)
 	}
-	if copies, ok := result["copies"].(map[string]interface{}); ok {
-		if value, ok := copies["value"].(string); ok {
-			// Parse string to int
-			var c int64
-			_, _ = fmt.Sscanf(value, "%d", &c)
-			model.Copies = types.Int64Value(c)
-		}
+	if value, ok := result.Copies.Text(); ok {
+		// Parse string to int
+		var c int64
+		_, _ = fmt.Sscanf(value, "%d", &c)
+		model.Copies = types.Int64Value(c)
 	}
-	if snapdir, ok := result["snapdir"].(map[string]interface{}); ok {
-		if value, ok := snapdir["value"].(string); ok {
-			model.Snapdir = types.StringValue(value)
-		}
+	if value, ok := result.Snapdir.Text(); ok {
+		model.Snapdir = types.StringValue(value)
 	}
-	if readonly, ok := result["readonly"].(map[string]interface{}); ok {
-		if value, ok := readonly["value"].(string); ok {
-			model.Readonly = types.StringValue(value)
-		}
+	if value, ok := result.Readonly.Text(); ok {
+		model.Readonly = types.StringValue(value)
 	}
-	if recordsize, ok := result["recordsize"].(map[string]interface{}); ok {
-		if value, ok := recordsize["value"].(string); ok {
-			model.Recordsize = types.StringValue(value)
-		}
+	if value, ok := result.Recordsize.Text(); ok {
+		model.Recordsize = types.StringValue(value)
 	}
-	if casesens, ok := result["casesensitivity"].(map[string]interface{}); ok {
-		if value, ok := casesens["value"].(string); ok {
-			model.Casesensitivity = types.StringValue(value)
-		}
+	if value, ok := result.Casesensitivity.Text(); ok {
+		model.Casesensitivity = types.StringValue(value)
 	}
-	if aclmode, ok := result["aclmode"].(map[string]interface{}); ok {
-		if value, ok := aclmode["value"].(string); ok {
-			model.Aclmode = types.StringValue(value)
-		}
+	if value, ok := result.Aclmode.Text(); ok {
+		model.Aclmode = types.StringValue(value)
 	}
-	if acltype, ok := result["acltype"].(map[string]interface{}); ok {
-		if value, ok := acltype["value"].(string); ok {
-			model.Acltype = types.StringValue(value)
-		}
+	if value, ok := result.Acltype.Text(); ok {
+		model.Acltype = types.StringValue(value)
 	}
-	if managedBy, ok := result["managedby"].(map[string]interface{}); ok {
-		if value, ok := managedBy["value"].(string); ok && value != "" {
-			model.ManagedBy = types.StringValue(value)
-		} else {
-			model.ManagedBy = types.StringNull()
-		}
+	if value, ok := result.Managedby.Text(); ok && value != "" {
+		model.ManagedBy = types.StringValue(value)
 	} else {
 		model.ManagedBy = types.StringNull()
 	}
-	if mountpoint, ok := result["mountpoint"].(string); ok {
-		model.Mountpoint = types.StringValue(mountpoint)
+	if result.Mountpoint != nil {
+		model.Mountpoint = types.StringValue(*result.Mountpoint)
 	}
-	if encrypted, ok := result["encrypted"].(bool); ok {
-		model.Encrypted = types.BoolValue(encrypted)
+	if result.Encrypted != nil {
+		model.Encrypted = types.BoolValue(*result.Encrypted)
 	}
-	if encryptionRoot, ok := result["encryption_root"].(string); ok && encryptionRoot != "" {
-		model.EncryptionRoot = types.StringValue(encryptionRoot)
+	if result.EncryptionRoot != nil && *result.EncryptionRoot != "" {
+		model.EncryptionRoot = types.StringValue(*result.EncryptionRoot)
 	} else {
 		model.EncryptionRoot = types.StringNull()
 	}
-	if keyLoaded, ok := result["key_loaded"].(bool); ok {
-		model.KeyLoaded = types.BoolValue(keyLoaded)
+	if result.KeyLoaded != nil {
+		model.KeyLoaded = types.BoolValue(*result.KeyLoaded)
 	}
-	if used, ok := result["used"].(map[string]interface{}); ok {
-		if parsed, ok := used["parsed"].(float64); ok {
-			model.Used = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Used.Int(); ok {
+		model.Used = types.Int64Value(parsed)
 	}
-	if available, ok := result["available"].(map[string]interface{}); ok {
-		if parsed, ok := available["parsed"].(float64); ok {
-			model.Available = types.Int64Value(int64(parsed))
-		}
+	if parsed, ok := result.Available.Int(); ok {
+		model.Available = types.Int64Value(parsed)
 	}
 
 	// share_type is a creation-only hint on TrueNAS 25.10 and isn't echoed by
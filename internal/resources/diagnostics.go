@@ -0,0 +1,74 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+// fieldAliases maps TrueNAS field names (without the schema prefix, e.g.
+// "attributes.path") to the schema attribute they are set from, for fields
+// that aren't named after the attribute
+type fieldAliases map[string]string
+
+// addAPIError reports a failed create or update. If TrueNAS rejected the
+// payload, each validation failure is attached to the schema attribute it
+// refers to so Terraform highlights the offending setting; failures that
+// can't be matched to an attribute, and all other errors, are reported
+// against the resource as a whole.
+func addAPIError(diags *diag.Diagnostics, plan tfsdk.Plan, aliases fieldAliases, summary, detail string, err error) {
+	failures := client.ValidationFailures(err)
+	if len(failures) == 0 {
+		diags.AddError(summary, detail+": "+err.Error())
+		return
+	}
+
+	for _, f := range failures {
+		if attrPath, ok := validationPath(plan, aliases, f.Field()); ok {
+			diags.AddAttributeError(attrPath, summary, detail+": "+f.Message)
+			continue
+		}
+		diags.AddError(summary, detail+": "+f.Attribute+": "+f.Message)
+	}
+}
+
+// validationPath finds the schema attribute a TrueNAS field name refers to.
+// Nested fields (e.g. "options.foo" or "aux.0") are reported against their
+// top-level attribute.
+func validationPath(plan tfsdk.Plan, aliases fieldAliases, field string) (path.Path, bool) {
+	// Prefer the longest aliased prefix, so "attributes.path" wins over
+	// "attributes"
+	for name := field; name != ""; {
+		if attr, ok := aliases[name]; ok {
+			return path.Root(attr), true
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	name, _, _ := strings.Cut(field, ".")
+	if _, ok := plan.Schema.GetAttributes()[name]; ok {
+		return path.Root(name), true
+	}
+	if _, ok := plan.Schema.GetBlocks()[name]; ok {
+		return path.Root(name), true
+	}
+	return path.Empty(), false
+}
+
+// addUnsupportedError reports at plan time that a configured attribute isn't
+// supported by the connected TrueNAS release
+func addUnsupportedError(diags *diag.Diagnostics, c *client.Client, attrPath path.Path, detail string) {
+	release := "this TrueNAS version"
+	if v := c.Version(); !v.IsZero() {
+		release = "TrueNAS " + v.String()
+	}
+	diags.AddAttributeError(attrPath, "Unsupported by "+release, detail)
+}
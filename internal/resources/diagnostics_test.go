@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+func TestAddAPIErrorSMBShareAliases(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	NewShareSMBResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	plan := tfsdk.Plan{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+
+	tests := []struct {
+		attribute string
+		want      path.Path
+	}{
+		// Names TrueNAS 25.10 reports for the renamed and moved fields
+		{attribute: "sharing_smb_create.readonly", want: path.Root("ro")},
+		{attribute: "sharing_smb_create.access_based_share_enumeration", want: path.Root("abe")},
+		{attribute: "sharing_smb_update.options.hostsallow", want: path.Root("hostsallow")},
+		{attribute: "sharing_smb_update.options.hostsdeny.0", want: path.Root("hostsdeny")},
+		{attribute: "sharing_smb_create.options.path_suffix", want: path.Root("path_suffix")},
+		{attribute: "sharing_smb_update.options.auxsmbconf", want: path.Root("auxsmbconf")},
+		{attribute: "sharing_smb_create.options.guestok", want: path.Root("guestok")},
+		// Older releases use the schema's names
+		{attribute: "sharing_smb_create.ro", want: path.Root("ro")},
+		{attribute: "sharing_smb_create.path", want: path.Root("path")},
+	}
+
+	for _, tt := range tests {
+		err := &client.APIError{
+			Code:             client.ErrCodeValidation,
+			Message:          "Validation error",
+			ValidationErrors: []client.ValidationFailure{{Attribute: tt.attribute, Message: "Invalid value"}},
+		}
+		var diags diag.Diagnostics
+		addAPIError(&diags, plan, smbShareFieldAliases, "Error Creating SMB Share", "Could not create SMB share", err)
+
+		if len(diags) != 1 {
+			t.Fatalf("%s: got %d diagnostics, want 1", tt.attribute, len(diags))
+		}
+		withPath, ok := diags[0].(diag.DiagnosticWithPath)
+		if !ok {
+			t.Errorf("%s: diagnostic %q is not attributed", tt.attribute, diags[0].Detail())
+			continue
+		}
+		if !withPath.Path().Equal(tt.want) {
+			t.Errorf("%s: diagnostic path = %s, want %s", tt.attribute, withPath.Path(), tt.want)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -167,6 +168,8 @@ func (r *ISCSIExtentResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 	r.client = client
+
+	client.Serialize("iscsi")
 }
 
 func (r *ISCSIExtentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -221,14 +224,14 @@ func (r *ISCSIExtentResource) Create(ctx context.Context, req resource.CreateReq
 		createData["ro"] = plan.Ro.ValueBool()
 	}
 
-	var result map[string]interface{}
+	var result api.ISCSIExtentEntry
 	err := r.client.Create(ctx, "iscsi.extent", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Extent", "Could not create iSCSI extent: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating iSCSI Extent", "Could not create iSCSI extent", err)
 		return
 	}
 
-	extentID := int64(result["id"].(float64))
+	extentID := result.ID
 	if err := r.readExtent(ctx, extentID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading iSCSI Extent", "Could not read iSCSI extent after creation: "+err.Error())
 		return
@@ -318,10 +321,10 @@ func (r *ISCSIExtentResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.ISCSIExtentEntry
 		err := r.client.Update(ctx, "iscsi.extent", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating iSCSI Extent", "Could not update iSCSI extent: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating iSCSI Extent", "Could not update iSCSI extent", err)
 			return
 		}
 	}
@@ -363,66 +366,66 @@ func (r *ISCSIExtentResource) ImportState(ctx context.Context, req resource.Impo
 }
 
 func (r *ISCSIExtentResource) readExtent(ctx context.Context, id int64, model *ISCSIExtentResourceModel) error {
-	var result map[string]interface{}
+	var result api.ISCSIExtentEntry
 	err := r.client.GetInstance(ctx, "iscsi.extent", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Name = types.StringValue(result["name"].(string))
-	model.Type = types.StringValue(result["type"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
+	model.Type = types.StringValue(result.Type)
 
-	if disk, ok := result["disk"].(string); ok {
-		model.Disk = types.StringValue(disk)
+	if result.Disk != nil {
+		model.Disk = types.StringValue(*result.Disk)
 	}
-	if path, ok := result["path"].(string); ok {
-		model.Path = types.StringValue(path)
+	if result.Path != nil {
+		model.Path = types.StringValue(*result.Path)
 	}
 	// TrueNAS 25.10 returns filesize as a string (e.g. "10485760"); older
 	// versions returned a number. Accept both shapes.
-	if filesize, ok := result["filesize"].(float64); ok {
+	if filesize, ok := result.Filesize.(float64); ok {
 		model.Filesize = types.Int64Value(int64(filesize))
-	} else if filesizeStr, ok := result["filesize"].(string); ok {
+	} else if filesizeStr, ok := result.Filesize.(string); ok {
 		if parsed, err := strconv.ParseInt(filesizeStr, 10, 64); err == nil {
 			model.Filesize = types.Int64Value(parsed)
 		}
 	}
-	if blocksize, ok := result["blocksize"].(float64); ok {
-		model.Blocksize = types.Int64Value(int64(blocksize))
+	if result.Blocksize != nil {
+		model.Blocksize = types.Int64Value(*result.Blocksize)
 	}
-	if pblocksize, ok := result["pblocksize"].(bool); ok {
-		model.Pblocksize = types.BoolValue(pblocksize)
+	if result.Pblocksize != nil {
+		model.Pblocksize = types.BoolValue(*result.Pblocksize)
 	}
-	if availThreshold, ok := result["avail_threshold"].(float64); ok {
-		model.AvailThreshold = types.Int64Value(int64(availThreshold))
+	if result.AvailThreshold != nil {
+		model.AvailThreshold = types.Int64Value(*result.AvailThreshold)
 	}
-	if comment, ok := result["comment"].(string); ok {
-		model.Comment = types.StringValue(comment)
+	if result.Comment != nil {
+		model.Comment = types.StringValue(*result.Comment)
 	}
-	if insecureTPC, ok := result["insecure_tpc"].(bool); ok {
-		model.InsecureTPC = types.BoolValue(insecureTPC)
+	if result.InsecureTPC != nil {
+		model.InsecureTPC = types.BoolValue(*result.InsecureTPC)
 	}
-	if xen, ok := result["xen"].(bool); ok {
-		model.Xen = types.BoolValue(xen)
+	if result.Xen != nil {
+		model.Xen = types.BoolValue(*result.Xen)
 	}
-	if rpm, ok := result["rpm"].(string); ok {
-		model.RPM = types.StringValue(rpm)
+	if result.RPM != nil {
+		model.RPM = types.StringValue(*result.RPM)
 	}
-	if ro, ok := result["ro"].(bool); ok {
-		model.Ro = types.BoolValue(ro)
+	if result.Ro != nil {
+		model.Ro = types.BoolValue(*result.Ro)
 	}
-	if enabled, ok := result["enabled"].(bool); ok {
-		model.Enabled = types.BoolValue(enabled)
+	if result.Enabled != nil {
+		model.Enabled = types.BoolValue(*result.Enabled)
 	}
-	if serial, ok := result["serial"].(string); ok {
-		model.Serial = types.StringValue(serial)
+	if result.Serial != nil {
+		model.Serial = types.StringValue(*result.Serial)
 	}
-	if naa, ok := result["naa"].(string); ok {
-		model.NAA = types.StringValue(naa)
+	if result.NAA != nil {
+		model.NAA = types.StringValue(*result.NAA)
 	}
-	if locked, ok := result["locked"].(bool); ok {
-		model.Locked = types.BoolValue(locked)
+	if result.Locked != nil {
+		model.Locked = types.BoolValue(*result.Locked)
 	}
 
 	return nil
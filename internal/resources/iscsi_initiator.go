@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -74,6 +75,8 @@ func (r *ISCSIInitiatorResource) Configure(ctx context.Context, req resource.Con
 		return
 	}
 	r.client = client
+
+	client.Serialize("iscsi")
 }
 
 func (r *ISCSIInitiatorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -108,14 +111,14 @@ func (r *ISCSIInitiatorResource) Create(ctx context.Context, req resource.Create
 		}
 	}
 
-	var result map[string]interface{}
+	var result api.ISCSIInitiatorEntry
 	err := r.client.Create(ctx, "iscsi.initiator", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Initiator", "Could not create iSCSI initiator: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating iSCSI Initiator", "Could not create iSCSI initiator", err)
 		return
 	}
 
-	initiatorID := int64(result["id"].(float64))
+	initiatorID := result.ID
 	if err := r.readInitiator(ctx, initiatorID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading iSCSI Initiator", "Could not read iSCSI initiator after creation: "+err.Error())
 		return
@@ -188,10 +191,10 @@ func (r *ISCSIInitiatorResource) Update(ctx context.Context, req resource.Update
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.ISCSIInitiatorEntry
 		err := r.client.Update(ctx, "iscsi.initiator", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating iSCSI Initiator", "Could not update iSCSI initiator: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating iSCSI Initiator", "Could not update iSCSI initiator", err)
 			return
 		}
 	}
@@ -233,33 +236,25 @@ func (r *ISCSIInitiatorResource) ImportState(ctx context.Context, req resource.I
 }
 
 func (r *ISCSIInitiatorResource) readInitiator(ctx context.Context, id int64, model *ISCSIInitiatorResourceModel) error {
-	var result map[string]interface{}
+	var result api.ISCSIInitiatorEntry
 	err := r.client.GetInstance(ctx, "iscsi.initiator", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
+	model.ID = types.Int64Value(result.ID)
 
-	if comment, ok := result["comment"].(string); ok {
-		model.Comment = types.StringValue(comment)
+	if result.Comment != nil {
+		model.Comment = types.StringValue(*result.Comment)
 	}
-	if initiators, ok := result["initiators"].([]interface{}); ok {
-		initiatorList := make([]string, len(initiators))
-		for i, init := range initiators {
-			initiatorList[i] = init.(string)
-		}
-		initiatorValues, diags := types.ListValueFrom(ctx, types.StringType, initiatorList)
+	if result.Initiators != nil {
+		initiatorValues, diags := types.ListValueFrom(ctx, types.StringType, result.Initiators)
 		if !diags.HasError() {
 			model.Initiators = initiatorValues
 		}
 	}
-	if authNetwork, ok := result["auth_network"].([]interface{}); ok {
-		networkList := make([]string, len(authNetwork))
-		for i, net := range authNetwork {
-			networkList[i] = net.(string)
-		}
-		networkValues, diags := types.ListValueFrom(ctx, types.StringType, networkList)
+	if result.AuthNetwork != nil {
+		networkValues, diags := types.ListValueFrom(ctx, types.StringType, result.AuthNetwork)
 		if !diags.HasError() {
 			model.AuthNetwork = networkValues
 		}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -98,6 +99,8 @@ func (r *ISCSIPortalResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 	r.client = client
+
+	client.Serialize("iscsi")
 }
 
 func (r *ISCSIPortalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -139,14 +142,14 @@ func (r *ISCSIPortalResource) Create(ctx context.Context, req resource.CreateReq
 		createData["discovery_authgroup"] = plan.DiscoveryGroup.ValueInt64()
 	}
 
-	var result map[string]interface{}
+	var result api.ISCSIPortalEntry
 	err := r.client.Create(ctx, "iscsi.portal", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Portal", "Could not create iSCSI portal: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating iSCSI Portal", "Could not create iSCSI portal", err)
 		return
 	}
 
-	portalID := int64(result["id"].(float64))
+	portalID := result.ID
 	if err := r.readPortal(ctx, portalID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading iSCSI Portal", "Could not read iSCSI portal after creation: "+err.Error())
 		return
@@ -221,10 +224,10 @@ func (r *ISCSIPortalResource) Update(ctx context.Context, req resource.UpdateReq
 		updateData["discovery_authgroup"] = plan.DiscoveryGroup.ValueInt64()
 	}
 
-	var result map[string]interface{}
+	var result api.ISCSIPortalEntry
 	err := r.client.Update(ctx, "iscsi.portal", state.ID.ValueInt64(), updateData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Updating iSCSI Portal", "Could not update iSCSI portal: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating iSCSI Portal", "Could not update iSCSI portal", err)
 		return
 	}
 
@@ -265,31 +268,34 @@ func (r *ISCSIPortalResource) ImportState(ctx context.Context, req resource.Impo
 }
 
 func (r *ISCSIPortalResource) readPortal(ctx context.Context, id int64, model *ISCSIPortalResourceModel) error {
-	var result map[string]interface{}
+	var result api.ISCSIPortalEntry
 	err := r.client.GetInstance(ctx, "iscsi.portal", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	if comment, ok := result["comment"].(string); ok {
-		model.Comment = types.StringValue(comment)
+	model.ID = types.Int64Value(result.ID)
+	if result.Comment != nil {
+		model.Comment = types.StringValue(*result.Comment)
 	}
-	if discoveryAuth, ok := result["discovery_authmethod"].(string); ok {
-		model.DiscoveryAuth = types.StringValue(discoveryAuth)
+	if result.DiscoveryAuthmethod != nil {
+		model.DiscoveryAuth = types.StringValue(*result.DiscoveryAuthmethod)
 	}
-	if discoveryGroup, ok := result["discovery_authgroup"].(float64); ok {
-		model.DiscoveryGroup = types.Int64Value(int64(discoveryGroup))
+	if result.DiscoveryAuthgroup != nil {
+		model.DiscoveryGroup = types.Int64Value(*result.DiscoveryAuthgroup)
 	}
 
-	if listenList, ok := result["listen"].([]interface{}); ok {
-		listenItems := make([]PortalListen, len(listenList))
-		for i, item := range listenList {
-			if listenMap, ok := item.(map[string]interface{}); ok {
-				listenItems[i] = PortalListen{
-					IP:   types.StringValue(listenMap["ip"].(string)),
-					Port: types.Int64Value(int64(listenMap["port"].(float64))),
-				}
+	if result.Listen != nil {
+		listenItems := make([]PortalListen, len(result.Listen))
+		for i, listen := range result.Listen {
+			// TrueNAS Scale 25 doesn't return a port, it is implicit (3260)
+			port := int64(3260)
+			if listen.Port != nil {
+				port = *listen.Port
+			}
+			listenItems[i] = PortalListen{
+				IP:   types.StringValue(listen.IP),
+				Port: types.Int64Value(port),
 			}
 		}
 		listenValue, d := types.ListValueFrom(ctx, types.ObjectType{
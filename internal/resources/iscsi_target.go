@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -112,6 +113,11 @@ func (r *ISCSITargetResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 	r.client = client
+
+	// Every iSCSI change reloads the target service, and changes made while
+	// a reload is running fail with a locked error. The other iSCSI resources
+	// serialize the same namespace.
+	client.Serialize("iscsi")
 }
 
 func (r *ISCSITargetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -161,14 +167,14 @@ func (r *ISCSITargetResource) Create(ctx context.Context, req resource.CreateReq
 		createData["groups"] = groups
 	}
 
-	var result map[string]interface{}
+	var result api.ISCSITargetEntry
 	err := r.client.Create(ctx, "iscsi.target", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Target", "Could not create iSCSI target: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating iSCSI Target", "Could not create iSCSI target", err)
 		return
 	}
 
-	targetID := int64(result["id"].(float64))
+	targetID := result.ID
 	if err := r.readTarget(ctx, targetID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading iSCSI Target", "Could not read iSCSI target after creation: "+err.Error())
 		return
@@ -253,10 +259,10 @@ func (r *ISCSITargetResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.ISCSITargetEntry
 		err := r.client.Update(ctx, "iscsi.target", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating iSCSI Target", "Could not update iSCSI target: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating iSCSI Target", "Could not update iSCSI target", err)
 			return
 		}
 	}
@@ -298,20 +304,20 @@ func (r *ISCSITargetResource) ImportState(ctx context.Context, req resource.Impo
 }
 
 func (r *ISCSITargetResource) readTarget(ctx context.Context, id int64, model *ISCSITargetResourceModel) error {
-	var result map[string]interface{}
+	var result api.ISCSITargetEntry
 	err := r.client.GetInstance(ctx, "iscsi.target", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Name = types.StringValue(result["name"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
 
-	if alias, ok := result["alias"].(string); ok {
-		model.Alias = types.StringValue(alias)
+	if result.Alias != nil {
+		model.Alias = types.StringValue(*result.Alias)
 	}
-	if mode, ok := result["mode"].(string); ok {
-		model.Mode = types.StringValue(mode)
+	if result.Mode != nil {
+		model.Mode = types.StringValue(*result.Mode)
 	}
 
 	return nil
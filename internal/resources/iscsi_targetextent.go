@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -75,6 +76,8 @@ func (r *ISCSITargetExtentResource) Configure(ctx context.Context, req resource.
 		return
 	}
 	r.client = client
+
+	client.Serialize("iscsi")
 }
 
 func (r *ISCSITargetExtentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -96,14 +99,14 @@ func (r *ISCSITargetExtentResource) Create(ctx context.Context, req resource.Cre
 		"lunid":  plan.LunID.ValueInt64(),
 	}
 
-	var result map[string]interface{}
+	var result api.ISCSITargetExtentEntry
 	err := r.client.Create(ctx, "iscsi.targetextent", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating iSCSI Target-Extent", "Could not create iSCSI target-extent mapping: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating iSCSI Target-Extent", "Could not create iSCSI target-extent mapping", err)
 		return
 	}
 
-	mappingID := int64(result["id"].(float64))
+	mappingID := result.ID
 	if err := r.readTargetExtent(ctx, mappingID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading iSCSI Target-Extent", "Could not read iSCSI target-extent mapping after creation: "+err.Error())
 		return
@@ -162,10 +165,10 @@ func (r *ISCSITargetExtentResource) Update(ctx context.Context, req resource.Upd
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.ISCSITargetExtentEntry
 		err := r.client.Update(ctx, "iscsi.targetextent", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating iSCSI Target-Extent", "Could not update iSCSI target-extent mapping: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating iSCSI Target-Extent", "Could not update iSCSI target-extent mapping", err)
 			return
 		}
 	}
@@ -207,16 +210,16 @@ func (r *ISCSITargetExtentResource) ImportState(ctx context.Context, req resourc
 }
 
 func (r *ISCSITargetExtentResource) readTargetExtent(ctx context.Context, id int64, model *ISCSITargetExtentResourceModel) error {
-	var result map[string]interface{}
+	var result api.ISCSITargetExtentEntry
 	err := r.client.GetInstance(ctx, "iscsi.targetextent", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Target = types.Int64Value(int64(result["target"].(float64)))
-	model.Extent = types.Int64Value(int64(result["extent"].(float64)))
-	model.LunID = types.Int64Value(int64(result["lunid"].(float64)))
+	model.ID = types.Int64Value(result.ID)
+	model.Target = types.Int64Value(result.Target)
+	model.Extent = types.Int64Value(result.Extent)
+	model.LunID = types.Int64Value(result.Lunid)
 
 	return nil
 }
@@ -0,0 +1,23 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+)
+
+// logJobProgress returns a callback that streams TrueNAS job progress to the
+// Terraform log, so long-running jobs don't look like a frozen apply.
+func logJobProgress(ctx context.Context, operation string) client.JobProgressFunc {
+	return func(p client.JobProgress) {
+		tflog.Info(ctx, fmt.Sprintf("%s: %s %.0f%%", operation, p.Description, p.Percent), map[string]interface{}{
+			"job_id":      p.JobID,
+			"method":      p.Method,
+			"percent":     p.Percent,
+			"description": p.Description,
+		})
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -47,6 +48,8 @@ type PoolResourceModel struct {
 	Size              types.Int64  `tfsdk:"size"`
 	Free              types.Int64  `tfsdk:"free"`
 	Allocated         types.Int64  `tfsdk:"allocated"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type TopologyVDev struct {
@@ -148,6 +151,9 @@ func (r *PoolResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -176,6 +182,14 @@ func (r *PoolResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Creating pool", map[string]interface{}{
 		"name": plan.Name.ValueString(),
 	})
@@ -237,12 +251,9 @@ func (r *PoolResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 
 	// Pool creation is a long-running job, wait for it to complete
-	result, err := r.client.CreateWithJob(ctx, "pool", createData, 10*time.Minute)
+	result, err := r.client.CreateWithJob(ctx, "pool", createData, createTimeout, logJobProgress(ctx, "Creating pool"))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating Pool",
-			"Could not create pool: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating Pool", "Could not create pool", err)
 		return
 	}
 
@@ -258,7 +269,7 @@ func (r *PoolResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	if poolID == 0 {
 		// If we can't get the ID from result, query by name
-		var pools []map[string]interface{}
+		var pools []api.PoolEntry
 		queryErr := r.client.Call(ctx, "pool.query", []interface{}{
 			[][]interface{}{{"name", "=", plan.Name.ValueString()}},
 		}, &pools)
@@ -269,7 +280,7 @@ func (r *PoolResource) Create(ctx context.Context, req resource.CreateRequest, r
 			)
 			return
 		}
-		poolID = int64(pools[0]["id"].(float64))
+		poolID = pools[0].ID
 	}
 	if err := r.readPool(ctx, poolID, &plan); err != nil {
 		resp.Diagnostics.AddError(
@@ -291,6 +302,14 @@ func (r *PoolResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	if err := r.readPool(ctx, state.ID.ValueInt64(), &state); err != nil {
 		if client.IsNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
@@ -322,6 +341,14 @@ func (r *PoolResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Updating pool", map[string]interface{}{
 		"id": state.ID.ValueInt64(),
 	})
@@ -355,17 +382,29 @@ func (r *PoolResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Deleting pool", map[string]interface{}{
 		"id": state.ID.ValueInt64(),
 	})
 
-	// Export and destroy the pool
+	// Export and destroy the pool, which runs as a job
+	var jobID int64
 	err := r.client.Call(ctx, "pool.export", []interface{}{
 		state.ID.ValueInt64(),
 		map[string]interface{}{
 			"destroy": true,
 		},
-	}, nil)
+	}, &jobID)
+	if err == nil {
+		_, err = r.client.WaitForJob(ctx, jobID, deleteTimeout, logJobProgress(ctx, "Deleting pool"))
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting Pool",
@@ -388,53 +427,57 @@ func (r *PoolResource) ImportState(ctx context.Context, req resource.ImportState
 }
 
 func (r *PoolResource) readPool(ctx context.Context, id int64, model *PoolResourceModel) error {
-	var result map[string]interface{}
+	var result api.PoolEntry
 	err := r.client.GetInstance(ctx, "pool", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Name = types.StringValue(result["name"].(string))
-	model.Status = types.StringValue(result["status"].(string))
-	model.Healthy = types.BoolValue(result["healthy"].(bool))
-	model.Path = types.StringValue(result["path"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
+	model.Status = types.StringValue(result.Status)
+	model.Healthy = types.BoolValue(result.Healthy)
+	model.Path = types.StringValue(result.Path)
 
-	if size, ok := result["size"].(float64); ok {
-		model.Size = types.Int64Value(int64(size))
+	if result.Size != nil {
+		model.Size = types.Int64Value(*result.Size)
 	}
-	if free, ok := result["free"].(float64); ok {
-		model.Free = types.Int64Value(int64(free))
+	if result.Free != nil {
+		model.Free = types.Int64Value(*result.Free)
 	}
-	if allocated, ok := result["allocated"].(float64); ok {
-		model.Allocated = types.Int64Value(int64(allocated))
+	if result.Allocated != nil {
+		model.Allocated = types.Int64Value(*result.Allocated)
 	}
 
 	// Reconstruct the topology list from the API's nested structure.
 	// API shape: {"topology": {"data": [{"children": [{"disk": "sdb"}, ...]}, ...], "cache": [...], ...}}
 	// Schema shape: [{type: "data", disks: ["sdb", ...]}, ...]
-	if topology, ok := result["topology"].(map[string]interface{}); ok {
+	if topology := result.Topology; topology != nil {
 		vdevType := types.ObjectType{AttrTypes: map[string]attr.Type{
 			"type":  types.StringType,
 			"disks": types.ListType{ElemType: types.StringType},
 		}}
 		var elements []attr.Value
-		// Stable ordering: iterate known categories first, then anything else.
-		categories := []string{"data", "log", "cache", "spare", "special", "dedup"}
-		seen := map[string]bool{}
+		// Stable ordering: iterate the categories in a fixed order.
+		categories := []struct {
+			name  string
+			vdevs []api.PoolTopologyVdev
+		}{
+			{"data", topology.Data},
+			{"log", topology.Log},
+			{"cache", topology.Cache},
+			{"spare", topology.Spare},
+			{"special", topology.Special},
+			{"dedup", topology.Dedup},
+		}
 		for _, cat := range categories {
-			seen[cat] = true
-			vdevs, ok := topology[cat].([]interface{})
-			if !ok || len(vdevs) == 0 {
-				continue
-			}
-			disks := collectVDevDisks(vdevs)
+			disks := collectVDevDisks(cat.vdevs)
 			if len(disks) == 0 {
 				continue
 			}
 			disksList, _ := types.ListValueFrom(ctx, types.StringType, disks)
 			obj, _ := types.ObjectValue(vdevType.AttrTypes, map[string]attr.Value{
-				"type":  types.StringValue(cat),
+				"type":  types.StringValue(cat.name),
 				"disks": disksList,
 			})
 			elements = append(elements, obj)
@@ -462,25 +505,19 @@ func (r *PoolResource) readPool(ctx context.Context, id int64, model *PoolResour
 // collectVDevDisks flattens a list of vdev entries into the underlying disk
 // identifiers. Each vdev may carry the disk directly (single-disk stripe) or
 // under a children array (mirror/raidz/etc.).
-func collectVDevDisks(vdevs []interface{}) []string {
+func collectVDevDisks(vdevs []api.PoolTopologyVdev) []string {
 	var disks []string
-	for _, v := range vdevs {
-		vdev, ok := v.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if children, ok := vdev["children"].([]interface{}); ok && len(children) > 0 {
-			for _, c := range children {
-				if cm, ok := c.(map[string]interface{}); ok {
-					if d, ok := cm["disk"].(string); ok && d != "" {
-						disks = append(disks, d)
-					}
+	for _, vdev := range vdevs {
+		if len(vdev.Children) > 0 {
+			for _, child := range vdev.Children {
+				if child.Disk != nil && *child.Disk != "" {
+					disks = append(disks, *child.Disk)
 				}
 			}
 			continue
 		}
-		if d, ok := vdev["disk"].(string); ok && d != "" {
-			disks = append(disks, d)
+		if vdev.Disk != nil && *vdev.Disk != "" {
+			disks = append(disks, *vdev.Disk)
 		}
 	}
 	return disks
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -32,6 +34,8 @@ type ServiceDockerResourceModel struct {
 	NvidiaEnabled      types.Bool   `tfsdk:"nvidia"`
 	EnableImageUpdates types.Bool   `tfsdk:"enable_image_updates"`
 	Status             types.String `tfsdk:"status"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ServiceDockerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -67,6 +71,9 @@ func (r *ServiceDockerResource) Schema(ctx context.Context, req resource.SchemaR
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -95,16 +102,24 @@ func (r *ServiceDockerResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Configuring Docker service", map[string]interface{}{
 		"pool": plan.Pool.ValueString(),
 	})
 
-	if err := r.updateDocker(ctx, &plan); err != nil {
-		resp.Diagnostics.AddError("Error Configuring Docker Service", err.Error())
+	if err := r.updateDocker(ctx, &plan, createTimeout); err != nil {
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Configuring Docker Service", "Docker service configuration failed", err)
 		return
 	}
 
-	if err := r.waitForRunning(ctx); err != nil {
+	if err := r.waitForRunning(ctx, createTimeout); err != nil {
 		resp.Diagnostics.AddError("Error Waiting for Docker Service", err.Error())
 		return
 	}
@@ -127,6 +142,14 @@ func (r *ServiceDockerResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	if err := r.readDocker(ctx, &state); err != nil {
 		resp.Diagnostics.AddError("Error Reading Docker Service", "Could not read Docker service: "+err.Error())
 		return
@@ -151,16 +174,24 @@ func (r *ServiceDockerResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Updating Docker service configuration", map[string]interface{}{
 		"pool": plan.Pool.ValueString(),
 	})
 
-	if err := r.updateDocker(ctx, &plan); err != nil {
-		resp.Diagnostics.AddError("Error Updating Docker Service", err.Error())
+	if err := r.updateDocker(ctx, &plan, updateTimeout); err != nil {
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating Docker Service", "Docker service update failed", err)
 		return
 	}
 
-	if err := r.waitForRunning(ctx); err != nil {
+	if err := r.waitForRunning(ctx, updateTimeout); err != nil {
 		resp.Diagnostics.AddError("Error Waiting for Docker Service", err.Error())
 		return
 	}
@@ -176,6 +207,21 @@ func (r *ServiceDockerResource) Update(ctx context.Context, req resource.UpdateR
 }
 
 func (r *ServiceDockerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ServiceDockerResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, r.client.JobTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Unconfiguring Docker service")
 
 	// Unconfigure Docker by setting pool to null
@@ -190,24 +236,25 @@ func (r *ServiceDockerResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
-	if _, err := r.client.WaitForJob(ctx, int64(jobID), 5*time.Minute); err != nil {
+	if _, err := r.client.WaitForJob(ctx, int64(jobID), deleteTimeout, logJobProgress(ctx, "Unconfiguring Docker service")); err != nil {
 		resp.Diagnostics.AddError("Error Unconfiguring Docker Service", "Docker unconfigure job failed: "+err.Error())
 		return
 	}
 }
 
-func (r *ServiceDockerResource) updateDocker(ctx context.Context, plan *ServiceDockerResourceModel) error {
+// updateDocker applies the plan, waiting up to timeout for each job
+func (r *ServiceDockerResource) updateDocker(ctx context.Context, plan *ServiceDockerResourceModel, timeout time.Duration) error {
 	targetPool := plan.Pool.ValueString()
 
 	// If Docker is already configured with the same pool, unset it first to force
 	// a clean dataset re-creation. TrueNAS 25.10+ won't reinitialize ix-apps datasets
 	// on a same-pool update, so we cycle through null to trigger a fresh setup.
 	// When changing to a different pool, TrueNAS handles the transition natively.
-	var currentConfig map[string]interface{}
+	var currentConfig api.DockerConfig
 	if err := r.client.Call(ctx, "docker.config", []interface{}{}, &currentConfig); err == nil {
-		if currentPool, _ := currentConfig["pool"].(string); currentPool == targetPool && currentPool != "" {
+		if currentConfig.Pool != nil && *currentConfig.Pool == targetPool && targetPool != "" {
 			tflog.Debug(ctx, "Unsetting existing Docker pool before reconfiguring (same pool)", map[string]interface{}{
-				"pool": currentPool,
+				"pool": targetPool,
 			})
 			var unsetJobID float64
 			if err := r.client.Call(ctx, "docker.update", []interface{}{
@@ -215,7 +262,7 @@ func (r *ServiceDockerResource) updateDocker(ctx context.Context, plan *ServiceD
 			}, &unsetJobID); err != nil {
 				return fmt.Errorf("could not unset Docker pool: %w", err)
 			}
-			if _, err := r.client.WaitForJob(ctx, int64(unsetJobID), 5*time.Minute); err != nil {
+			if _, err := r.client.WaitForJob(ctx, int64(unsetJobID), timeout, logJobProgress(ctx, "Unsetting Docker pool")); err != nil {
 				return fmt.Errorf("docker pool unset job failed: %w", err)
 			}
 		}
@@ -238,15 +285,14 @@ func (r *ServiceDockerResource) updateDocker(ctx context.Context, plan *ServiceD
 		return fmt.Errorf("could not update Docker service: %w", err)
 	}
 
-	if _, err := r.client.WaitForJob(ctx, int64(jobID), 5*time.Minute); err != nil {
+	if _, err := r.client.WaitForJob(ctx, int64(jobID), timeout, logJobProgress(ctx, "Updating Docker service")); err != nil {
 		return fmt.Errorf("docker update job failed: %w", err)
 	}
 
 	return nil
 }
 
-func (r *ServiceDockerResource) waitForRunning(ctx context.Context) error {
-	timeout := 10 * time.Minute
+func (r *ServiceDockerResource) waitForRunning(ctx context.Context, timeout time.Duration) error {
 	pollInterval := 2 * time.Second
 	deadline := time.Now().Add(timeout)
 
@@ -255,7 +301,7 @@ func (r *ServiceDockerResource) waitForRunning(ctx context.Context) error {
 			return fmt.Errorf("timeout waiting for Docker service to become RUNNING after %v", timeout)
 		}
 
-		var status map[string]interface{}
+		var status api.DockerStatus
 		err := r.client.Call(ctx, "docker.status", []interface{}{}, &status)
 		if err != nil {
 			tflog.Debug(ctx, "Error polling Docker status, retrying", map[string]interface{}{
@@ -265,7 +311,7 @@ func (r *ServiceDockerResource) waitForRunning(ctx context.Context) error {
 			continue
 		}
 
-		state, _ := status["status"].(string)
+		state := status.Status
 		tflog.Debug(ctx, "Docker service status", map[string]interface{}{
 			"status": state,
 		})
@@ -280,33 +326,33 @@ func (r *ServiceDockerResource) waitForRunning(ctx context.Context) error {
 
 func (r *ServiceDockerResource) readDocker(ctx context.Context, model *ServiceDockerResourceModel) error {
 	// Get Docker configuration
-	var config map[string]interface{}
+	var config api.DockerConfig
 	err := r.client.Call(ctx, "docker.config", []interface{}{}, &config)
 	if err != nil {
 		return fmt.Errorf("failed to get Docker config: %w", err)
 	}
 
-	if pool, ok := config["pool"].(string); ok {
-		model.Pool = types.StringValue(pool)
+	if config.Pool != nil {
+		model.Pool = types.StringValue(*config.Pool)
 	} else {
 		model.Pool = types.StringValue("")
 	}
-	if nvidia, ok := config["nvidia"].(bool); ok {
-		model.NvidiaEnabled = types.BoolValue(nvidia)
+	if config.Nvidia != nil {
+		model.NvidiaEnabled = types.BoolValue(*config.Nvidia)
 	}
-	if enableImageUpdates, ok := config["enable_image_updates"].(bool); ok {
-		model.EnableImageUpdates = types.BoolValue(enableImageUpdates)
+	if config.EnableImageUpdates != nil {
+		model.EnableImageUpdates = types.BoolValue(*config.EnableImageUpdates)
 	}
 
 	// Get Docker status
-	var status map[string]interface{}
+	var status api.DockerStatus
 	err = r.client.Call(ctx, "docker.status", []interface{}{}, &status)
 	if err != nil {
 		return fmt.Errorf("failed to get Docker status: %w", err)
 	}
 
-	if state, ok := status["status"].(string); ok {
-		model.Status = types.StringValue(state)
+	if status.Status != "" {
+		model.Status = types.StringValue(status.Status)
 	}
 
 	return nil
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -155,13 +156,10 @@ func (r *ServiceNFSResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	var configResult map[string]interface{}
+	var configResult api.NFSConfig
 	err := r.client.Call(ctx, "nfs.update", []interface{}{configData}, &configResult)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Configuring NFS Service",
-			"Could not configure NFS service: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Configuring NFS Service", "Could not configure NFS service", err)
 		return
 	}
 
@@ -235,13 +233,10 @@ func (r *ServiceNFSResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	var configResult map[string]interface{}
+	var configResult api.NFSConfig
 	err := r.client.Call(ctx, "nfs.update", []interface{}{configData}, &configResult)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating NFS Service",
-			"Could not update NFS service: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating NFS Service", "Could not update NFS service", err)
 		return
 	}
 
@@ -355,23 +350,14 @@ func (r *ServiceNFSResource) buildConfigData(ctx context.Context, plan *ServiceN
 
 func (r *ServiceNFSResource) readService(ctx context.Context, model *ServiceNFSResourceModel) error {
 	// Get NFS configuration
-	var config map[string]interface{}
+	var config api.NFSConfig
 	err := r.client.Call(ctx, "nfs.config", []interface{}{}, &config)
 	if err != nil {
 		return fmt.Errorf("failed to get NFS config: %w", err)
 	}
 
-	// Get service state
-	var serviceState map[string]interface{}
-	err = r.client.Call(ctx, "service.query", []interface{}{
-		[][]interface{}{{"service", "=", "nfs"}},
-	}, &serviceState)
-	if err != nil {
-		return fmt.Errorf("failed to get service state: %w", err)
-	}
-
 	// Check if service is running
-	var services []map[string]interface{}
+	var services []api.ServiceEntry
 	err = r.client.Call(ctx, "service.query", []interface{}{
 		[][]interface{}{{"service", "=", "nfs"}},
 	}, &services)
@@ -380,48 +366,42 @@ func (r *ServiceNFSResource) readService(ctx context.Context, model *ServiceNFSR
 	}
 
 	if len(services) > 0 {
-		if state, ok := services[0]["state"].(string); ok {
-			model.Enabled = types.BoolValue(state == "RUNNING")
-		}
+		model.Enabled = types.BoolValue(services[0].State == "RUNNING")
 	}
 
 	// Map config values
-	if servers, ok := config["servers"].(float64); ok {
-		model.Servers = types.Int64Value(int64(servers))
+	if config.Servers != nil {
+		model.Servers = types.Int64Value(*config.Servers)
 	}
-	if udp, ok := config["udp"].(bool); ok {
-		model.UDPEnabled = types.BoolValue(udp)
+	if config.UDP != nil {
+		model.UDPEnabled = types.BoolValue(*config.UDP)
 	}
-	if v4, ok := config["v4"].(bool); ok {
-		model.V4 = types.BoolValue(v4)
+	if config.V4 != nil {
+		model.V4 = types.BoolValue(*config.V4)
 	}
-	if v4v3owner, ok := config["v4_v3owner"].(bool); ok {
-		model.V4V3Owner = types.BoolValue(v4v3owner)
+	if config.V4V3owner != nil {
+		model.V4V3Owner = types.BoolValue(*config.V4V3owner)
 	}
-	if v4krb, ok := config["v4_krb"].(bool); ok {
-		model.V4Krb = types.BoolValue(v4krb)
+	if config.V4Krb != nil {
+		model.V4Krb = types.BoolValue(*config.V4Krb)
 	}
-	if bindip, ok := config["bindip"].([]interface{}); ok {
-		ips := make([]string, len(bindip))
-		for i, ip := range bindip {
-			ips[i] = ip.(string)
-		}
-		ipValues, diags := types.ListValueFrom(ctx, types.StringType, ips)
+	if config.Bindip != nil {
+		ipValues, diags := types.ListValueFrom(ctx, types.StringType, config.Bindip)
 		if !diags.HasError() {
 			model.Bindip = ipValues
 		}
 	}
-	if mountdPort, ok := config["mountd_port"].(float64); ok {
-		model.MountdPort = types.Int64Value(int64(mountdPort))
+	if config.MountdPort != nil {
+		model.MountdPort = types.Int64Value(*config.MountdPort)
 	}
-	if rpcstatdPort, ok := config["rpcstatd_port"].(float64); ok {
-		model.RpclockdPort = types.Int64Value(int64(rpcstatdPort))
+	if config.RpcstatdPort != nil {
+		model.RpclockdPort = types.Int64Value(*config.RpcstatdPort)
 	}
-	if allowNonroot, ok := config["allow_nonroot"].(bool); ok {
-		model.AllowNonroot = types.BoolValue(allowNonroot)
+	if config.AllowNonroot != nil {
+		model.AllowNonroot = types.BoolValue(*config.AllowNonroot)
 	}
-	if managedNfsv4Acl, ok := config["managed_nfsv4_acl"].(bool); ok {
-		model.ManagedNFSv4ACL = types.BoolValue(managedNfsv4Acl)
+	if config.ManagedNfsv4ACL != nil {
+		model.ManagedNFSv4ACL = types.BoolValue(*config.ManagedNfsv4ACL)
 	}
 
 	return nil
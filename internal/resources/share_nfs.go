@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -163,6 +164,9 @@ func (r *ShareNFSResource) Configure(ctx context.Context, req resource.Configure
 	}
 
 	r.client = client
+
+	// Every NFS share change reloads the NFS server, and concurrent reloads fail
+	client.Serialize("sharing.nfs")
 }
 
 func (r *ShareNFSResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -237,17 +241,14 @@ func (r *ShareNFSResource) Create(ctx context.Context, req resource.CreateReques
 		createData["ro"] = plan.Ro.ValueBool()
 	}
 
-	var result map[string]interface{}
+	var result api.NFSShareEntry
 	err := r.client.Create(ctx, "sharing.nfs", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating NFS Share",
-			"Could not create NFS share: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating NFS Share", "Could not create NFS share", err)
 		return
 	}
 
-	shareID := int64(result["id"].(float64))
+	shareID := result.ID
 	if err := r.readShare(ctx, shareID, &plan); err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading NFS Share",
@@ -371,13 +372,10 @@ func (r *ShareNFSResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.NFSShareEntry
 		err := r.client.Update(ctx, "sharing.nfs", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error Updating NFS Share",
-				"Could not update NFS share: "+err.Error(),
-			)
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating NFS Share", "Could not update NFS share", err)
 			return
 		}
 	}
@@ -429,95 +427,73 @@ func (r *ShareNFSResource) ImportState(ctx context.Context, req resource.ImportS
 }
 
 func (r *ShareNFSResource) readShare(ctx context.Context, id int64, model *ShareNFSResourceModel) error {
-	var result map[string]interface{}
+	var result api.NFSShareEntry
 	err := r.client.GetInstance(ctx, "sharing.nfs", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Path = types.StringValue(result["path"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Path = types.StringValue(result.Path)
 
-	if aliases, ok := result["aliases"].([]interface{}); ok {
-		aliasList := make([]string, len(aliases))
-		for i, a := range aliases {
-			aliasList[i] = a.(string)
-		}
-		aliasValues, diags := types.ListValueFrom(ctx, types.StringType, aliasList)
+	// aliases is Optional+Computed with an empty-list default; populate so
+	// imported state matches the schema's effective config.
+	if result.Aliases != nil {
+		aliasValues, diags := types.ListValueFrom(ctx, types.StringType, result.Aliases)
 		if !diags.HasError() {
 			model.Aliases = aliasValues
 		}
 	}
-	if comment, ok := result["comment"].(string); ok && comment != "" {
-		model.Comment = types.StringValue(comment)
+	if result.Comment != nil && *result.Comment != "" {
+		model.Comment = types.StringValue(*result.Comment)
 	} else if model.Comment.IsUnknown() {
 		model.Comment = types.StringNull()
 	}
-	if enabled, ok := result["enabled"].(bool); ok {
-		model.Enabled = types.BoolValue(enabled)
+	if result.Enabled != nil {
+		model.Enabled = types.BoolValue(*result.Enabled)
 	}
-	if networks, ok := result["networks"].([]interface{}); ok && len(networks) > 0 {
-		networkList := make([]string, len(networks))
-		for i, n := range networks {
-			networkList[i] = n.(string)
-		}
-		networkValues, diags := types.ListValueFrom(ctx, types.StringType, networkList)
+	if len(result.Networks) > 0 {
+		networkValues, diags := types.ListValueFrom(ctx, types.StringType, result.Networks)
 		if !diags.HasError() {
 			model.Networks = networkValues
 		}
 	} else if model.Networks.IsUnknown() {
 		model.Networks = types.ListNull(types.StringType)
 	}
-	if hosts, ok := result["hosts"].([]interface{}); ok {
-		hostList := make([]string, len(hosts))
-		for i, h := range hosts {
-			hostList[i] = h.(string)
-		}
-		hostValues, diags := types.ListValueFrom(ctx, types.StringType, hostList)
+	if result.Hosts != nil {
+		hostValues, diags := types.ListValueFrom(ctx, types.StringType, result.Hosts)
 		if !diags.HasError() {
 			model.Hosts = hostValues
 		}
 	}
-	// aliases is Optional+Computed with an empty-list default; populate so
-	// imported state matches the schema's effective config.
-	if aliases, ok := result["aliases"].([]interface{}); ok {
-		aliasList := make([]string, len(aliases))
-		for i, a := range aliases {
-			aliasList[i] = a.(string)
-		}
-		aliasValues, diags := types.ListValueFrom(ctx, types.StringType, aliasList)
-		if !diags.HasError() {
-			model.Aliases = aliasValues
-		}
+	if result.MaprootUser != nil {
+		model.MaprootUser = types.StringValue(*result.MaprootUser)
 	}
-	if maprootUser, ok := result["maproot_user"].(string); ok {
-		model.MaprootUser = types.StringValue(maprootUser)
+	if result.MaprootGroup != nil {
+		model.MaprootGroup = types.StringValue(*result.MaprootGroup)
 	}
-	if maprootGroup, ok := result["maproot_group"].(string); ok {
-		model.MaprootGroup = types.StringValue(maprootGroup)
+	if result.MapallUser != nil {
+		model.MapallUser = types.StringValue(*result.MapallUser)
 	}
-	if mapallUser, ok := result["mapall_user"].(string); ok {
-		model.MapallUser = types.StringValue(mapallUser)
+	if result.MapallGroup != nil {
+		model.MapallGroup = types.StringValue(*result.MapallGroup)
 	}
-	if mapallGroup, ok := result["mapall_group"].(string); ok {
-		model.MapallGroup = types.StringValue(mapallGroup)
-	}
-	if security, ok := result["security"].([]interface{}); ok {
-		secList := make([]string, len(security))
-		for i, s := range security {
+	if result.Security != nil {
+		secList := make([]string, len(result.Security))
+		for i, s := range result.Security {
 			// Normalize to lowercase for consistent state (users typically write lowercase)
-			secList[i] = strings.ToLower(s.(string))
+			secList[i] = strings.ToLower(s)
 		}
 		secValues, diags := types.ListValueFrom(ctx, types.StringType, secList)
 		if !diags.HasError() {
 			model.Security = secValues
 		}
 	}
-	if ro, ok := result["ro"].(bool); ok {
-		model.Ro = types.BoolValue(ro)
+	if result.Ro != nil {
+		model.Ro = types.BoolValue(*result.Ro)
 	}
-	if locked, ok := result["locked"].(bool); ok {
-		model.Locked = types.BoolValue(locked)
+	if result.Locked != nil {
+		model.Locked = types.BoolValue(*result.Locked)
 	}
 
 	return nil
@@ -15,12 +15,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
 var (
 	_ resource.Resource                = &ShareSMBResource{}
 	_ resource.ResourceWithImportState = &ShareSMBResource{}
+	_ resource.ResourceWithModifyPlan  = &ShareSMBResource{}
 )
 
 func NewShareSMBResource() resource.Resource {
@@ -218,6 +220,133 @@ func (r *ShareSMBResource) Configure(ctx context.Context, req resource.Configure
 	r.client = client
 }
 
+// smbShareFieldAliases maps the fields TrueNAS 25.10 reports validation
+// failures under, after renaming ro and abe and moving the other settings
+// under options, to the schema attributes they are set from
+var smbShareFieldAliases = fieldAliases{
+	"readonly":                       "ro",
+	"access_based_share_enumeration": "abe",
+	"options.hostsallow":             "hostsallow",
+	"options.hostsdeny":              "hostsdeny",
+	"options.path_suffix":            "path_suffix",
+	"options.auxsmbconf":             "auxsmbconf",
+	"options.home":                   "home",
+	"options.timemachine":            "timemachine",
+	"options.recyclebin":             "recyclebin",
+	"options.guestok":                "guestok",
+	"options.acl":                    "acl",
+	"options.durablehandle":          "durablehandle",
+	"options.shadowcopy":             "shadowcopy",
+	"options.streams":                "streams",
+	"options.fsrvp":                  "fsrvp",
+}
+
+// smbShareRenamedFlags maps the share flags TrueNAS 25.10 renamed to their
+// new names. Unlike the other flags, 25.10 still updates them.
+var smbShareRenamedFlags = map[string]string{
+	"ro":  "readonly",
+	"abe": "access_based_share_enumeration",
+}
+
+// smbShareOptions are the payload fields TrueNAS 25.10 only takes under
+// options: the host lists, path_suffix, auxsmbconf and the share flags it
+// didn't rename
+var smbShareOptions = map[string]bool{
+	"hostsallow":    true,
+	"hostsdeny":     true,
+	"path_suffix":   true,
+	"auxsmbconf":    true,
+	"home":          true,
+	"timemachine":   true,
+	"recyclebin":    true,
+	"guestok":       true,
+	"acl":           true,
+	"durablehandle": true,
+	"shadowcopy":    true,
+	"streams":       true,
+	"fsrvp":         true,
+}
+
+// payload turns a create or update payload built with the pre-25.10 field
+// names into the shape the connected TrueNAS accepts
+func (r *ShareSMBResource) payload(data map[string]interface{}) map[string]interface{} {
+	if !r.client.Supports(client.CapabilitySMBShareOptions) {
+		return data
+	}
+
+	out := make(map[string]interface{}, len(data))
+	options := map[string]interface{}{}
+	for field, value := range data {
+		if name, ok := smbShareRenamedFlags[field]; ok {
+			out[name] = value
+		} else if smbShareOptions[field] {
+			options[field] = value
+		} else {
+			out[field] = value
+		}
+	}
+	if len(options) > 0 {
+		out["options"] = options
+	}
+	return out
+}
+
+// smbShareFlag is a boolean share setting, named as in the API
+type smbShareFlag struct {
+	name  string
+	value types.Bool
+}
+
+// smbShareFlags returns the share settings that TrueNAS releases without
+// client.CapabilitySMBFlagUpdate only accept on create
+func smbShareFlags(model *ShareSMBResourceModel) []smbShareFlag {
+	return []smbShareFlag{
+		{"home", model.Home},
+		{"timemachine", model.TimeMachine},
+		{"ro", model.Ro},
+		{"recyclebin", model.Recyclebin},
+		{"guestok", model.Guestok},
+		{"abe", model.Abe},
+		{"acl", model.Acl},
+		{"durablehandle", model.Durablehandle},
+		{"shadowcopy", model.Shadowcopy},
+		{"streams", model.Streams},
+		{"fsrvp", model.Fsrvp},
+	}
+}
+
+// ModifyPlan rejects changes to share flags the connected TrueNAS can't
+// update, rather than letting the apply silently drop them. TrueNAS 25.10
+// still updates ro and abe under their new names.
+func (r *ShareSMBResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	if r.client.Supports(client.CapabilitySMBFlagUpdate) {
+		return
+	}
+
+	var plan, state ShareSMBResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	renamed := r.client.Supports(client.CapabilitySMBShareOptions)
+	stateFlags := smbShareFlags(&state)
+	for i, flag := range smbShareFlags(&plan) {
+		if flag.value.IsUnknown() || flag.value.Equal(stateFlags[i].value) {
+			continue
+		}
+		if _, ok := smbShareRenamedFlags[flag.name]; ok && renamed {
+			continue
+		}
+		addUnsupportedError(&resp.Diagnostics, r.client, path.Root(flag.name),
+			fmt.Sprintf("%q can only be set when an SMB share is created. Recreate the share to change it.", flag.name))
+	}
+}
+
 func (r *ShareSMBResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan ShareSMBResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -303,17 +432,14 @@ func (r *ShareSMBResource) Create(ctx context.Context, req resource.CreateReques
 	}
 	// Note: audit_logging is not supported in TrueNAS Scale 25
 
-	var result map[string]interface{}
-	err := r.client.Create(ctx, "sharing.smb", createData, &result)
+	var result api.SMBShareEntry
+	err := r.client.Create(ctx, "sharing.smb", r.payload(createData), &result)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating SMB Share",
-			"Could not create SMB share: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, smbShareFieldAliases, "Error Creating SMB Share", "Could not create SMB share", err)
 		return
 	}
 
-	shareID := int64(result["id"].(float64))
+	shareID := result.ID
 	if err := r.readShare(ctx, shareID, &plan); err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SMB Share",
@@ -390,34 +516,24 @@ func (r *ShareSMBResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 	// Always include enabled and browsable in update - TrueNAS Scale 25/25.10
 	// resets these to defaults if omitted from the update payload.
-	// Note: ro, guestok, recyclebin, abe, acl, durablehandle, shadowcopy,
-	// streams, fsrvp, and audit_logging are NOT accepted in the update API on
-	// TrueNAS 25.10 — they can only be set at creation time.
 	updateData["enabled"] = plan.Enabled.ValueBool()
 	updateData["browsable"] = plan.Browsable.ValueBool()
-	if !plan.Home.Equal(state.Home) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'home' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
 	if !plan.Purpose.Equal(state.Purpose) {
 		updateData["purpose"] = plan.Purpose.ValueString()
 	}
-	if !plan.TimeMachine.Equal(state.TimeMachine) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'timemachine' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Abe.Equal(state.Abe) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'abe' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Ro.Equal(state.Ro) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'ro' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Browsable.Equal(state.Browsable) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'browsable' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Recyclebin.Equal(state.Recyclebin) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'recyclebin' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Guestok.Equal(state.Guestok) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'guestok' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
+	// Releases without the capability only take the share flags on create,
+	// except ro and abe on 25.10; ModifyPlan rejects changes to the others
+	// there.
+	flagUpdate := r.client.Supports(client.CapabilitySMBFlagUpdate)
+	renamed := r.client.Supports(client.CapabilitySMBShareOptions)
+	stateFlags := smbShareFlags(&state)
+	for i, flag := range smbShareFlags(&plan) {
+		if flag.value.Equal(stateFlags[i].value) {
+			continue
+		}
+		if _, ok := smbShareRenamedFlags[flag.name]; flagUpdate || (ok && renamed) {
+			updateData[flag.name] = flag.value.ValueBool()
+		}
 	}
 	if !plan.HostsAllow.Equal(state.HostsAllow) {
 		var hosts []string
@@ -442,30 +558,12 @@ func (r *ShareSMBResource) Update(ctx context.Context, req resource.UpdateReques
 			updateData["auxsmbconf"] = plan.AuxSMBConf.ValueString()
 		}
 	}
-	if !plan.Acl.Equal(state.Acl) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'acl' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Durablehandle.Equal(state.Durablehandle) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'durablehandle' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Shadowcopy.Equal(state.Shadowcopy) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'shadowcopy' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Streams.Equal(state.Streams) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'streams' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
-	if !plan.Fsrvp.Equal(state.Fsrvp) {
-		resp.Diagnostics.AddWarning("SMB Field Update Limitation", "The 'fsrvp' field cannot be updated after creation in TrueNAS Scale 25. Recreate the share to change this value.")
-	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
-		err := r.client.Update(ctx, "sharing.smb", state.ID.ValueInt64(), updateData, &result)
+		var result api.SMBShareEntry
+		err := r.client.Update(ctx, "sharing.smb", state.ID.ValueInt64(), r.payload(updateData), &result)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error Updating SMB Share",
-				"Could not update SMB share: "+err.Error(),
-			)
+			addAPIError(&resp.Diagnostics, req.Plan, smbShareFieldAliases, "Error Updating SMB Share", "Could not update SMB share", err)
 			return
 		}
 	}
@@ -516,144 +614,158 @@ func (r *ShareSMBResource) ImportState(ctx context.Context, req resource.ImportS
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
+// smbShareShape holds the SMB share fields that TrueNAS 25.10 renamed or
+// moved, as read from one release's shape of sharing.smb.query
+type smbShareShape struct {
+	abe, ro                *bool
+	hostsallow, hostsdeny  []string
+	pathSuffix, auxsmbconf *string
+}
+
 func (r *ShareSMBResource) readShare(ctx context.Context, id int64, model *ShareSMBResourceModel) error {
-	var result map[string]interface{}
+	var result api.SMBShareEntry
 	err := r.client.GetInstance(ctx, "sharing.smb", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Path = types.StringValue(result["path"].(string))
-	model.Name = types.StringValue(result["name"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Path = types.StringValue(result.Path)
+	model.Name = types.StringValue(result.Name)
 
-	if pathSuffix, ok := result["path_suffix"].(string); ok && pathSuffix != "" {
-		model.PathSuffix = types.StringValue(pathSuffix)
-	}
-	if comment, ok := result["comment"].(string); ok && comment != "" {
-		model.Comment = types.StringValue(comment)
+	if result.Comment != nil && *result.Comment != "" {
+		model.Comment = types.StringValue(*result.Comment)
 	} else if model.Comment.IsUnknown() {
 		model.Comment = types.StringNull()
 	}
-	if enabled, ok := result["enabled"].(bool); ok {
-		model.Enabled = types.BoolValue(enabled)
+	if result.Enabled != nil {
+		model.Enabled = types.BoolValue(*result.Enabled)
 	}
-	if purpose, ok := result["purpose"].(string); ok {
-		model.Purpose = types.StringValue(purpose)
+	if result.Purpose != nil {
+		model.Purpose = types.StringValue(*result.Purpose)
 	}
-	if browsable, ok := result["browsable"].(bool); ok {
-		model.Browsable = types.BoolValue(browsable)
+	if result.Browsable != nil {
+		model.Browsable = types.BoolValue(*result.Browsable)
 	}
-	if locked, ok := result["locked"].(bool); ok {
-		model.Locked = types.BoolValue(locked)
+	if result.Locked != nil {
+		model.Locked = types.BoolValue(*result.Locked)
 	}
 
-	// TrueNAS 25.10 renamed several SMB share fields. Read the new names first
-	// and fall back to the legacy flat names so the provider keeps working
-	// against older TrueNAS versions.
-	if abe, ok := result["access_based_share_enumeration"].(bool); ok {
-		model.Abe = types.BoolValue(abe)
-	} else if abe, ok := result["abe"].(bool); ok {
-		model.Abe = types.BoolValue(abe)
+	// TrueNAS 25.10 renamed several SMB share fields and moved hostsallow,
+	// hostsdeny, path_suffix and auxsmbconf under `options`. Prefer the shape
+	// of the detected release but fall back to the other, so a failed
+	// version detection doesn't null them out.
+	legacy := smbShareShape{
+		abe: result.Abe, ro: result.Ro,
+		hostsallow: result.Hostsallow, hostsdeny: result.Hostsdeny,
+		pathSuffix: result.PathSuffix, auxsmbconf: result.Auxsmbconf,
 	}
-	if ro, ok := result["readonly"].(bool); ok {
-		model.Ro = types.BoolValue(ro)
-	} else if ro, ok := result["ro"].(bool); ok {
-		model.Ro = types.BoolValue(ro)
+	current := smbShareShape{abe: result.AccessBasedShareEnumeration, ro: result.Readonly}
+	if result.Options != nil {
+		current.hostsallow, current.hostsdeny = result.Options.Hostsallow, result.Options.Hostsdeny
+		current.pathSuffix, current.auxsmbconf = result.Options.PathSuffix, result.Options.Auxsmbconf
 	}
-	if audit, ok := result["audit"].(map[string]interface{}); ok {
-		if enable, ok := audit["enable"].(bool); ok {
-			model.AuditLogging = types.BoolValue(enable)
-		}
-	} else if auditLogging, ok := result["audit_logging"].(bool); ok {
-		model.AuditLogging = types.BoolValue(auditLogging)
+	shape, fallback := legacy, current
+	if r.client.Supports(client.CapabilitySMBShareOptions) {
+		shape, fallback = current, legacy
 	}
-
-	// hostsallow / hostsdeny moved under `options` in 25.10.
-	hostsallowSrc := result["hostsallow"]
-	hostsdenySrc := result["hostsdeny"]
-	if options, ok := result["options"].(map[string]interface{}); ok {
-		if v, ok := options["hostsallow"]; ok {
-			hostsallowSrc = v
-		}
-		if v, ok := options["hostsdeny"]; ok {
-			hostsdenySrc = v
-		}
+	if shape.abe == nil {
+		shape.abe = fallback.abe
+	}
+	if shape.ro == nil {
+		shape.ro = fallback.ro
+	}
+	if shape.hostsallow == nil {
+		shape.hostsallow = fallback.hostsallow
+	}
+	if shape.hostsdeny == nil {
+		shape.hostsdeny = fallback.hostsdeny
+	}
+	if shape.pathSuffix == nil {
+		shape.pathSuffix = fallback.pathSuffix
+	}
+	if shape.auxsmbconf == nil {
+		shape.auxsmbconf = fallback.auxsmbconf
+	}
+	abe, ro, hostsallow, hostsdeny := shape.abe, shape.ro, shape.hostsallow, shape.hostsdeny
+	if shape.pathSuffix != nil && *shape.pathSuffix != "" {
+		model.PathSuffix = types.StringValue(*shape.pathSuffix)
 	}
+	if shape.auxsmbconf != nil {
+		model.AuxSMBConf = types.StringValue(*shape.auxsmbconf)
+	}
+	if abe != nil {
+		model.Abe = types.BoolValue(*abe)
+	}
+	if ro != nil {
+		model.Ro = types.BoolValue(*ro)
+	}
+	if result.Audit != nil && result.Audit.Enable != nil {
+		model.AuditLogging = types.BoolValue(*result.Audit.Enable)
+	}
+
 	// Only materialize a list when the API actually returns entries — config
 	// authors leave hostsallow/hostsdeny unset (null), and an empty []
 	// from the API would diverge from null on the next plan.
-	if hostsallow, ok := hostsallowSrc.([]interface{}); ok && len(hostsallow) > 0 {
-		hosts := make([]string, len(hostsallow))
-		for i, h := range hostsallow {
-			hosts[i] = h.(string)
-		}
-		hostValues, diags := types.ListValueFrom(ctx, types.StringType, hosts)
+	if len(hostsallow) > 0 {
+		hostValues, diags := types.ListValueFrom(ctx, types.StringType, hostsallow)
 		if !diags.HasError() {
 			model.HostsAllow = hostValues
 		}
 	}
-	if hostsdeny, ok := hostsdenySrc.([]interface{}); ok && len(hostsdeny) > 0 {
-		hosts := make([]string, len(hostsdeny))
-		for i, h := range hostsdeny {
-			hosts[i] = h.(string)
-		}
-		hostValues, diags := types.ListValueFrom(ctx, types.StringType, hosts)
+	if len(hostsdeny) > 0 {
+		hostValues, diags := types.ListValueFrom(ctx, types.StringType, hostsdeny)
 		if !diags.HasError() {
 			model.HostsDeny = hostValues
 		}
 	}
 
 	// The following fields are no longer returned by sharing.smb.query on
-	// TrueNAS 25.10 (the API silently accepts them on input for backwards
-	// compatibility but doesn't echo them back). Read them where available
-	// (older TrueNAS); otherwise default to schema defaults so freshly-imported
-	// state matches a config that uses those defaults.
-	if home, ok := result["home"].(bool); ok {
-		model.Home = types.BoolValue(home)
+	// TrueNAS 25.10, which only takes them under options on input. Read them
+	// where available (older TrueNAS); otherwise default to schema defaults
+	// so freshly-imported state matches a config that uses those defaults.
+	if result.Home != nil {
+		model.Home = types.BoolValue(*result.Home)
 	} else if model.Home.IsNull() || model.Home.IsUnknown() {
 		model.Home = types.BoolValue(false)
 	}
-	if timemachine, ok := result["timemachine"].(bool); ok {
-		model.TimeMachine = types.BoolValue(timemachine)
+	if result.Timemachine != nil {
+		model.TimeMachine = types.BoolValue(*result.Timemachine)
 	} else if model.TimeMachine.IsNull() || model.TimeMachine.IsUnknown() {
 		model.TimeMachine = types.BoolValue(false)
 	}
-	if recyclebin, ok := result["recyclebin"].(bool); ok {
-		model.Recyclebin = types.BoolValue(recyclebin)
+	if result.Recyclebin != nil {
+		model.Recyclebin = types.BoolValue(*result.Recyclebin)
 	} else if model.Recyclebin.IsNull() || model.Recyclebin.IsUnknown() {
 		model.Recyclebin = types.BoolValue(false)
 	}
-	if guestok, ok := result["guestok"].(bool); ok {
-		model.Guestok = types.BoolValue(guestok)
+	if result.Guestok != nil {
+		model.Guestok = types.BoolValue(*result.Guestok)
 	} else if model.Guestok.IsNull() || model.Guestok.IsUnknown() {
 		model.Guestok = types.BoolValue(false)
 	}
-	if auxsmbconf, ok := result["auxsmbconf"].(string); ok {
-		model.AuxSMBConf = types.StringValue(auxsmbconf)
-	}
-	if acl, ok := result["acl"].(bool); ok {
-		model.Acl = types.BoolValue(acl)
+	if result.ACL != nil {
+		model.Acl = types.BoolValue(*result.ACL)
 	} else if model.Acl.IsNull() || model.Acl.IsUnknown() {
 		model.Acl = types.BoolValue(true)
 	}
-	if durablehandle, ok := result["durablehandle"].(bool); ok {
-		model.Durablehandle = types.BoolValue(durablehandle)
+	if result.Durablehandle != nil {
+		model.Durablehandle = types.BoolValue(*result.Durablehandle)
 	} else if model.Durablehandle.IsNull() || model.Durablehandle.IsUnknown() {
 		model.Durablehandle = types.BoolValue(true)
 	}
-	if shadowcopy, ok := result["shadowcopy"].(bool); ok {
-		model.Shadowcopy = types.BoolValue(shadowcopy)
+	if result.Shadowcopy != nil {
+		model.Shadowcopy = types.BoolValue(*result.Shadowcopy)
 	} else if model.Shadowcopy.IsNull() || model.Shadowcopy.IsUnknown() {
 		model.Shadowcopy = types.BoolValue(true)
 	}
-	if streams, ok := result["streams"].(bool); ok {
-		model.Streams = types.BoolValue(streams)
+	if result.Streams != nil {
+		model.Streams = types.BoolValue(*result.Streams)
 	} else if model.Streams.IsNull() || model.Streams.IsUnknown() {
 		model.Streams = types.BoolValue(true)
 	}
-	if fsrvp, ok := result["fsrvp"].(bool); ok {
-		model.Fsrvp = types.BoolValue(fsrvp)
+	if result.Fsrvp != nil {
+		model.Fsrvp = types.BoolValue(*result.Fsrvp)
 	} else if model.Fsrvp.IsNull() || model.Fsrvp.IsUnknown() {
 		model.Fsrvp = types.BoolValue(false)
 	}
@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -161,13 +163,10 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 		createData["properties"] = props
 	}
 
-	var result map[string]interface{}
+	var result api.SnapshotEntry
 	err := r.client.Create(ctx, "zfs.snapshot", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating Snapshot",
-			"Could not create snapshot: "+err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating Snapshot", "Could not create snapshot", err)
 		return
 	}
 
@@ -237,13 +236,10 @@ func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateReques
 			"user_properties_update": props,
 		}
 
-		var result map[string]interface{}
+		var result api.SnapshotEntry
 		err := r.client.Update(ctx, "zfs.snapshot", state.ID.ValueString(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error Updating Snapshot",
-				"Could not update snapshot: "+err.Error(),
-			)
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating Snapshot", "Could not update snapshot", err)
 			return
 		}
 	}
@@ -292,13 +288,13 @@ func (r *SnapshotResource) ImportState(ctx context.Context, req resource.ImportS
 }
 
 func (r *SnapshotResource) readSnapshot(ctx context.Context, id string, model *SnapshotResourceModel) error {
-	var result map[string]interface{}
+	var result api.SnapshotEntry
 	err := r.client.GetInstance(ctx, "zfs.snapshot", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.StringValue(result["id"].(string))
+	model.ID = types.StringValue(result.ID)
 
 	// Parse dataset and name from the ID
 	parts := strings.SplitN(id, "@", 2)
@@ -307,11 +303,23 @@ func (r *SnapshotResource) readSnapshot(ctx context.Context, id string, model *S
 		model.Name = types.StringValue(parts[1])
 	}
 
-	if holds, ok := result["holds"].([]interface{}); ok && len(holds) > 0 {
-		holdsList := make([]string, len(holds))
-		for i, h := range holds {
-			holdsList[i] = h.(string)
+	// holds is a list of tags, or an object keyed by tag when the holds are
+	// requested with their details
+	var holdsList []string
+	switch holds := result.Holds.(type) {
+	case []interface{}:
+		for _, h := range holds {
+			if tag, ok := h.(string); ok {
+				holdsList = append(holdsList, tag)
+			}
 		}
+	case map[string]interface{}:
+		for tag := range holds {
+			holdsList = append(holdsList, tag)
+		}
+		sort.Strings(holdsList)
+	}
+	if len(holdsList) > 0 {
 		holdValues, diags := types.ListValueFrom(ctx, types.StringType, holdsList)
 		if !diags.HasError() {
 			model.Holds = holdValues
@@ -335,28 +343,24 @@ func (r *SnapshotResource) readSnapshot(ctx context.Context, id string, model *S
 		model.VMWareSync = types.BoolValue(false)
 	}
 
-	if properties, ok := result["properties"].(map[string]interface{}); ok {
-		if referenced, ok := properties["referenced"].(map[string]interface{}); ok {
-			if parsed, ok := referenced["parsed"].(float64); ok {
-				model.ReferencedBytes = types.Int64Value(int64(parsed))
-			}
+	if referenced, ok := result.Properties["referenced"]; ok {
+		if parsed, ok := referenced.Int(); ok {
+			model.ReferencedBytes = types.Int64Value(parsed)
 		}
-		if used, ok := properties["used"].(map[string]interface{}); ok {
-			if parsed, ok := used["parsed"].(float64); ok {
-				model.UsedBytes = types.Int64Value(int64(parsed))
-			}
+	}
+	if used, ok := result.Properties["used"]; ok {
+		if parsed, ok := used.Int(); ok {
+			model.UsedBytes = types.Int64Value(parsed)
 		}
-		if creation, ok := properties["creation"].(map[string]interface{}); ok {
-			// creation.parsed can be either a string or a timestamp
-			if parsed, ok := creation["parsed"].(string); ok {
-				model.CreationTime = types.StringValue(parsed)
-			} else if rawValue, ok := creation["rawvalue"].(string); ok {
-				model.CreationTime = types.StringValue(rawValue)
-			} else if value, ok := creation["value"].(string); ok {
-				model.CreationTime = types.StringValue(value)
-			} else {
-				model.CreationTime = types.StringNull()
-			}
+	}
+	if creation, ok := result.Properties["creation"]; ok {
+		// creation.parsed can be either a string or a timestamp
+		if parsed, ok := creation.Parsed.(string); ok {
+			model.CreationTime = types.StringValue(parsed)
+		} else if creation.Rawvalue != nil {
+			model.CreationTime = types.StringValue(*creation.Rawvalue)
+		} else if value, ok := creation.Text(); ok {
+			model.CreationTime = types.StringValue(value)
 		} else {
 			model.CreationTime = types.StringNull()
 		}
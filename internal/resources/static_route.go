@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -96,14 +97,14 @@ func (r *StaticRouteResource) Create(ctx context.Context, req resource.CreateReq
 		createData["description"] = plan.Description.ValueString()
 	}
 
-	var result map[string]interface{}
+	var result api.StaticRouteEntry
 	err := r.client.Create(ctx, "staticroute", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Static Route", "Could not create static route: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating Static Route", "Could not create static route", err)
 		return
 	}
 
-	routeID := int64(result["id"].(float64))
+	routeID := result.ID
 	if err := r.readStaticRoute(ctx, routeID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading Static Route", "Could not read static route after creation: "+err.Error())
 		return
@@ -166,10 +167,10 @@ func (r *StaticRouteResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.StaticRouteEntry
 		err := r.client.Update(ctx, "staticroute", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating Static Route", "Could not update static route: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating Static Route", "Could not update static route", err)
 			return
 		}
 	}
@@ -211,18 +212,18 @@ func (r *StaticRouteResource) ImportState(ctx context.Context, req resource.Impo
 }
 
 func (r *StaticRouteResource) readStaticRoute(ctx context.Context, id int64, model *StaticRouteResourceModel) error {
-	var result map[string]interface{}
+	var result api.StaticRouteEntry
 	err := r.client.GetInstance(ctx, "staticroute", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Destination = types.StringValue(result["destination"].(string))
-	model.Gateway = types.StringValue(result["gateway"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Destination = types.StringValue(result.Destination)
+	model.Gateway = types.StringValue(result.Gateway)
 
-	if description, ok := result["description"].(string); ok {
-		model.Description = types.StringValue(description)
+	if result.Description != nil {
+		model.Description = types.StringValue(*result.Description)
 	}
 
 	return nil
@@ -0,0 +1,17 @@
+package resources
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout bounds ctx by an operation's timeout from the resource's
+// timeouts block. Calls made under the deadline wait for their response as
+// long as it allows. Zero, the default of operations that run no job, leaves
+// ctx alone so each call keeps the provider's request_timeout.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
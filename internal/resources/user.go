@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -262,14 +263,14 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 		createData["sshpubkey"] = plan.SSHPubKey.ValueString()
 	}
 
-	var result map[string]interface{}
+	var result api.UserEntry
 	err := r.client.Create(ctx, "user", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating User", "Could not create user: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating User", "Could not create user", err)
 		return
 	}
 
-	userID := int64(result["id"].(float64))
+	userID := result.ID
 	if err := r.readUser(ctx, userID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading User", "Could not read user after creation: "+err.Error())
 		return
@@ -374,10 +375,10 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.UserEntry
 		err := r.client.Update(ctx, "user", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating User", "Could not update user: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating User", "Could not update user", err)
 			return
 		}
 	}
@@ -419,83 +420,68 @@ func (r *UserResource) ImportState(ctx context.Context, req resource.ImportState
 }
 
 func (r *UserResource) readUser(ctx context.Context, id int64, model *UserResourceModel) error {
-	var result map[string]interface{}
+	var result api.UserEntry
 	err := r.client.GetInstance(ctx, "user", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.UID = types.Int64Value(int64(result["uid"].(float64)))
-	model.Username = types.StringValue(result["username"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.UID = types.Int64Value(result.UID)
+	model.Username = types.StringValue(result.Username)
 
-	if fullName, ok := result["full_name"].(string); ok {
-		model.FullName = types.StringValue(fullName)
+	if result.FullName != nil {
+		model.FullName = types.StringValue(*result.FullName)
 	}
-	if email, ok := result["email"].(string); ok {
-		model.Email = types.StringValue(email)
+	if result.Email != nil {
+		model.Email = types.StringValue(*result.Email)
 	}
-	if passwordDisabled, ok := result["password_disabled"].(bool); ok {
-		model.PasswordDisabled = types.BoolValue(passwordDisabled)
+	if result.PasswordDisabled != nil {
+		model.PasswordDisabled = types.BoolValue(*result.PasswordDisabled)
 	}
-	if group, ok := result["group"].(map[string]interface{}); ok {
-		if gid, ok := group["id"].(float64); ok {
-			model.Group = types.Int64Value(int64(gid))
-		}
+	if result.Group != nil {
+		model.Group = types.Int64Value(result.Group.ID)
 	}
-	if groups, ok := result["groups"].([]interface{}); ok && len(groups) > 0 {
-		var groupIDs []int64
-		for _, g := range groups {
-			if gmap, ok := g.(map[string]interface{}); ok {
-				if gid, ok := gmap["id"].(float64); ok {
-					// Filter out invalid group IDs (0 or negative)
-					if int64(gid) > 0 {
-						groupIDs = append(groupIDs, int64(gid))
-					}
-				}
-			}
-		}
-		// Only set groups if there are valid group IDs
-		if len(groupIDs) > 0 {
-			groupValues, diags := types.ListValueFrom(ctx, types.Int64Type, groupIDs)
-			if !diags.HasError() {
-				model.Groups = groupValues
-			}
+	// groups holds the IDs of the user's auxiliary groups. TrueNAS adds new
+	// users to builtin_users by itself, so only refresh groups the
+	// configuration manages; otherwise every plan would try to remove it.
+	var groupIDs []int64
+	for _, gid := range result.Groups {
+		// Filter out invalid group IDs (0 or negative)
+		if gid > 0 {
+			groupIDs = append(groupIDs, gid)
 		}
 	}
-	if home, ok := result["home"].(string); ok {
-		model.Home = types.StringValue(home)
-	}
-	if shell, ok := result["shell"].(string); ok {
-		model.Shell = types.StringValue(shell)
+	// Only set groups if there are valid group IDs
+	if !model.Groups.IsNull() && len(groupIDs) > 0 {
+		groupValues, diags := types.ListValueFrom(ctx, types.Int64Type, groupIDs)
+		if !diags.HasError() {
+			model.Groups = groupValues
+		}
 	}
-	if sshpubkey, ok := result["sshpubkey"].(string); ok {
-		model.SSHPubKey = types.StringValue(sshpubkey)
+	if result.Home != nil {
+		model.Home = types.StringValue(*result.Home)
 	}
-	if locked, ok := result["locked"].(bool); ok {
-		model.Locked = types.BoolValue(locked)
+	if result.Shell != nil {
+		model.Shell = types.StringValue(*result.Shell)
 	}
-	if smb, ok := result["smb"].(bool); ok {
-		model.SMB = types.BoolValue(smb)
+	if result.Sshpubkey != nil {
+		model.SSHPubKey = types.StringValue(*result.Sshpubkey)
 	}
-	if sudo, ok := result["sudo"].(bool); ok {
-		model.Sudo = types.BoolValue(sudo)
+	if result.Locked != nil {
+		model.Locked = types.BoolValue(*result.Locked)
 	}
-	if sudoNopasswd, ok := result["sudo_nopasswd"].(bool); ok {
-		model.SudoNopasswd = types.BoolValue(sudoNopasswd)
+	if result.SMB != nil {
+		model.SMB = types.BoolValue(*result.SMB)
 	}
-	if sudoCommands, ok := result["sudo_commands"].([]interface{}); ok && len(sudoCommands) > 0 {
-		commands := make([]string, len(sudoCommands))
-		for i, cmd := range sudoCommands {
-			commands[i] = cmd.(string)
-		}
-		cmdValues, diags := types.ListValueFrom(ctx, types.StringType, commands)
+	if len(result.SudoCommands) > 0 {
+		cmdValues, diags := types.ListValueFrom(ctx, types.StringType, result.SudoCommands)
 		if !diags.HasError() {
 			model.SudoCommands = cmdValues
 		}
 	}
-	if builtin, ok := result["builtin"].(bool); ok {
-		model.Builtin = types.BoolValue(builtin)
+	if result.Builtin != nil {
+		model.Builtin = types.BoolValue(*result.Builtin)
 	}
 
 	// The following fields are write-only on TrueNAS 25.10 — the API consumes
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,6 +17,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -54,6 +56,8 @@ type VMResourceModel struct {
 	CPUMode          types.String `tfsdk:"cpu_mode"`
 	CPUModel         types.String `tfsdk:"cpu_model"`
 	Status           types.String `tfsdk:"status"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *VMResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -172,6 +176,9 @@ func (r *VMResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -195,6 +202,14 @@ func (r *VMResource) Create(ctx context.Context, req resource.CreateRequest, res
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Creating VM", map[string]interface{}{
 		"name": plan.Name.ValueString(),
 	})
@@ -243,14 +258,14 @@ func (r *VMResource) Create(ctx context.Context, req resource.CreateRequest, res
 		createData["cpu_model"] = plan.CPUModel.ValueString()
 	}
 
-	var result map[string]interface{}
+	var result api.VMEntry
 	err := r.client.Create(ctx, "vm", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating VM", "Could not create VM: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Creating VM", "Could not create VM", err)
 		return
 	}
 
-	vmID := int64(result["id"].(float64))
+	vmID := result.ID
 	if err := r.readVM(ctx, vmID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading VM", "Could not read VM after creation: "+err.Error())
 		return
@@ -268,6 +283,14 @@ func (r *VMResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	if err := r.readVM(ctx, state.ID.ValueInt64(), &state); err != nil {
 		if client.IsNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
@@ -296,6 +319,14 @@ func (r *VMResource) Update(ctx context.Context, req resource.UpdateRequest, res
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	updateData := map[string]interface{}{}
 
 	if !plan.Description.Equal(state.Description) {
@@ -349,10 +380,10 @@ func (r *VMResource) Update(ctx context.Context, req resource.UpdateRequest, res
 	}
 
 	if len(updateData) > 0 {
-		var result map[string]interface{}
+		var result api.VMEntry
 		err := r.client.Update(ctx, "vm", state.ID.ValueInt64(), updateData, &result)
 		if err != nil {
-			resp.Diagnostics.AddError("Error Updating VM", "Could not update VM: "+err.Error())
+			addAPIError(&resp.Diagnostics, req.Plan, nil, "Error Updating VM", "Could not update VM", err)
 			return
 		}
 	}
@@ -374,6 +405,14 @@ func (r *VMResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 0)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Stop the VM first if running (ignore error - VM may already be stopped)
 	_ = r.client.Call(ctx, "vm.stop", []interface{}{state.ID.ValueInt64()}, nil)
 
@@ -397,73 +436,71 @@ func (r *VMResource) ImportState(ctx context.Context, req resource.ImportStateRe
 }
 
 func (r *VMResource) readVM(ctx context.Context, id int64, model *VMResourceModel) error {
-	var result map[string]interface{}
+	var result api.VMEntry
 	err := r.client.GetInstance(ctx, "vm", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.Name = types.StringValue(result["name"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.Name = types.StringValue(result.Name)
 
-	if description, ok := result["description"].(string); ok {
-		model.Description = types.StringValue(description)
+	if result.Description != nil {
+		model.Description = types.StringValue(*result.Description)
 	}
-	if vcpus, ok := result["vcpus"].(float64); ok {
-		model.VCPUs = types.Int64Value(int64(vcpus))
+	if result.Vcpus != nil {
+		model.VCPUs = types.Int64Value(*result.Vcpus)
 	}
-	if cores, ok := result["cores"].(float64); ok {
-		model.Cores = types.Int64Value(int64(cores))
+	if result.Cores != nil {
+		model.Cores = types.Int64Value(*result.Cores)
 	}
-	if threads, ok := result["threads"].(float64); ok {
-		model.Threads = types.Int64Value(int64(threads))
+	if result.Threads != nil {
+		model.Threads = types.Int64Value(*result.Threads)
 	}
-	if memory, ok := result["memory"].(float64); ok {
-		model.Memory = types.Int64Value(int64(memory))
+	if result.Memory != nil {
+		model.Memory = types.Int64Value(*result.Memory)
 	}
-	if minMemory, ok := result["min_memory"].(float64); ok {
-		model.MinMemory = types.Int64Value(int64(minMemory))
+	if result.MinMemory != nil {
+		model.MinMemory = types.Int64Value(*result.MinMemory)
 	}
-	if bootloader, ok := result["bootloader"].(string); ok {
-		model.Bootloader = types.StringValue(bootloader)
+	if result.Bootloader != nil {
+		model.Bootloader = types.StringValue(*result.Bootloader)
 	}
-	if bootloaderOVMF, ok := result["bootloader_ovmf"].(string); ok {
-		model.BootloaderOVMF = types.StringValue(bootloaderOVMF)
+	if result.BootloaderOVMF != nil {
+		model.BootloaderOVMF = types.StringValue(*result.BootloaderOVMF)
 	}
-	if autostart, ok := result["autostart"].(bool); ok {
-		model.Autostart = types.BoolValue(autostart)
+	if result.Autostart != nil {
+		model.Autostart = types.BoolValue(*result.Autostart)
 	}
-	if hideFromMSR, ok := result["hide_from_msr"].(bool); ok {
-		model.HideFromMSR = types.BoolValue(hideFromMSR)
+	if result.HideFromMSR != nil {
+		model.HideFromMSR = types.BoolValue(*result.HideFromMSR)
 	}
-	if ensureDisplayDevice, ok := result["ensure_display_device"].(bool); ok {
-		model.EnsureDisplayDevice = types.BoolValue(ensureDisplayDevice)
+	if result.EnsureDisplayDevice != nil {
+		model.EnsureDisplayDevice = types.BoolValue(*result.EnsureDisplayDevice)
 	}
-	if time, ok := result["time"].(string); ok {
-		model.Time = types.StringValue(time)
+	if result.Time != nil {
+		model.Time = types.StringValue(*result.Time)
 	}
-	if shutdownTimeout, ok := result["shutdown_timeout"].(float64); ok {
-		model.ShutdownTimeout = types.Int64Value(int64(shutdownTimeout))
+	if result.ShutdownTimeout != nil {
+		model.ShutdownTimeout = types.Int64Value(*result.ShutdownTimeout)
 	}
-	if archType, ok := result["arch_type"].(string); ok {
-		model.ArchType = types.StringValue(archType)
+	if result.ArchType != nil {
+		model.ArchType = types.StringValue(*result.ArchType)
 	}
-	if machineType, ok := result["machine_type"].(string); ok {
-		model.MachineType = types.StringValue(machineType)
+	if result.MachineType != nil {
+		model.MachineType = types.StringValue(*result.MachineType)
 	}
-	if uuid, ok := result["uuid"].(string); ok {
-		model.UUID = types.StringValue(uuid)
+	if result.UUID != nil {
+		model.UUID = types.StringValue(*result.UUID)
 	}
-	if cpuMode, ok := result["cpu_mode"].(string); ok {
-		model.CPUMode = types.StringValue(cpuMode)
+	if result.CPUMode != nil {
+		model.CPUMode = types.StringValue(*result.CPUMode)
 	}
-	if cpuModel, ok := result["cpu_model"].(string); ok {
-		model.CPUModel = types.StringValue(cpuModel)
+	if result.CPUModel != nil {
+		model.CPUModel = types.StringValue(*result.CPUModel)
 	}
-	if status, ok := result["status"].(map[string]interface{}); ok {
-		if state, ok := status["state"].(string); ok {
-			model.Status = types.StringValue(state)
-		}
+	if result.Status != nil {
+		model.Status = types.StringValue(result.Status.State)
 	}
 
 	return nil
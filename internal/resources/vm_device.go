@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/trueform/terraform-provider-trueform/internal/api"
 	"github.com/trueform/terraform-provider-trueform/internal/client"
 )
 
@@ -189,6 +190,9 @@ func (r *VMDeviceResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 	r.client = client
+
+	// Devices created concurrently race on their boot order
+	client.Serialize("vm.device")
 }
 
 func (r *VMDeviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -280,14 +284,14 @@ func (r *VMDeviceResource) Create(ctx context.Context, req resource.CreateReques
 
 	createData["attributes"] = attrs
 
-	var result map[string]interface{}
+	var result api.VMDeviceEntry
 	err := r.client.Create(ctx, "vm.device", createData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating VM Device", "Could not create VM device: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, vmDeviceFieldAliases(plan.DeviceType.ValueString()), "Error Creating VM Device", "Could not create VM device", err)
 		return
 	}
 
-	deviceID := int64(result["id"].(float64))
+	deviceID := result.ID
 	if err := r.readDevice(ctx, deviceID, &plan); err != nil {
 		resp.Diagnostics.AddError("Error Reading VM Device", "Could not read VM device after creation: "+err.Error())
 		return
@@ -371,10 +375,10 @@ func (r *VMDeviceResource) Update(ctx context.Context, req resource.UpdateReques
 		updateData["attributes"] = attrs
 	}
 
-	var result map[string]interface{}
+	var result api.VMDeviceEntry
 	err := r.client.Update(ctx, "vm.device", state.ID.ValueInt64(), updateData, &result)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Updating VM Device", "Could not update VM device: "+err.Error())
+		addAPIError(&resp.Diagnostics, req.Plan, vmDeviceFieldAliases(plan.DeviceType.ValueString()), "Error Updating VM Device", "Could not update VM device", err)
 		return
 	}
 
@@ -415,65 +419,116 @@ func (r *VMDeviceResource) ImportState(ctx context.Context, req resource.ImportS
 }
 
 func (r *VMDeviceResource) readDevice(ctx context.Context, id int64, model *VMDeviceResourceModel) error {
-	var result map[string]interface{}
+	var result api.VMDeviceEntry
 	err := r.client.GetInstance(ctx, "vm.device", id, &result)
 	if err != nil {
 		return err
 	}
 
-	model.ID = types.Int64Value(int64(result["id"].(float64)))
-	model.VM = types.Int64Value(int64(result["vm"].(float64)))
-	model.DeviceType = types.StringValue(result["dtype"].(string))
+	model.ID = types.Int64Value(result.ID)
+	model.VM = types.Int64Value(result.VM)
+
+	// TrueNAS 25.10 reports the device type inside attributes; older
+	// releases returned it at the top level
+	dtype := ""
+	if result.Attributes != nil && result.Attributes.Dtype != "" {
+		dtype = result.Attributes.Dtype
+	} else if result.Dtype != nil {
+		dtype = *result.Dtype
+	}
+	model.DeviceType = types.StringValue(dtype)
 
-	if order, ok := result["order"].(float64); ok {
-		model.Order = types.Int64Value(int64(order))
+	if result.Order != nil {
+		model.Order = types.Int64Value(*result.Order)
 	}
 
-	if attrs, ok := result["attributes"].(map[string]interface{}); ok {
-		switch model.DeviceType.ValueString() {
+	if attrs := result.Attributes; attrs != nil {
+		switch dtype {
 		case "DISK":
-			if path, ok := attrs["path"].(string); ok {
-				model.DiskPath = types.StringValue(path)
+			if attrs.Path != nil {
+				model.DiskPath = types.StringValue(*attrs.Path)
 			}
-			if dtype, ok := attrs["type"].(string); ok {
-				model.DiskType = types.StringValue(dtype)
+			if attrs.Type != nil {
+				model.DiskType = types.StringValue(*attrs.Type)
 			}
 		case "NIC":
-			if nicType, ok := attrs["type"].(string); ok {
-				model.NICType = types.StringValue(nicType)
+			if attrs.Type != nil {
+				model.NICType = types.StringValue(*attrs.Type)
 			}
-			if mac, ok := attrs["mac"].(string); ok {
-				model.NICMac = types.StringValue(mac)
+			if attrs.MAC != nil {
+				model.NICMac = types.StringValue(*attrs.MAC)
 			}
-			if attach, ok := attrs["nic_attach"].(string); ok {
-				model.NICAttach = types.StringValue(attach)
+			if attrs.NICAttach != nil {
+				model.NICAttach = types.StringValue(*attrs.NICAttach)
 			}
 		case "CDROM":
-			if path, ok := attrs["path"].(string); ok {
-				model.CDROMPath = types.StringValue(path)
+			if attrs.Path != nil {
+				model.CDROMPath = types.StringValue(*attrs.Path)
 			}
 		case "DISPLAY":
-			if displayType, ok := attrs["type"].(string); ok {
-				model.DisplayType = types.StringValue(displayType)
+			if attrs.Type != nil {
+				model.DisplayType = types.StringValue(*attrs.Type)
 			}
-			if port, ok := attrs["port"].(float64); ok {
-				model.DisplayPort = types.Int64Value(int64(port))
+			if attrs.Port != nil {
+				model.DisplayPort = types.Int64Value(*attrs.Port)
 			}
-			if bind, ok := attrs["bind"].(string); ok {
-				model.DisplayBind = types.StringValue(bind)
+			if attrs.Bind != nil {
+				model.DisplayBind = types.StringValue(*attrs.Bind)
 			}
-			if web, ok := attrs["web"].(bool); ok {
-				model.DisplayWeb = types.BoolValue(web)
+			if attrs.Web != nil {
+				model.DisplayWeb = types.BoolValue(*attrs.Web)
 			}
-			if resolution, ok := attrs["resolution"].(string); ok {
-				model.DisplayResolution = types.StringValue(resolution)
+			if attrs.Resolution != nil {
+				model.DisplayResolution = types.StringValue(*attrs.Resolution)
 			}
 		case "PCI":
-			if pptdev, ok := attrs["pptdev"].(string); ok {
-				model.PCIDevice = types.StringValue(pptdev)
+			if attrs.Pptdev != nil {
+				model.PCIDevice = types.StringValue(*attrs.Pptdev)
 			}
 		}
 	}
 
 	return nil
 }
+
+// vmDeviceFieldAliases maps the device's attributes.* fields to the schema
+// attributes they are set from for the given device type
+func vmDeviceFieldAliases(dtype string) fieldAliases {
+	switch dtype {
+	case "DISK":
+		return fieldAliases{
+			"attributes.path":                "disk_path",
+			"attributes.type":                "disk_type",
+			"attributes.physical_sectorsize": "disk_sector_size",
+			"attributes.logical_sectorsize":  "disk_sector_size",
+		}
+	case "NIC":
+		return fieldAliases{
+			"attributes.type":                   "nic_type",
+			"attributes.mac":                    "nic_mac",
+			"attributes.nic_attach":             "nic_attach",
+			"attributes.trust_guest_rx_filters": "trust_guest_rx_filters",
+		}
+	case "CDROM":
+		return fieldAliases{"attributes.path": "cdrom_path"}
+	case "DISPLAY":
+		return fieldAliases{
+			"attributes.type":       "display_type",
+			"attributes.port":       "display_port",
+			"attributes.bind":       "display_bind",
+			"attributes.password":   "display_password",
+			"attributes.web":        "display_web",
+			"attributes.resolution": "display_resolution",
+		}
+	case "PCI":
+		return fieldAliases{"attributes.pptdev": "pci_device"}
+	case "USB":
+		return fieldAliases{"attributes.device": "usb_device"}
+	case "RAW":
+		return fieldAliases{
+			"attributes.path": "raw_path",
+			"attributes.size": "raw_size",
+		}
+	}
+	return nil
+}
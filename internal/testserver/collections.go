@@ -0,0 +1,854 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// poolSize is the capacity reported for every pool and dataset
+const poolSize = 1 << 40
+
+// registerNamespaces sets up the collections the provider manages and the
+// methods that act on them beyond plain CRUD
+func (s *Server) registerNamespaces() {
+	s.addNamespace(&namespace{name: "disk", key: func(rec map[string]interface{}) interface{} { return rec["name"] }})
+	for _, name := range []string{"sdb", "sdc", "sdd", "sde"} {
+		s.namespaces["disk"].records[name] = map[string]interface{}{
+			"id": name, "name": name, "identifier": "{serial}" + strings.ToUpper(name), "size": float64(poolSize),
+		}
+	}
+
+	s.addNamespace(&namespace{
+		name:     "pool",
+		jobs:     true,
+		required: []string{"name", "topology"},
+		unique:   []string{"name"},
+		defaults: map[string]interface{}{
+			"status":    "ONLINE",
+			"healthy":   true,
+			"size":      poolSize,
+			"allocated": 0,
+			"free":      poolSize,
+			"encrypt":   0,
+		},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			rec["path"] = "/mnt/" + fmt.Sprint(rec["name"])
+			delete(rec, "allow_duplicate_serials")
+			return nil
+		},
+		view: poolView,
+		afterCreate: func(s *Server, rec map[string]interface{}) {
+			name := rec["name"].(string)
+			s.namespaces["pool.dataset"].records[name] = newDataset(name, map[string]interface{}{})
+		},
+	})
+	s.methods["pool.export"] = func(params []json.RawMessage) (interface{}, error) {
+		return s.RunJob("pool.export", params, func() (interface{}, error) {
+			var id interface{}
+			if err := decodeParam(params, 0, &id); err != nil {
+				return nil, err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			pool, ok := s.namespaces["pool"].records[idKey(id)]
+			if !ok {
+				return nil, NotFound("pool %v does not exist", id)
+			}
+			delete(s.namespaces["pool"].records, idKey(id))
+			s.removeDatasets(pool["name"].(string))
+			return nil, nil
+		}), nil
+	}
+	s.methods["pool.scrub"] = func(params []json.RawMessage) (interface{}, error) {
+		var id interface{}
+		var action string
+		if err := decodeParam(params, 0, &id); err != nil {
+			return nil, err
+		}
+		if err := decodeParam(params, 1, &action); err != nil {
+			return nil, err
+		}
+		if action != "START" && action != "STOP" && action != "PAUSE" {
+			return nil, ValidationError("pool.scrub.action", fmt.Sprintf("Invalid choice: %s", action))
+		}
+		s.mu.Lock()
+		_, ok := s.namespaces["pool"].records[idKey(id)]
+		s.mu.Unlock()
+		if !ok {
+			return nil, NotFound("pool %v does not exist", id)
+		}
+		// Scrubs of the test server's empty pools finish at once
+		return s.RunJob("pool.scrub", params, func() (interface{}, error) { return nil, nil }), nil
+	}
+
+	s.addNamespace(&namespace{
+		name:     "pool.dataset",
+		key:      func(rec map[string]interface{}) interface{} { return rec["name"] },
+		required: []string{"name"},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			name, _ := rec["name"].(string)
+			if op == "create" {
+				parent, _, found := cutLast(name, "/")
+				if !found {
+					return ValidationError("pool_dataset_create.name", "Dataset name must include the pool name")
+				}
+				if _, ok := s.namespaces["pool.dataset"].records[parent]; !ok {
+					return ValidationError("pool_dataset_create.name", fmt.Sprintf("Parent dataset %s does not exist", parent))
+				}
+				for k, v := range newDataset(name, rec) {
+					rec[k] = v
+				}
+			}
+			return nil
+		},
+		view: datasetView,
+	})
+	s.methods["pool.dataset.delete"] = func(params []json.RawMessage) (interface{}, error) {
+		var id string
+		var opts struct {
+			Recursive bool `json:"recursive"`
+		}
+		if err := decodeParam(params, 0, &id); err != nil {
+			return nil, err
+		}
+		if len(params) > 1 {
+			_ = json.Unmarshal(params[1], &opts)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.namespaces["pool.dataset"].records[id]; !ok {
+			return nil, NotFound("pool.dataset %s does not exist", id)
+		}
+		if !opts.Recursive {
+			for name := range s.namespaces["pool.dataset"].records {
+				if strings.HasPrefix(name, id+"/") {
+					return nil, CallError(16, "EBUSY", fmt.Sprintf("Failed to delete dataset: cannot destroy '%s': filesystem has children", id))
+				}
+			}
+		}
+		s.removeDatasets(id)
+		return true, nil
+	}
+
+	s.addNamespace(&namespace{
+		name: "zfs.snapshot",
+		// prepare turns name into the full dataset@name snapshot ID
+		key:      func(rec map[string]interface{}) interface{} { return rec["name"] },
+		required: []string{"dataset", "name"},
+		defaults: map[string]interface{}{"holds": []interface{}{}},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			if op == "create" {
+				dataset, _ := rec["dataset"].(string)
+				if _, ok := s.namespaces["pool.dataset"].records[dataset]; !ok {
+					return ValidationError("zfs_snapshot_create.dataset", fmt.Sprintf("Dataset %s does not exist", dataset))
+				}
+				rec["snapshot_name"] = rec["name"]
+				rec["name"] = fmt.Sprintf("%s@%v", dataset, rec["name"])
+				rec["pool"], _, _ = strings.Cut(dataset, "/")
+				rec["user_properties"] = rec["properties"]
+				rec["created"] = float64(time.Now().Unix())
+				delete(rec, "properties")
+				delete(rec, "recursive")
+				delete(rec, "vmware_sync")
+			}
+			if update, ok := rec["user_properties_update"].(map[string]interface{}); ok {
+				props, _ := rec["user_properties"].(map[string]interface{})
+				if props == nil {
+					props = map[string]interface{}{}
+				}
+				for k, v := range update {
+					props[k] = v
+				}
+				rec["user_properties"] = props
+				delete(rec, "user_properties_update")
+			}
+			return nil
+		},
+		view: snapshotView,
+	})
+	s.methods["zfs.snapshot.rollback"] = func(params []json.RawMessage) (interface{}, error) {
+		var id string
+		if err := decodeParam(params, 0, &id); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.namespaces["zfs.snapshot"].records[id]; !ok {
+			return nil, NotFound("snapshot %s does not exist", id)
+		}
+		return nil, nil
+	}
+
+	s.addNamespace(&namespace{
+		name:     "sharing.smb",
+		required: []string{"path", "name"},
+		unique:   []string{"name"},
+		defaults: map[string]interface{}{
+			"path_suffix":   "",
+			"comment":       "",
+			"enabled":       true,
+			"purpose":       "DEFAULT_SHARE",
+			"home":          false,
+			"timemachine":   false,
+			"ro":            false,
+			"browsable":     true,
+			"recyclebin":    false,
+			"guestok":       false,
+			"abe":           false,
+			"hostsallow":    []interface{}{},
+			"hostsdeny":     []interface{}{},
+			"acl":           true,
+			"durablehandle": true,
+			"shadowcopy":    true,
+			"streams":       true,
+			"fsrvp":         false,
+			"audit":         map[string]interface{}{"enable": false, "watch_list": []interface{}{}, "ignore_list": []interface{}{}},
+			"locked":        false,
+		},
+		input:   smbShareInput,
+		view:    s.smbShareView,
+		prepare: requirePath("sharing_smb"),
+	})
+
+	s.addNamespace(&namespace{
+		name:     "sharing.nfs",
+		required: []string{"path"},
+		unique:   []string{"path"},
+		defaults: map[string]interface{}{
+			"aliases":       []interface{}{},
+			"comment":       "",
+			"enabled":       true,
+			"networks":      []interface{}{},
+			"hosts":         []interface{}{},
+			"ro":            false,
+			"maproot_user":  "",
+			"maproot_group": "",
+			"mapall_user":   "",
+			"mapall_group":  "",
+			"security":      []interface{}{},
+			"locked":        false,
+		},
+		prepare: requirePath("sharing_nfs"),
+	})
+
+	s.addNamespace(&namespace{name: "group", unique: []string{"gid", "group"}})
+	s.addNamespace(&namespace{
+		name:     "user",
+		required: []string{"username"},
+		unique:   []string{"username", "uid"},
+		defaults: map[string]interface{}{
+			"full_name":              "",
+			"email":                  nil,
+			"home":                   "/var/empty",
+			"shell":                  "/usr/bin/zsh",
+			"password_disabled":      false,
+			"locked":                 false,
+			"smb":                    true,
+			"sudo_commands":          []interface{}{},
+			"sudo_commands_nopasswd": []interface{}{},
+			"groups":                 []interface{}{},
+			"sshpubkey":              nil,
+			"builtin":                false,
+		},
+		prepare: prepareUser,
+	})
+
+	s.addNamespace(&namespace{
+		name:     "vm",
+		required: []string{"name", "memory"},
+		unique:   []string{"name"},
+		defaults: map[string]interface{}{
+			"description":           "",
+			"vcpus":                 1,
+			"cores":                 1,
+			"threads":               1,
+			"min_memory":            nil,
+			"bootloader":            "UEFI",
+			"bootloader_ovmf":       "OVMF_CODE.fd",
+			"autostart":             true,
+			"hide_from_msr":         false,
+			"ensure_display_device": true,
+			"time":                  "LOCAL",
+			"shutdown_timeout":      90,
+			"arch_type":             nil,
+			"machine_type":          nil,
+			"cpu_mode":              "CUSTOM",
+			"cpu_model":             nil,
+			"devices":               []interface{}{},
+			"status":                map[string]interface{}{"state": "STOPPED", "pid": nil, "domain_state": "SHUTOFF"},
+		},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			if op == "create" {
+				rec["uuid"] = fmt.Sprintf("00000000-0000-4000-8000-%012d", len(s.namespaces["vm"].records)+1)
+			}
+			return nil
+		},
+		afterDelete: func(s *Server, rec map[string]interface{}) {
+			devices := s.namespaces["vm.device"]
+			for key, dev := range devices.records {
+				if equal(dev["vm"], rec["id"]) {
+					delete(devices.records, key)
+				}
+			}
+		},
+	})
+	for _, action := range []string{"start", "stop", "restart", "poweroff"} {
+		state := "RUNNING"
+		if action == "stop" || action == "poweroff" {
+			state = "STOPPED"
+		}
+		s.methods["vm."+action] = s.vmPower("vm."+action, state)
+	}
+
+	s.addNamespace(&namespace{
+		name:     "vm.device",
+		required: []string{"vm", "dtype"},
+		defaults: map[string]interface{}{"order": 1000, "attributes": map[string]interface{}{}},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			if _, ok := s.namespaces["vm"].records[idKey(rec["vm"])]; !ok {
+				return ValidationError("vm_device_"+op+".vm", fmt.Sprintf("VM %v does not exist", rec["vm"]))
+			}
+			if attrs, ok := rec["attributes"].(map[string]interface{}); ok {
+				attrs["dtype"] = rec["dtype"]
+			}
+			return nil
+		},
+	})
+
+	s.addNamespace(&namespace{
+		name:     "app",
+		jobs:     true,
+		key:      func(rec map[string]interface{}) interface{} { return rec["name"] },
+		required: []string{"app_name", "catalog_app"},
+		prepare:  prepareApp,
+	})
+	s.methods["app.upgrade"] = s.appAction("app.upgrade", func(app map[string]interface{}, opts map[string]interface{}) {
+		if version, ok := opts["app_version"].(string); ok {
+			app["version"] = version
+			app["upgrade_available"] = false
+		}
+	})
+	s.methods["app.start"] = s.appAction("app.start", func(app, _ map[string]interface{}) { app["state"] = "RUNNING" })
+	s.methods["app.stop"] = s.appAction("app.stop", func(app, _ map[string]interface{}) { app["state"] = "STOPPED" })
+	s.methods["app.redeploy"] = s.appAction("app.redeploy", func(app, _ map[string]interface{}) { app["state"] = "RUNNING" })
+
+	s.addNamespace(&namespace{
+		name:     "cronjob",
+		required: []string{"user", "command"},
+		defaults: map[string]interface{}{
+			"description": "",
+			"enabled":     true,
+			"stdout":      true,
+			"stderr":      false,
+			"schedule":    map[string]interface{}{"minute": "00", "hour": "*", "dom": "*", "month": "*", "dow": "*"},
+		},
+	})
+	s.methods["cronjob.run"] = func(params []json.RawMessage) (interface{}, error) {
+		var id interface{}
+		if err := decodeParam(params, 0, &id); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		_, ok := s.namespaces["cronjob"].records[idKey(id)]
+		s.mu.Unlock()
+		if !ok {
+			return nil, NotFound("cronjob %v does not exist", id)
+		}
+		return s.RunJob("cronjob.run", params, func() (interface{}, error) { return nil, nil }), nil
+	}
+
+	s.addNamespace(&namespace{
+		name:     "iscsi.portal",
+		required: []string{"listen"},
+		defaults: map[string]interface{}{"comment": "", "discovery_authmethod": "NONE", "discovery_authgroup": nil},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			if listen, ok := rec["listen"].([]interface{}); ok {
+				for _, l := range listen {
+					if entry, ok := l.(map[string]interface{}); ok {
+						if _, ok := entry["port"]; !ok {
+							entry["port"] = float64(3260)
+						}
+					}
+				}
+			}
+			return nil
+		},
+	})
+	s.addNamespace(&namespace{
+		name:     "iscsi.target",
+		required: []string{"name"},
+		unique:   []string{"name"},
+		defaults: map[string]interface{}{"alias": nil, "mode": "ISCSI", "groups": []interface{}{}, "auth_networks": []interface{}{}},
+	})
+	s.addNamespace(&namespace{
+		name:     "iscsi.extent",
+		required: []string{"name", "type"},
+		unique:   []string{"name"},
+		defaults: map[string]interface{}{
+			"disk":            nil,
+			"path":            nil,
+			"filesize":        "0",
+			"blocksize":       512,
+			"pblocksize":      false,
+			"avail_threshold": nil,
+			"comment":         "",
+			"insecure_tpc":    true,
+			"xen":             false,
+			"rpm":             "SSD",
+			"ro":              false,
+			"enabled":         true,
+			"locked":          false,
+		},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			if op == "create" {
+				n := len(s.namespaces["iscsi.extent"].records) + 1
+				rec["serial"] = fmt.Sprintf("%015x", n)
+				rec["naa"] = fmt.Sprintf("0x6589cfc000000%019x", n)
+			}
+			return nil
+		},
+	})
+	s.addNamespace(&namespace{
+		name:     "iscsi.initiator",
+		defaults: map[string]interface{}{"comment": "", "initiators": []interface{}{}, "auth_network": []interface{}{}},
+	})
+	s.addNamespace(&namespace{
+		name:     "iscsi.targetextent",
+		required: []string{"target", "extent"},
+		defaults: map[string]interface{}{"lunid": 0},
+		prepare: func(s *Server, rec map[string]interface{}, op string) *Error {
+			if _, ok := s.namespaces["iscsi.target"].records[idKey(rec["target"])]; !ok {
+				return ValidationError("iscsi_targetextent_"+op+".target", fmt.Sprintf("Target %v does not exist", rec["target"]))
+			}
+			if _, ok := s.namespaces["iscsi.extent"].records[idKey(rec["extent"])]; !ok {
+				return ValidationError("iscsi_targetextent_"+op+".extent", fmt.Sprintf("Extent %v does not exist", rec["extent"]))
+			}
+			return nil
+		},
+	})
+
+	s.addNamespace(&namespace{
+		name:     "certificate",
+		jobs:     true,
+		required: []string{"name", "create_type"},
+		unique:   []string{"name"},
+		defaults: map[string]interface{}{
+			"key_length":       2048,
+			"key_type":         "RSA",
+			"digest_algorithm": "SHA256",
+			"lifetime":         3650,
+			"san":              []interface{}{},
+		},
+		prepare: prepareCertificate,
+	})
+
+	s.addNamespace(&namespace{
+		name:     "staticroute",
+		required: []string{"destination", "gateway"},
+		defaults: map[string]interface{}{"description": ""},
+	})
+}
+
+// requirePath checks that a share's path is inside an existing dataset
+func requirePath(schema string) func(*Server, map[string]interface{}, string) *Error {
+	return func(s *Server, rec map[string]interface{}, op string) *Error {
+		path, _ := rec["path"].(string)
+		dataset := strings.TrimPrefix(path, "/mnt/")
+		if !strings.HasPrefix(path, "/mnt/") {
+			return ValidationError(schema+"_"+op+".path", "Path must be within a pool mount point")
+		}
+		if _, ok := s.namespaces["pool.dataset"].records[dataset]; !ok {
+			return ValidationError(schema+"_"+op+".path", "Path does not exist")
+		}
+		return nil
+	}
+}
+
+// smbShareFields are the fields sharing.smb.create and sharing.smb.update
+// accept before 25.10
+var smbShareFields = []string{
+	"purpose", "path", "path_suffix", "home", "name", "comment", "ro", "browsable", "timemachine",
+	"timemachine_quota", "recyclebin", "guestok", "abe", "hostsallow", "hostsdeny", "auxsmbconf",
+	"aapl_name_mangling", "acl", "durablehandle", "shadowcopy", "streams", "fsrvp", "enabled", "afp", "audit",
+}
+
+// smbShareOptionsFields are the fields they accept from 25.10 on. The rest
+// of the settings go under options, which takes any key.
+var smbShareOptionsFields = []string{
+	"purpose", "name", "path", "enabled", "comment", "readonly", "browsable",
+	"access_based_share_enumeration", "audit", "options",
+}
+
+// smbShareInput rejects the fields the running release doesn't accept, as
+// the schemas in internal/api/schema do, and stores a 25.10 payload in the
+// pre-25.10 shape
+func smbShareInput(s *Server, data map[string]interface{}, op string) *Error {
+	options := releaseAtLeast(s.version, 25, 10)
+	accepted := smbShareFields
+	if options {
+		accepted = smbShareOptionsFields
+	}
+	for key := range data {
+		if !slices.Contains(accepted, key) {
+			return ValidationError("sharing_smb_"+op+"."+key, "Extra inputs are not permitted")
+		}
+	}
+	if !options {
+		return nil
+	}
+
+	for from, to := range map[string]string{"readonly": "ro", "access_based_share_enumeration": "abe"} {
+		if value, ok := data[from]; ok {
+			data[to] = value
+			delete(data, from)
+		}
+	}
+	if opts, ok := data["options"].(map[string]interface{}); ok {
+		for key, value := range opts {
+			data[key] = value
+		}
+	}
+	delete(data, "options")
+	return nil
+}
+
+// smbShareView renders an SMB share the way the running release returns it.
+// Shares are stored in the pre-25.10 shape. 25.10 renamed ro and abe, moved
+// the host lists, path_suffix and auxsmbconf under options and stopped
+// returning the other share flags. It runs with the server lock held.
+func (s *Server) smbShareView(rec map[string]interface{}) map[string]interface{} {
+	if !releaseAtLeast(s.version, 25, 10) {
+		return rec
+	}
+	rec["readonly"] = rec["ro"]
+	rec["access_based_share_enumeration"] = rec["abe"]
+	options := map[string]interface{}{}
+	for _, key := range []string{"hostsallow", "hostsdeny", "path_suffix", "auxsmbconf"} {
+		if value, ok := rec[key]; ok {
+			options[key] = value
+		}
+	}
+	rec["options"] = options
+	for _, key := range []string{
+		"ro", "abe", "hostsallow", "hostsdeny", "path_suffix", "auxsmbconf",
+		"home", "timemachine", "recyclebin", "guestok", "acl", "durablehandle", "shadowcopy", "streams", "fsrvp",
+	} {
+		delete(rec, key)
+	}
+	return rec
+}
+
+// removeDatasets deletes a dataset with its children and snapshots
+func (s *Server) removeDatasets(name string) {
+	datasets := s.namespaces["pool.dataset"].records
+	for key := range datasets {
+		if key == name || strings.HasPrefix(key, name+"/") {
+			delete(datasets, key)
+		}
+	}
+	snapshots := s.namespaces["zfs.snapshot"].records
+	for key := range snapshots {
+		if strings.HasPrefix(key, name+"@") || strings.HasPrefix(key, name+"/") {
+			delete(snapshots, key)
+		}
+	}
+}
+
+// poolView expands the topology given to pool.create into the vdev tree
+// pool.query returns
+func poolView(rec map[string]interface{}) map[string]interface{} {
+	topology, _ := rec["topology"].(map[string]interface{})
+	out := map[string]interface{}{}
+	for _, category := range []string{"data", "log", "cache", "spare", "special", "dedup"} {
+		vdevs, _ := topology[category].([]interface{})
+		expanded := []interface{}{}
+		for _, v := range vdevs {
+			vdev, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := vdev["children"]; ok {
+				expanded = append(expanded, vdev)
+				continue
+			}
+			disks, _ := vdev["disks"].([]interface{})
+			children := make([]interface{}, 0, len(disks))
+			for _, d := range disks {
+				children = append(children, map[string]interface{}{"type": "DISK", "disk": d, "status": "ONLINE"})
+			}
+			expanded = append(expanded, map[string]interface{}{
+				"type":     vdev["type"],
+				"status":   "ONLINE",
+				"children": children,
+			})
+		}
+		out[category] = expanded
+	}
+	rec["topology"] = out
+	return rec
+}
+
+// newDataset returns the stored form of a dataset with defaults for the
+// properties the caller didn't set
+func newDataset(name string, data map[string]interface{}) map[string]interface{} {
+	rec := map[string]interface{}{
+		"type":            "FILESYSTEM",
+		"compression":     "LZ4",
+		"atime":           "OFF",
+		"deduplication":   "OFF",
+		"copies":          float64(1),
+		"snapdir":         "HIDDEN",
+		"readonly":        "OFF",
+		"recordsize":      "128K",
+		"casesensitivity": "SENSITIVE",
+		"aclmode":         "DISCARD",
+		"acltype":         "POSIX",
+		"quota":           float64(0),
+		"refquota":        float64(0),
+		"reservation":     float64(0),
+		"refreservation":  float64(0),
+	}
+	for k, v := range data {
+		rec[k] = v
+	}
+	rec["id"] = name
+	rec["name"] = name
+	rec["pool"], _, _ = strings.Cut(name, "/")
+	rec["mountpoint"] = "/mnt/" + name
+	rec["encrypted"] = false
+	rec["encryption_root"] = nil
+	rec["key_loaded"] = false
+	return rec
+}
+
+// datasetSizeProperties are reported as byte counts; the rest as strings
+var datasetSizeProperties = []string{"quota", "refquota", "reservation", "refreservation", "quota_warning", "quota_critical"}
+
+// datasetView wraps dataset properties in the {value, rawvalue, parsed,
+// source} objects pool.dataset.query returns
+func datasetView(rec map[string]interface{}) map[string]interface{} {
+	for _, prop := range []string{"compression", "atime", "deduplication", "snapdir", "readonly", "recordsize", "casesensitivity", "aclmode", "acltype", "managedby", "share_type"} {
+		if v, ok := rec[prop].(string); ok {
+			rec[prop] = property(v, strings.ToLower(v), v)
+		}
+	}
+	for _, prop := range datasetSizeProperties {
+		if v, ok := rec[prop].(float64); ok {
+			raw := strconv.FormatInt(int64(v), 10)
+			if v == 0 {
+				// TrueNAS reports unset quotas and reservations as null
+				rec[prop] = map[string]interface{}{"value": nil, "rawvalue": raw, "parsed": nil, "source": "DEFAULT"}
+			} else {
+				rec[prop] = property(raw, raw, v)
+			}
+		}
+	}
+	if copies, ok := rec["copies"].(float64); ok {
+		raw := strconv.FormatInt(int64(copies), 10)
+		rec["copies"] = property(raw, raw, copies)
+	}
+	if comments, ok := rec["comments"].(string); ok {
+		rec["comments"] = property(comments, comments, comments)
+		rec["user_properties"] = map[string]interface{}{"comments": property(comments, comments, comments)}
+	}
+	rec["used"] = property("0B", "0", float64(0))
+	rec["available"] = property("1T", strconv.FormatInt(poolSize, 10), float64(poolSize))
+	rec["children"] = []interface{}{}
+	return rec
+}
+
+// snapshotView adds the ZFS properties zfs.snapshot.query returns
+func snapshotView(rec map[string]interface{}) map[string]interface{} {
+	created, _ := rec["created"].(float64)
+	createdAt := time.Unix(int64(created), 0).UTC()
+	properties := map[string]interface{}{
+		"referenced": property("0B", "0", float64(0)),
+		"used":       property("0B", "0", float64(0)),
+		"creation":   property(createdAt.Format("Mon Jan _2 15:04 2006"), strconv.FormatInt(int64(created), 10), createdAt.Format(time.RFC3339)),
+	}
+	if userProps, ok := rec["user_properties"].(map[string]interface{}); ok {
+		for k, v := range userProps {
+			str := fmt.Sprint(v)
+			properties[k] = property(str, str, str)
+		}
+	}
+	rec["properties"] = properties
+	delete(rec, "created")
+	return rec
+}
+
+func property(value, rawvalue string, parsed interface{}) map[string]interface{} {
+	return map[string]interface{}{"value": value, "rawvalue": rawvalue, "parsed": parsed, "source": "LOCAL"}
+}
+
+// prepareUser assigns the UID and primary group and drops the write-only
+// fields user.create accepts
+func prepareUser(s *Server, rec map[string]interface{}, op string) *Error {
+	if op == "create" {
+		if _, ok := rec["uid"]; !ok {
+			uid := float64(3000)
+			for _, u := range s.namespaces["user"].records {
+				if existing, ok := u["uid"].(float64); ok && existing >= uid {
+					uid = existing + 1
+				}
+			}
+			rec["uid"] = uid
+		}
+
+		groups := s.namespaces["group"]
+		if create, _ := rec["group_create"].(bool); create {
+			id := groups.newID(nil)
+			groups.records[idKey(id)] = map[string]interface{}{"id": id, "gid": rec["uid"], "group": rec["username"]}
+			rec["group"] = id
+		}
+		if _, ok := rec["group"]; !ok {
+			return ValidationError("user_create.group", "Enter either a group name or create a new group to continue")
+		}
+	}
+
+	if gid, ok := rec["group"].(float64); ok {
+		group, ok := s.namespaces["group"].records[idKey(gid)]
+		if !ok {
+			return ValidationError("user_"+op+".group", fmt.Sprintf("Group %v does not exist", gid))
+		}
+		rec["group"] = map[string]interface{}{"id": gid, "bsdgrp_gid": group["gid"], "bsdgrp_group": group["group"]}
+	}
+	for _, field := range []string{"password", "group_create", "home_create", "home_mode"} {
+		delete(rec, field)
+	}
+	return nil
+}
+
+// prepareApp turns app.create arguments into the installed app record
+func prepareApp(s *Server, rec map[string]interface{}, op string) *Error {
+	if op != "create" {
+		delete(rec, "values")
+		return nil
+	}
+	version, _ := rec["version"].(string)
+	if version == "" || version == "latest" {
+		version = "1.0.0"
+	}
+	train, _ := rec["train"].(string)
+	if train == "" {
+		train = "stable"
+	}
+	rec["name"] = rec["app_name"]
+	rec["state"] = "RUNNING"
+	rec["version"] = version
+	rec["upgrade_available"] = false
+	rec["metadata"] = map[string]interface{}{
+		"name":        rec["catalog_app"],
+		"train":       train,
+		"app_version": version,
+	}
+	for _, field := range []string{"app_name", "catalog_app", "train", "values"} {
+		delete(rec, field)
+	}
+	return nil
+}
+
+// prepareCertificate fills in what TrueNAS derives when it imports or
+// creates a certificate
+func prepareCertificate(s *Server, rec map[string]interface{}, op string) *Error {
+	if op != "create" {
+		return nil
+	}
+	createType, _ := rec["create_type"].(string)
+	switch createType {
+	case "CERTIFICATE_CREATE_IMPORTED":
+		if cert, _ := rec["certificate"].(string); cert == "" {
+			return ValidationError("certificate_create.certificate", "This field is required")
+		}
+		rec["type"] = float64(8)
+	case "CERTIFICATE_CREATE_CSR":
+		rec["type"] = float64(32)
+		rec["CSR"] = "-----BEGIN CERTIFICATE REQUEST-----\n-----END CERTIFICATE REQUEST-----\n"
+	default:
+		rec["type"] = float64(16)
+	}
+	if name, ok := rec["common_name"]; ok {
+		rec["common"] = name
+		delete(rec, "common_name")
+	}
+	now := time.Now().UTC()
+	lifetime, _ := rec["lifetime"].(float64)
+	rec["from"] = now.Format(time.ANSIC)
+	rec["until"] = now.AddDate(0, 0, int(lifetime)).Format(time.ANSIC)
+	rec["fingerprint"] = fmt.Sprintf("AA:BB:CC:%02X", len(s.namespaces["certificate"].records)+1)
+	// Serials are random and commonly 128 bits, beyond any int64
+	serial := new(big.Int).Lsh(big.NewInt(1), 127)
+	serial.Add(serial, big.NewInt(int64(len(s.namespaces["certificate"].records)+1)))
+	rec["serial"] = json.Number(serial.String())
+	delete(rec, "create_type")
+	delete(rec, "privatekey")
+	return nil
+}
+
+// vmPower returns a handler for a VM power action that leaves the VM in state
+func (s *Server) vmPower(method, state string) Handler {
+	return func(params []json.RawMessage) (interface{}, error) {
+		var id interface{}
+		if err := decodeParam(params, 0, &id); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		vm, ok := s.namespaces["vm"].records[idKey(id)]
+		if !ok {
+			s.mu.Unlock()
+			return nil, NotFound("vm %v does not exist", id)
+		}
+		vm["status"] = map[string]interface{}{"state": state, "pid": nil, "domain_state": state}
+		s.mu.Unlock()
+
+		if method == "vm.start" {
+			return nil, nil
+		}
+		return s.RunJob(method, params, func() (interface{}, error) { return nil, nil }), nil
+	}
+}
+
+// appAction returns a handler for a job that changes an installed app
+func (s *Server) appAction(method string, apply func(app, opts map[string]interface{})) Handler {
+	return func(params []json.RawMessage) (interface{}, error) {
+		var name string
+		if err := decodeParam(params, 0, &name); err != nil {
+			return nil, err
+		}
+		opts := map[string]interface{}{}
+		if len(params) > 1 {
+			_ = json.Unmarshal(params[1], &opts)
+		}
+		s.mu.Lock()
+		_, ok := s.namespaces["app"].records[name]
+		s.mu.Unlock()
+		if !ok {
+			return nil, NotFound("app %s does not exist", name)
+		}
+
+		return s.RunJob(method, params, func() (interface{}, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			app, ok := s.namespaces["app"].records[name]
+			if !ok {
+				return nil, NotFound("app %s does not exist", name)
+			}
+			apply(app, opts)
+			return nil, nil
+		}), nil
+	}
+}
+
+// cutLast splits s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,80 @@
+package testserver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// JSON-RPC error codes used by TrueNAS
+const (
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeCallError      = -32001
+)
+
+// Error is a JSON-RPC error returned from a Handler
+type Error struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// NotFound returns the error TrueNAS sends when an instance doesn't exist
+func NotFound(format string, args ...interface{}) *Error {
+	reason := fmt.Sprintf(format, args...)
+	return &Error{
+		Code:    CodeCallError,
+		Message: "Method call error",
+		Data:    errnoData(2, "ENOENT", "[ENOENT] None: "+reason, nil),
+	}
+}
+
+// ValidationError returns the error TrueNAS sends when it rejects a payload.
+// attribute is the schema-prefixed field name, e.g. "sharing_smb_create.path".
+func ValidationError(attribute, message string) *Error {
+	return &Error{
+		Code:    CodeInvalidParams,
+		Message: "Invalid params",
+		Data: errnoData(22, "EINVAL", fmt.Sprintf("[EINVAL] %s: %s", attribute, message), []interface{}{
+			[]interface{}{attribute, message, 22},
+		}),
+	}
+}
+
+// CallError returns a generic method call error, e.g. CallError(16,
+// "EBUSY", "pool is busy")
+func CallError(errno int, errname, reason string) *Error {
+	return &Error{
+		Code:    CodeCallError,
+		Message: "Method call error",
+		Data:    errnoData(errno, errname, fmt.Sprintf("[%s] %s", errname, reason), nil),
+	}
+}
+
+// errnoData builds the data member TrueNAS attaches to call errors
+func errnoData(errno int, errname, reason string, extra interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"error":   errno,
+		"errname": errname,
+		"reason":  reason,
+		"trace":   nil,
+		"extra":   extra,
+	}
+}
+
+// toRPCError converts a handler error to its wire form
+func toRPCError(err error) *rpcError {
+	var e *Error
+	if errors.As(err, &e) {
+		return &rpcError{Code: e.Code, Message: e.Message, Data: e.Data}
+	}
+	return &rpcError{
+		Code:    CodeCallError,
+		Message: "Method call error",
+		Data:    errnoData(14, "EFAULT", "[EFAULT] "+err.Error(), nil),
+	}
+}
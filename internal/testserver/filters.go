@@ -0,0 +1,296 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// queryOptions are the query-options TrueNAS accepts as the second argument
+// of a .query call
+type queryOptions struct {
+	Limit   int      `json:"limit"`
+	Offset  int      `json:"offset"`
+	Count   bool     `json:"count"`
+	Get     bool     `json:"get"`
+	Select  []string `json:"select"`
+	OrderBy []string `json:"order_by"`
+}
+
+// runQuery applies query-filters and query-options to records, in the way
+// TrueNAS' filter_list does
+func runQuery(records []map[string]interface{}, params []json.RawMessage) (interface{}, error) {
+	var filters []interface{}
+	if len(params) > 0 && string(params[0]) != "null" {
+		if err := decodeParam(params, 0, &filters); err != nil {
+			return nil, err
+		}
+	}
+	var opts queryOptions
+	if len(params) > 1 && string(params[1]) != "null" {
+		if err := decodeParam(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var matched []map[string]interface{}
+	for _, rec := range records {
+		ok, err := matchAll(rec, filters)
+		if err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+		}
+		if ok {
+			matched = append(matched, rec)
+		}
+	}
+
+	for i := len(opts.OrderBy) - 1; i >= 0; i-- {
+		field := opts.OrderBy[i]
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		sort.SliceStable(matched, func(a, b int) bool {
+			va, _ := lookup(matched[a], field)
+			vb, _ := lookup(matched[b], field)
+			c, _ := compare(va, vb)
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	if opts.Count {
+		return len(matched), nil
+	}
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(matched) {
+			matched = nil
+		} else {
+			matched = matched[opts.Offset:]
+		}
+	}
+	if opts.Limit > 0 && opts.Limit < len(matched) {
+		matched = matched[:opts.Limit]
+	}
+
+	if len(opts.Select) > 0 {
+		selected := make([]map[string]interface{}, len(matched))
+		for i, rec := range matched {
+			selected[i] = make(map[string]interface{}, len(opts.Select))
+			for _, field := range opts.Select {
+				if v, ok := lookup(rec, field); ok {
+					selected[i][field] = v
+				}
+			}
+		}
+		matched = selected
+	}
+
+	if opts.Get {
+		if len(matched) == 0 {
+			return nil, CallError(2, "ENOENT", "Object not found")
+		}
+		return matched[0], nil
+	}
+	if matched == nil {
+		matched = []map[string]interface{}{}
+	}
+	return matched, nil
+}
+
+// matchAll reports whether rec matches every filter in the list
+func matchAll(rec map[string]interface{}, filters []interface{}) (bool, error) {
+	for _, f := range filters {
+		ok, err := matchFilter(rec, f)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchFilter evaluates one filter: either [field, operator, value] or
+// ["OR", [filter, ...]], where each alternative may itself be a list of
+// filters that must all match
+func matchFilter(rec map[string]interface{}, f interface{}) (bool, error) {
+	filter, ok := f.([]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid filter %v", f)
+	}
+
+	if len(filter) == 2 && filter[0] == "OR" {
+		alternatives, ok := filter[1].([]interface{})
+		if !ok {
+			return false, fmt.Errorf("invalid OR filter %v", f)
+		}
+		for _, alt := range alternatives {
+			var match bool
+			var err error
+			if group, ok := alt.([]interface{}); ok && len(group) > 0 && isFilterList(group) {
+				match, err = matchAll(rec, group)
+			} else {
+				match, err = matchFilter(rec, alt)
+			}
+			if err != nil {
+				return false, err
+			}
+			if match {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if len(filter) != 3 {
+		return false, fmt.Errorf("invalid filter %v", f)
+	}
+	field, ok1 := filter[0].(string)
+	op, ok2 := filter[1].(string)
+	if !ok1 || !ok2 {
+		return false, fmt.Errorf("invalid filter %v", f)
+	}
+	value, _ := lookup(rec, field)
+	return evaluate(op, value, filter[2])
+}
+
+// isFilterList reports whether a list is a list of filters rather than a
+// single filter
+func isFilterList(list []interface{}) bool {
+	_, ok := list[0].([]interface{})
+	return ok
+}
+
+// evaluate applies a filter operator
+func evaluate(op string, value, operand interface{}) (bool, error) {
+	negate := false
+	if strings.HasPrefix(op, "!") && op != "!=" {
+		negate = true
+		op = op[1:]
+	}
+
+	var result bool
+	switch op {
+	case "=":
+		result = equal(value, operand)
+	case "!=":
+		result = !equal(value, operand)
+	case ">", ">=", "<", "<=":
+		c, ok := compare(value, operand)
+		if !ok {
+			return false, nil
+		}
+		result = (op == ">" && c > 0) || (op == ">=" && c >= 0) || (op == "<" && c < 0) || (op == "<=" && c <= 0)
+	case "in", "nin":
+		list, ok := operand.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator %s requires a list", op)
+		}
+		result = contains(list, value)
+		if op == "nin" {
+			result = !result
+		}
+	case "rin", "rnin":
+		list, ok := value.([]interface{})
+		result = ok && contains(list, operand)
+		if op == "rnin" {
+			result = !result
+		}
+	case "^", "$", "~":
+		str, ok1 := value.(string)
+		pattern, ok2 := operand.(string)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		switch op {
+		case "^":
+			result = strings.HasPrefix(str, pattern)
+		case "$":
+			result = strings.HasSuffix(str, pattern)
+		default:
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return false, fmt.Errorf("invalid regular expression %q: %v", pattern, err)
+			}
+			result = re.MatchString(str)
+		}
+	default:
+		return false, fmt.Errorf("unsupported filter operator %q", op)
+	}
+
+	if negate {
+		return !result, nil
+	}
+	return result, nil
+}
+
+// lookup resolves a possibly dotted field name in a record
+func lookup(rec map[string]interface{}, field string) (interface{}, bool) {
+	if v, ok := rec[field]; ok {
+		return v, true
+	}
+	head, rest, found := strings.Cut(field, ".")
+	if !found {
+		return nil, false
+	}
+	nested, ok := rec[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(nested, rest)
+}
+
+func equal(a, b interface{}) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	return a == nil && b == nil
+}
+
+func contains(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if equal(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare orders two JSON values of the same kind
+func compare(a, b interface{}) (int, bool) {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(av, bv), true
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if av == bv {
+			return 0, true
+		}
+		if !av {
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
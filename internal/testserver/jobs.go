@@ -0,0 +1,159 @@
+package testserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RunJob records a job for method, runs fn to completion and returns the job
+// ID for the handler to send back. The job is SUCCESS with fn's result, or
+// FAILED with its error. Subscribed clients are sent the job's final state.
+func (s *Server) RunJob(method string, params []json.RawMessage, fn func() (interface{}, error)) int64 {
+	id, finish := s.StartJob(method, params)
+	finish(fn())
+	return id
+}
+
+// StartJob records a RUNNING job for method and returns its ID and a func
+// that finishes it, for handlers whose jobs should still be running when
+// the client first looks, e.g. to report progress with SetJobProgress. The
+// func does nothing once the job has been aborted.
+func (s *Server) StartJob(method string, params []json.RawMessage) (int64, func(result interface{}, err error)) {
+	s.mu.Lock()
+	s.nextJobID++
+	id := s.nextJobID
+	args := make([]interface{}, len(params))
+	for i, p := range params {
+		_ = json.Unmarshal(p, &args[i])
+	}
+	now := float64(time.Now().UnixMilli())
+	job := map[string]interface{}{
+		"id":           float64(id),
+		"method":       method,
+		"arguments":    args,
+		"state":        "RUNNING",
+		"progress":     map[string]interface{}{"percent": 0.0, "description": "", "extra": nil},
+		"result":       nil,
+		"error":        nil,
+		"exception":    nil,
+		"logs_excerpt": nil,
+		"abortable":    false,
+		"time_started": map[string]interface{}{"$date": now},
+	}
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	return id, func(result interface{}, err error) {
+		s.mu.Lock()
+		// An aborted job stays aborted
+		if job["state"] != "RUNNING" {
+			s.mu.Unlock()
+			return
+		}
+		if err != nil {
+			job["state"] = "FAILED"
+			job["error"] = jobErrorMessage(err)
+		} else {
+			job["state"] = "SUCCESS"
+			job["result"] = cloneValue(result)
+			job["progress"] = map[string]interface{}{"percent": 100.0, "description": "Done", "extra": nil}
+		}
+		job["time_finished"] = map[string]interface{}{"$date": float64(time.Now().UnixMilli())}
+		s.mu.Unlock()
+		s.notifyJob(id)
+	}
+}
+
+// SetJobProgress updates the progress of a running job
+func (s *Server) SetJobProgress(id int64, percent float64, description string) {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		panic(fmt.Sprintf("testserver: unknown job %d", id))
+	}
+	job["progress"] = map[string]interface{}{"percent": percent, "description": description, "extra": nil}
+	s.mu.Unlock()
+	s.notifyJob(id)
+}
+
+// notifyJob sends subscribed clients the current state of a job
+func (s *Server) notifyJob(id int64) {
+	s.mu.Lock()
+	fields := clone(s.jobs[id])
+	s.mu.Unlock()
+
+	s.notify("collection_update", map[string]interface{}{
+		"msg":        "changed",
+		"collection": "core.get_jobs",
+		"id":         float64(id),
+		"fields":     fields,
+	})
+}
+
+// Job returns a job as core.get_jobs reports it
+func (s *Server) Job(id int64) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(job), true
+}
+
+// jobErrorMessage renders an error the way TrueNAS fills a failed job's
+// "error" field
+func jobErrorMessage(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		if data, ok := e.Data.(map[string]interface{}); ok {
+			if reason, ok := data["reason"].(string); ok {
+				return reason
+			}
+		}
+		return e.Message
+	}
+	return err.Error()
+}
+
+// getJobs answers core.get_jobs
+func (s *Server) getJobs(params []json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	jobs := make([]map[string]interface{}, 0, len(s.jobs))
+	for id := int64(1); id <= s.nextJobID; id++ {
+		if job, ok := s.jobs[id]; ok {
+			jobs = append(jobs, clone(job))
+		}
+	}
+	s.mu.Unlock()
+	return runQuery(jobs, params)
+}
+
+// abortJob answers core.job_abort. Like TrueNAS, it aborts a RUNNING job
+// and quietly leaves one that has already ended as it is.
+func (s *Server) abortJob(params []json.RawMessage) (interface{}, error) {
+	var id int64
+	if err := decodeParam(params, 0, &id); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, NotFound("Job %d does not exist", id)
+	}
+	running := job["state"] == "RUNNING"
+	if running {
+		job["state"] = "ABORTED"
+		job["time_finished"] = map[string]interface{}{"$date": float64(time.Now().UnixMilli())}
+	}
+	s.mu.Unlock()
+	if running {
+		s.notifyJob(id)
+	}
+	return nil, nil
+}
@@ -0,0 +1,383 @@
+package testserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// namespace is a stateful collection served through the standard
+// <name>.query, .get_instance, .create, .update and .delete methods
+type namespace struct {
+	name    string
+	records map[string]map[string]interface{}
+	nextID  int64
+
+	// key returns the ID of a new record; nil means sequential integers
+	key func(rec map[string]interface{}) interface{}
+	// defaults are applied to new records for fields the caller didn't set
+	defaults map[string]interface{}
+	// required fields must be present on create
+	required []string
+	// unique fields must not repeat across records
+	unique []string
+	// view renders a stored record the way TrueNAS returns it
+	view func(rec map[string]interface{}) map[string]interface{}
+
+	// The hooks below run with the server lock held.
+
+	// input checks a create or update payload against the running release
+	// and rewrites it into the stored shape
+	input func(s *Server, data map[string]interface{}, op string) *Error
+	// prepare checks a record before it is stored, and may fill in fields
+	// TrueNAS derives from the payload
+	prepare func(s *Server, rec map[string]interface{}, op string) *Error
+	// afterCreate and afterDelete keep dependent namespaces in step
+	afterCreate func(s *Server, rec map[string]interface{})
+	afterDelete func(s *Server, rec map[string]interface{})
+	// jobs makes create, update and delete return a job ID
+	jobs bool
+}
+
+// schemaName returns the prefix TrueNAS uses for validation attributes,
+// e.g. "sharing_smb_create"
+func (ns *namespace) schemaName(op string) string {
+	return strings.ReplaceAll(ns.name, ".", "_") + "_" + op
+}
+
+// addNamespace registers a collection. Must be called before the server is
+// used.
+func (s *Server) addNamespace(ns *namespace) {
+	ns.records = make(map[string]map[string]interface{})
+	ns.nextID = 1
+	s.namespaces[ns.name] = ns
+}
+
+// Seed inserts a record into a namespace without going through create, and
+// returns its ID. Records are stored as given; defaults are not applied.
+func (s *Server) Seed(namespaceName string, rec map[string]interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns := s.mustNamespace(namespaceName)
+	rec = clone(rec)
+	id, ok := rec["id"]
+	if !ok {
+		id = ns.newID(rec)
+		rec["id"] = id
+	}
+	ns.records[idKey(id)] = rec
+	return id
+}
+
+// Record returns a namespace record as TrueNAS would, or false if it doesn't
+// exist
+func (s *Server) Record(namespaceName string, id interface{}) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns := s.mustNamespace(namespaceName)
+	rec, ok := ns.records[idKey(id)]
+	if !ok {
+		return nil, false
+	}
+	return ns.render(rec), true
+}
+
+// Records returns every record in a namespace, ordered by ID
+func (s *Server) Records(namespaceName string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mustNamespace(namespaceName).list()
+}
+
+func (s *Server) mustNamespace(name string) *namespace {
+	ns, ok := s.namespaces[name]
+	if !ok {
+		panic(fmt.Sprintf("testserver: unknown namespace %q", name))
+	}
+	return ns
+}
+
+func (ns *namespace) newID(rec map[string]interface{}) interface{} {
+	if ns.key != nil {
+		return ns.key(rec)
+	}
+	for {
+		id := ns.nextID
+		ns.nextID++
+		if _, taken := ns.records[idKey(float64(id))]; !taken {
+			return float64(id)
+		}
+	}
+}
+
+func (ns *namespace) render(rec map[string]interface{}) map[string]interface{} {
+	out := clone(rec)
+	if ns.view != nil {
+		out = ns.view(out)
+	}
+	return out
+}
+
+// list renders all records, integer IDs in numeric order and others sorted
+// as strings
+func (ns *namespace) list() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(ns.records))
+	for _, rec := range ns.records {
+		out = append(out, ns.render(rec))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		c, ok := compare(out[i]["id"], out[j]["id"])
+		if !ok {
+			return idKey(out[i]["id"]) < idKey(out[j]["id"])
+		}
+		return c < 0
+	})
+	return out
+}
+
+// builtinCRUD returns the handler for a standard namespace method, or nil
+func (s *Server) builtinCRUD(method string) Handler {
+	i := strings.LastIndex(method, ".")
+	if i < 0 {
+		return nil
+	}
+	ns, ok := s.namespaces[method[:i]]
+	if !ok {
+		return nil
+	}
+
+	switch method[i+1:] {
+	case "query":
+		return func(params []json.RawMessage) (interface{}, error) {
+			s.mu.Lock()
+			records := ns.list()
+			s.mu.Unlock()
+			return runQuery(records, params)
+		}
+	case "get_instance":
+		return func(params []json.RawMessage) (interface{}, error) {
+			var id interface{}
+			if err := decodeParam(params, 0, &id); err != nil {
+				return nil, err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			rec, ok := ns.records[idKey(id)]
+			if !ok {
+				return nil, NotFound("%s %v does not exist", ns.name, id)
+			}
+			return ns.render(rec), nil
+		}
+	case "create":
+		return s.jobOrDirect(ns, method, func(params []json.RawMessage) (interface{}, error) {
+			var data map[string]interface{}
+			if err := decodeParam(params, 0, &data); err != nil {
+				return nil, err
+			}
+			return s.create(ns, data)
+		})
+	case "update":
+		return s.jobOrDirect(ns, method, func(params []json.RawMessage) (interface{}, error) {
+			var id interface{}
+			var data map[string]interface{}
+			if err := decodeParam(params, 0, &id); err != nil {
+				return nil, err
+			}
+			if err := decodeParam(params, 1, &data); err != nil {
+				return nil, err
+			}
+			return s.update(ns, id, data)
+		})
+	case "delete":
+		return s.jobOrDirect(ns, method, func(params []json.RawMessage) (interface{}, error) {
+			var id interface{}
+			if err := decodeParam(params, 0, &id); err != nil {
+				return nil, err
+			}
+			return true, s.delete(ns, id)
+		})
+	}
+	return nil
+}
+
+// jobOrDirect runs a mutating method inline, or as a job for namespaces whose
+// changes TrueNAS runs as jobs. Validation errors then fail the job, as they
+// do on TrueNAS.
+func (s *Server) jobOrDirect(ns *namespace, method string, h Handler) Handler {
+	if !ns.jobs {
+		return h
+	}
+	return func(params []json.RawMessage) (interface{}, error) {
+		return s.RunJob(method, params, func() (interface{}, error) {
+			return h(params)
+		}), nil
+	}
+}
+
+func (s *Server) create(ns *namespace, data map[string]interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := clone(data)
+	if ns.input != nil {
+		if err := ns.input(s, rec, "create"); err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range ns.defaults {
+		if _, ok := rec[k]; !ok {
+			rec[k] = cloneValue(v)
+		}
+	}
+	for _, field := range ns.required {
+		if v, ok := rec[field]; !ok || v == nil || v == "" {
+			return nil, ValidationError(ns.schemaName("create")+"."+field, "This field is required")
+		}
+	}
+	if err := ns.checkUnique(rec, nil); err != nil {
+		return nil, err
+	}
+	if ns.prepare != nil {
+		if err := ns.prepare(s, rec, "create"); err != nil {
+			return nil, err
+		}
+	}
+
+	id := ns.newID(rec)
+	if _, exists := ns.records[idKey(id)]; exists {
+		return nil, ValidationError(ns.schemaName("create")+".name", fmt.Sprintf("%v already exists", id))
+	}
+	rec["id"] = id
+	ns.records[idKey(id)] = rec
+
+	if ns.afterCreate != nil {
+		ns.afterCreate(s, rec)
+	}
+	return ns.render(rec), nil
+}
+
+func (s *Server) update(ns *namespace, id interface{}, data map[string]interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := ns.records[idKey(id)]
+	if !ok {
+		return nil, NotFound("%s %v does not exist", ns.name, id)
+	}
+
+	changes := clone(data)
+	if ns.input != nil {
+		if err := ns.input(s, changes, "update"); err != nil {
+			return nil, err
+		}
+	}
+	updated := clone(rec)
+	for k, v := range changes {
+		updated[k] = v
+	}
+	updated["id"] = rec["id"]
+	if err := ns.checkUnique(updated, rec["id"]); err != nil {
+		return nil, err
+	}
+	if ns.prepare != nil {
+		if err := ns.prepare(s, updated, "update"); err != nil {
+			return nil, err
+		}
+	}
+
+	ns.records[idKey(id)] = updated
+	return ns.render(updated), nil
+}
+
+func (s *Server) delete(ns *namespace, id interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := ns.records[idKey(id)]
+	if !ok {
+		return NotFound("%s %v does not exist", ns.name, id)
+	}
+	delete(ns.records, idKey(id))
+
+	if ns.afterDelete != nil {
+		ns.afterDelete(s, rec)
+	}
+	return nil
+}
+
+// checkUnique rejects a record that repeats a unique field of another record
+func (ns *namespace) checkUnique(rec map[string]interface{}, self interface{}) *Error {
+	for _, field := range ns.unique {
+		v, ok := rec[field]
+		if !ok {
+			continue
+		}
+		for _, other := range ns.records {
+			if self != nil && idKey(other["id"]) == idKey(self) {
+				continue
+			}
+			if equal(other[field], v) {
+				return ValidationError(ns.schemaName("create")+"."+field, fmt.Sprintf("%v is already in use", v))
+			}
+		}
+	}
+	return nil
+}
+
+// idKey turns an ID into a map key; integer IDs arrive as float64 or int
+func idKey(id interface{}) string {
+	switch v := id.(type) {
+	case float64:
+		return fmt.Sprintf("%d", int64(v))
+	case int:
+		return fmt.Sprintf("%d", v)
+	case int64:
+		return fmt.Sprintf("%d", v)
+	}
+	return fmt.Sprint(id)
+}
+
+// clone deep-copies a record through JSON, which also normalizes numbers to
+// float64 the way records decoded from the wire look
+func clone(rec map[string]interface{}) map[string]interface{} {
+	out, _ := cloneValue(rec).(map[string]interface{})
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	return out
+}
+
+func cloneValue(v interface{}) interface{} {
+	raw, _ := json.Marshal(v)
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var out interface{}
+	_ = dec.Decode(&out)
+	return normalizeNumbers(out)
+}
+
+// normalizeNumbers turns json.Numbers into float64s, except integers too
+// large for one, such as certificate serials, which stay exact
+func normalizeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = normalizeNumbers(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normalizeNumbers(value)
+		}
+	case json.Number:
+		if _, err := v.Int64(); err != nil && !strings.ContainsAny(v.String(), ".eE") {
+			return v
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
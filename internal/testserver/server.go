@@ -0,0 +1,345 @@
+// Package testserver implements an in-process fake of the TrueNAS JSON-RPC
+// WebSocket API, so the client and the resources can be tested without a NAS.
+//
+// The server authenticates with APIKey, keeps stateful collections for the
+// namespaces the provider manages (pool, pool.dataset, sharing.smb, vm, ...)
+// and runs jobs to completion immediately. Individual methods can be replaced
+// with Handle to simulate failures or behaviour the fake doesn't model.
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// APIKey is the API key the server accepts
+const APIKey = "1-testserver"
+
+// DefaultVersion is the release the server reports until SetVersion is
+// called. The collections model this release's API.
+const DefaultVersion = "TrueNAS-SCALE-25.04.2"
+
+// Handler answers a JSON-RPC method. params holds the positional arguments.
+// Return an *Error to control the error sent to the client; any other error
+// is reported as a generic call error.
+type Handler func(params []json.RawMessage) (interface{}, error)
+
+// Server is a fake TrueNAS API endpoint
+type Server struct {
+	httpServer *httptest.Server
+
+	mu         sync.Mutex
+	version    string
+	failover   string
+	handlers   map[string]Handler
+	methods    map[string]Handler
+	namespaces map[string]*namespace
+	configs    map[string]map[string]interface{}
+	jobs       map[int64]map[string]interface{}
+	nextJobID  int64
+	calls      map[string]int
+	conns      map[*websocket.Conn]*connState
+}
+
+// connState tracks a client connection
+type connState struct {
+	writeMu       sync.Mutex
+	authenticated bool
+	subscribed    bool
+}
+
+// New starts a server listening on a random local port with a self-signed
+// certificate. Call Close when done.
+func New() *Server {
+	s := &Server{
+		version:    DefaultVersion,
+		failover:   "SINGLE",
+		handlers:   make(map[string]Handler),
+		methods:    make(map[string]Handler),
+		namespaces: make(map[string]*namespace),
+		configs:    make(map[string]map[string]interface{}),
+		jobs:       make(map[int64]map[string]interface{}),
+		calls:      make(map[string]int),
+		conns:      make(map[*websocket.Conn]*connState),
+	}
+	s.registerNamespaces()
+	s.registerServices()
+
+	upgrader := websocket.Upgrader{}
+	s.httpServer = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/current" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.serve(conn)
+	}))
+	return s
+}
+
+// Close shuts the server down and disconnects all clients
+func (s *Server) Close() {
+	s.DropConnections()
+	s.httpServer.Close()
+}
+
+// Host returns the host:port to configure the client or provider with.
+// The certificate is self-signed, so TLS verification must be disabled.
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.httpServer.URL, "https://")
+}
+
+// ProviderConfig returns a provider block pointing at the server
+func (s *Server) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "trueform" {
+  host       = %q
+  api_key    = %q
+  verify_ssl = false
+}
+`, s.Host(), APIKey)
+}
+
+// Handle registers a handler for a method, replacing the built-in one. A
+// nil handler restores the built-in one.
+func (s *Server) Handle(method string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h == nil {
+		delete(s.handlers, method)
+		return
+	}
+	s.handlers[method] = h
+}
+
+// SetVersion changes the release reported by system.version and
+// system.info. Releases before 25.04 don't offer auth.login_ex.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetFailoverStatus changes what failover.status reports, e.g. MASTER or
+// BACKUP to act as one controller of an HA pair. It is SINGLE until set.
+func (s *Server) SetFailoverStatus(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failover = status
+}
+
+// CallCount returns how many times a method has been called
+func (s *Server) CallCount(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// Connections returns the number of open client connections
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// DropConnections closes every client connection, simulating a middleware
+// restart
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+// request is a JSON-RPC request read from a client
+type request struct {
+	ID     *int64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// rpcError is the JSON-RPC error object sent to clients
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (s *Server) serve(conn *websocket.Conn) {
+	state := &connState{}
+	s.mu.Lock()
+	s.conns[conn] = state
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		var req request
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if req.ID == nil {
+			// Notifications from the client need no answer
+			continue
+		}
+
+		result, err := s.dispatch(state, req.Method, req.Params)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": *req.ID}
+		if err != nil {
+			resp["error"] = toRPCError(err)
+		} else {
+			resp["result"] = result
+		}
+		s.write(conn, state, resp)
+	}
+}
+
+func (s *Server) write(conn *websocket.Conn, state *connState, v interface{}) {
+	state.writeMu.Lock()
+	defer state.writeMu.Unlock()
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_ = conn.WriteJSON(v)
+}
+
+// dispatch runs a method: authentication and subscriptions are handled per
+// connection, everything else by a custom or built-in handler
+func (s *Server) dispatch(state *connState, method string, rawParams json.RawMessage) (interface{}, error) {
+	var params []json.RawMessage
+	if len(rawParams) > 0 && string(rawParams) != "null" {
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: "params must be a list"}
+		}
+	}
+
+	s.mu.Lock()
+	s.calls[method]++
+	h, custom := s.handlers[method]
+	s.mu.Unlock()
+	if custom {
+		return h(params)
+	}
+
+	switch method {
+	case "auth.login_ex", "auth.login_with_api_key", "auth.login":
+		return s.login(state, method, params)
+	case "core.ping":
+		return "pong", nil
+	case "core.subscribe":
+		s.mu.Lock()
+		state.subscribed = true
+		s.mu.Unlock()
+		return "sub-" + method, nil
+	case "core.unsubscribe":
+		s.mu.Lock()
+		state.subscribed = false
+		s.mu.Unlock()
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	authenticated := state.authenticated
+	s.mu.Unlock()
+	if !authenticated {
+		return nil, &Error{Code: CodeCallError, Message: "Not authenticated", Data: errnoData(13, "EACCES", "Not authenticated", nil)}
+	}
+
+	if h := s.builtin(method); h != nil {
+		return h(params)
+	}
+	return nil, &Error{Code: CodeMethodNotFound, Message: "Method not found"}
+}
+
+// login accepts the API key in all the forms the client uses
+func (s *Server) login(state *connState, method string, params []json.RawMessage) (interface{}, error) {
+	var key string
+	switch method {
+	case "auth.login_ex":
+		s.mu.Lock()
+		version := s.version
+		s.mu.Unlock()
+		if !releaseAtLeast(version, 25, 4) {
+			return nil, &Error{Code: CodeMethodNotFound, Message: "Method not found"}
+		}
+		var creds struct {
+			Mechanism string `json:"mechanism"`
+			APIKey    string `json:"api_key"`
+		}
+		if err := decodeParam(params, 0, &creds); err != nil {
+			return nil, err
+		}
+		if creds.Mechanism != "API_KEY_PLAIN" {
+			return map[string]interface{}{"response_type": "AUTH_ERR"}, nil
+		}
+		key = creds.APIKey
+	case "auth.login_with_api_key":
+		if err := decodeParam(params, 0, &key); err != nil {
+			return nil, err
+		}
+	default:
+		return false, nil
+	}
+
+	ok := key == APIKey
+	s.mu.Lock()
+	state.authenticated = ok
+	s.mu.Unlock()
+
+	if method == "auth.login_with_api_key" {
+		return ok, nil
+	}
+	if !ok {
+		return map[string]interface{}{"response_type": "AUTH_ERR"}, nil
+	}
+	return map[string]interface{}{"response_type": "SUCCESS"}, nil
+}
+
+// notify sends a notification to every subscribed connection
+func (s *Server) notify(method string, params interface{}) {
+	s.mu.Lock()
+	var targets []*websocket.Conn
+	var states []*connState
+	for conn, state := range s.conns {
+		if state.subscribed {
+			targets = append(targets, conn)
+			states = append(states, state)
+		}
+	}
+	s.mu.Unlock()
+
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	for i, conn := range targets {
+		s.write(conn, states[i], msg)
+	}
+}
+
+// decodeParam unmarshals the i-th positional parameter into v
+func decodeParam(params []json.RawMessage, i int, v interface{}) error {
+	if i >= len(params) {
+		return &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: fmt.Sprintf("missing parameter %d", i+1)}
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: fmt.Sprintf("parameter %d: %v", i+1, err)}
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package testserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/trueform/terraform-provider-trueform/internal/client"
+	"github.com/trueform/terraform-provider-trueform/internal/testserver"
+)
+
+func newClient(t *testing.T, srv *testserver.Server) *client.Client {
+	t.Helper()
+	c := client.NewClient(&client.Config{
+		Host:    srv.Host(),
+		APIKey:  testserver.APIKey,
+		Timeout: 5 * time.Second,
+	})
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func newServer(t *testing.T) *testserver.Server {
+	t.Helper()
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAuthentication(t *testing.T) {
+	srv := newServer(t)
+
+	c := client.NewClient(&client.Config{Host: srv.Host(), APIKey: "1-wrong", Timeout: 5 * time.Second})
+	err := c.Connect(context.Background())
+	if !client.IsAuthError(err) {
+		t.Fatalf("Connect() with wrong key error = %v, want auth error", err)
+	}
+
+	newClient(t, srv)
+}
+
+func TestCRUD(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	var created map[string]interface{}
+	err := c.Create(ctx, "staticroute", map[string]interface{}{
+		"destination": "10.0.0.0/8",
+		"gateway":     "192.168.1.1",
+	}, &created)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if created["id"] != 1.0 || created["description"] != "" {
+		t.Errorf("Create() = %v, want id 1 with default description", created)
+	}
+
+	var updated map[string]interface{}
+	if err := c.Update(ctx, "staticroute", 1, map[string]interface{}{"description": "lab"}, &updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated["description"] != "lab" || updated["gateway"] != "192.168.1.1" {
+		t.Errorf("Update() = %v, want description changed and gateway kept", updated)
+	}
+
+	var got map[string]interface{}
+	if err := c.GetInstance(ctx, "staticroute", 1, &got); err != nil {
+		t.Fatalf("GetInstance() error = %v", err)
+	}
+	if got["description"] != "lab" {
+		t.Errorf("GetInstance() = %v", got)
+	}
+
+	if err := c.Delete(ctx, "staticroute", 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	err = c.GetInstance(ctx, "staticroute", 1, &got)
+	if !client.IsNotFoundError(err) {
+		t.Errorf("GetInstance() after delete error = %v, want not found", err)
+	}
+	if !errors.Is(c.Delete(ctx, "staticroute", 1), client.ErrNotFound) {
+		t.Error("Delete() of missing record should match ErrNotFound")
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	err := c.Create(ctx, "sharing.smb", map[string]interface{}{
+		"name": "media",
+		"path": "/mnt/tank/media",
+	}, nil)
+	failures := client.ValidationFailures(err)
+	if len(failures) != 1 || failures[0].Field() != "path" {
+		t.Fatalf("ValidationFailures() = %v, want one failure on path", failures)
+	}
+
+	err = c.Create(ctx, "sharing.smb", map[string]interface{}{"path": "/mnt/tank"}, nil)
+	failures = client.ValidationFailures(err)
+	if len(failures) != 1 || failures[0].Attribute != "sharing_smb_create.name" {
+		t.Fatalf("ValidationFailures() = %v, want name required", failures)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	for _, name := range []string{"carol", "alice", "bob"} {
+		srv.Seed("group", map[string]interface{}{"group": name, "gid": 1000.0, "builtin": name == "bob"})
+	}
+
+	tests := []struct {
+		name   string
+		params *client.QueryParams
+		want   []string
+	}{
+		{
+			name:   "all",
+			params: nil,
+			want:   []string{"carol", "alice", "bob"},
+		},
+		{
+			name:   "filter",
+			params: &client.QueryParams{Filters: [][]interface{}{{"builtin", "=", false}}},
+			want:   []string{"carol", "alice"},
+		},
+		{
+			name:   "order and limit",
+			params: &client.QueryParams{OrderBy: []string{"group"}, Limit: 2},
+			want:   []string{"alice", "bob"},
+		},
+		{
+			name:   "descending with offset",
+			params: &client.QueryParams{OrderBy: []string{"-group"}, Offset: 1},
+			want:   []string{"bob", "alice"},
+		},
+		{
+			name:   "prefix",
+			params: &client.QueryParams{Filters: [][]interface{}{{"group", "^", "a"}}},
+			want:   []string{"alice"},
+		},
+		{
+			name: "or",
+			params: &client.QueryParams{Filters: [][]interface{}{
+				{"OR", []interface{}{
+					[]interface{}{"group", "=", "alice"},
+					[]interface{}{"group", "=", "carol"},
+				}},
+			}},
+			want: []string{"carol", "alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []map[string]interface{}
+			if err := c.Query(ctx, "group", tt.params, &result); err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			var got []string
+			for _, rec := range result {
+				got = append(got, rec["group"].(string))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Query() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	var count int
+	if err := c.Query(ctx, "group", &client.QueryParams{Count: true}, &count); err != nil || count != 3 {
+		t.Errorf("Query(count) = %d, %v; want 3", count, err)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	pool, err := c.CreateWithJob(ctx, "pool", map[string]interface{}{
+		"name":     "tank",
+		"topology": map[string]interface{}{"data": []interface{}{map[string]interface{}{"type": "MIRROR", "disks": []string{"sdb", "sdc"}}}},
+	}, 10*time.Second, nil)
+	if err != nil {
+		t.Fatalf("CreateWithJob() error = %v", err)
+	}
+	if pool["path"] != "/mnt/tank" {
+		t.Errorf("CreateWithJob() = %v, want path /mnt/tank", pool)
+	}
+	if _, ok := srv.Record("pool.dataset", "tank"); !ok {
+		t.Error("pool.create should add the root dataset")
+	}
+
+	_, err = c.CreateWithJob(ctx, "pool", map[string]interface{}{"name": "tank", "topology": map[string]interface{}{}}, 10*time.Second, nil)
+	if !client.IsJobError(err) {
+		t.Errorf("CreateWithJob() duplicate error = %v, want job error", err)
+	}
+
+	var jobID int64
+	if err := c.Call(ctx, "pool.export", []interface{}{pool["id"], map[string]interface{}{"destroy": true}}, &jobID); err != nil {
+		t.Fatalf("pool.export error = %v", err)
+	}
+	if _, err := c.WaitForJob(ctx, jobID, 10*time.Second, nil); err != nil {
+		t.Fatalf("WaitForJob() error = %v", err)
+	}
+	if len(srv.Records("pool.dataset")) != 0 {
+		t.Error("pool.export should remove the pool's datasets")
+	}
+}
+
+func TestJobAbort(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+
+	id, finish := srv.StartJob("pool.scrub.run", nil)
+	_, err := c.WaitForJob(context.Background(), id, 100*time.Millisecond, nil)
+	var cancelErr *client.JobCancelledError
+	if !errors.As(err, &cancelErr) || !cancelErr.Aborted {
+		t.Fatalf("WaitForJob() error = %v, want the job aborted", err)
+	}
+
+	// Finishing an aborted job leaves it aborted
+	finish(nil, nil)
+	if job, _ := srv.Job(id); job["state"] != "ABORTED" {
+		t.Errorf("job state = %v, want ABORTED", job["state"])
+	}
+
+	// Aborting a finished job is a no-op
+	done := srv.RunJob("pool.scrub.run", nil, func() (interface{}, error) { return nil, nil })
+	if err := c.Call(context.Background(), "core.job_abort", []interface{}{done}, nil); err != nil {
+		t.Fatalf("core.job_abort error = %v", err)
+	}
+	if job, _ := srv.Job(done); job["state"] != "SUCCESS" {
+		t.Errorf("job state = %v, want SUCCESS", job["state"])
+	}
+}
+
+func TestDatasetDelete(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	srv.Seed("pool.dataset", map[string]interface{}{"id": "tank", "name": "tank"})
+	for _, name := range []string{"tank/a", "tank/a/b"} {
+		if err := c.Create(ctx, "pool.dataset", map[string]interface{}{"name": name}, nil); err != nil {
+			t.Fatalf("Create(%s) error = %v", name, err)
+		}
+	}
+	if err := c.Create(ctx, "pool.dataset", map[string]interface{}{"name": "tank/x/y"}, nil); !client.IsValidationError(err) {
+		t.Errorf("Create() without parent error = %v, want validation error", err)
+	}
+
+	err := c.Delete(ctx, "pool.dataset", "tank/a")
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrName != "EBUSY" {
+		t.Fatalf("Delete() with children error = %v, want EBUSY", err)
+	}
+	if err := c.DeleteWithOptions(ctx, "pool.dataset", "tank/a", map[string]interface{}{"recursive": true}); err != nil {
+		t.Fatalf("DeleteWithOptions() error = %v", err)
+	}
+	if len(srv.Records("pool.dataset")) != 1 {
+		t.Errorf("datasets after recursive delete = %v", srv.Records("pool.dataset"))
+	}
+}
+
+func TestSMBShareShape(t *testing.T) {
+	srv := newServer(t)
+	srv.SetVersion("TrueNAS-SCALE-25.10.0")
+	srv.Seed("pool.dataset", map[string]interface{}{"id": "tank/media", "name": "tank/media"})
+	c := newClient(t, srv)
+
+	// 25.10 rejects the old names
+	err := c.Create(context.Background(), "sharing.smb", map[string]interface{}{
+		"name": "media",
+		"path": "/mnt/tank/media",
+		"ro":   true,
+	}, nil)
+	if !client.IsValidationError(err) {
+		t.Errorf("Create() with ro error = %v, want validation error", err)
+	}
+
+	// It takes and returns the new shape
+	var share map[string]interface{}
+	err = c.Create(context.Background(), "sharing.smb", map[string]interface{}{
+		"name":     "media",
+		"path":     "/mnt/tank/media",
+		"readonly": true,
+		"options": map[string]interface{}{
+			"hostsallow":  []string{"10.0.0.0/8"},
+			"timemachine": true,
+		},
+	}, &share)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	options, _ := share["options"].(map[string]interface{})
+	if share["readonly"] != true || share["access_based_share_enumeration"] != false || len(options["hostsallow"].([]interface{})) != 1 {
+		t.Errorf("share = %v, want the 25.10 shape", share)
+	}
+	for _, key := range []string{"ro", "abe", "hostsallow", "timemachine"} {
+		if _, ok := share[key]; ok {
+			t.Errorf("share has %s, which 25.10 doesn't return", key)
+		}
+	}
+
+	srv.SetVersion(testserver.DefaultVersion)
+	if rec, _ := srv.Record("sharing.smb", share["id"]); rec["ro"] != true || rec["timemachine"] != true {
+		t.Errorf("share = %v on 25.04, want the old shape", rec)
+	}
+	err = c.Update(context.Background(), "sharing.smb", share["id"], map[string]interface{}{"readonly": false}, nil)
+	if !client.IsValidationError(err) {
+		t.Errorf("Update() with readonly on 25.04 error = %v, want validation error", err)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv)
+
+	srv.Handle("system.info", func(params []json.RawMessage) (interface{}, error) {
+		return map[string]interface{}{"version": "25.04.0"}, nil
+	})
+	var info map[string]interface{}
+	if err := c.Call(context.Background(), "system.info", []interface{}{}, &info); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if info["version"] != "25.04.0" {
+		t.Errorf("Call() = %v", info)
+	}
+	if srv.CallCount("system.info") != 1 {
+		t.Errorf("CallCount() = %d, want 1", srv.CallCount("system.info"))
+	}
+
+	err := c.Call(context.Background(), "system.unknown", []interface{}{}, nil)
+	if !client.IsMethodNotFoundError(err) {
+		t.Errorf("Call() unknown method error = %v, want method not found", err)
+	}
+}
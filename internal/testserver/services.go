@@ -0,0 +1,175 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// builtin returns the server's own handler for a method, or nil
+func (s *Server) builtin(method string) Handler {
+	switch method {
+	case "core.get_jobs":
+		return s.getJobs
+	case "core.job_abort":
+		return s.abortJob
+	}
+	if h, ok := s.methods[method]; ok {
+		return h
+	}
+	return s.builtinCRUD(method)
+}
+
+// registerServices sets up the singleton configs (<name>.config and
+// <name>.update) and the service namespace
+func (s *Server) registerServices() {
+	s.configs["docker"] = map[string]interface{}{
+		"id":                   float64(1),
+		"pool":                 nil,
+		"nvidia":               false,
+		"enable_image_updates": true,
+		"address_pools":        []interface{}{},
+	}
+	s.configs["nfs"] = map[string]interface{}{
+		"id":                float64(1),
+		"servers":           float64(4),
+		"allow_nonroot":     false,
+		"protocols":         []interface{}{"NFSV3", "NFSV4"},
+		"v4":                true,
+		"v4_v3owner":        false,
+		"v4_krb":            false,
+		"v4_domain":         "",
+		"bindip":            []interface{}{},
+		"mountd_port":       nil,
+		"rpcstatd_port":     nil,
+		"rpclockd_port":     nil,
+		"udp":               false,
+		"userd_manage_gids": false,
+		"managed_nfsv4_acl": false,
+	}
+
+	s.methods["system.version"] = func(params []json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.version, nil
+	}
+	s.methods["failover.status"] = func(params []json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.failover, nil
+	}
+	s.methods["system.info"] = func(params []json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return map[string]interface{}{
+			"version":  s.version,
+			"hostname": "truenas",
+			"cores":    float64(4),
+			"physmem":  float64(8 << 30),
+		}, nil
+	}
+
+	s.methods["docker.config"] = s.getConfig("docker")
+	s.methods["nfs.config"] = s.getConfig("nfs")
+	s.methods["nfs.update"] = s.updateConfig("nfs")
+	s.methods["docker.update"] = func(params []json.RawMessage) (interface{}, error) {
+		return s.RunJob("docker.update", params, func() (interface{}, error) {
+			return s.updateConfig("docker")(params)
+		}), nil
+	}
+	s.methods["docker.status"] = func(params []json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.configs["docker"]["pool"] == nil {
+			return map[string]interface{}{"status": "UNCONFIGURED", "description": ""}, nil
+		}
+		return map[string]interface{}{"status": "RUNNING", "description": ""}, nil
+	}
+
+	s.addNamespace(&namespace{name: "service", key: func(rec map[string]interface{}) interface{} { return rec["service"] }})
+	for i, name := range []string{"cifs", "docker", "iscsitarget", "nfs", "ssh", "ups"} {
+		s.namespaces["service"].records[name] = map[string]interface{}{
+			"id": float64(i + 1), "service": name, "enable": false, "state": "STOPPED", "pids": []interface{}{},
+		}
+	}
+	s.methods["service.start"] = s.serviceAction("RUNNING")
+	s.methods["service.restart"] = s.serviceAction("RUNNING")
+	s.methods["service.reload"] = s.serviceAction("RUNNING")
+	s.methods["service.stop"] = s.serviceAction("STOPPED")
+}
+
+func (s *Server) getConfig(name string) Handler {
+	return func(params []json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return clone(s.configs[name]), nil
+	}
+}
+
+func (s *Server) updateConfig(name string) Handler {
+	return func(params []json.RawMessage) (interface{}, error) {
+		var data map[string]interface{}
+		if err := decodeParam(params, 0, &data); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		config := s.configs[name]
+		for k, v := range data {
+			if _, ok := config[k]; !ok {
+				return nil, ValidationError(name+"_update."+k, "Field was not expected")
+			}
+			config[k] = v
+		}
+		return clone(config), nil
+	}
+}
+
+// serviceAction returns a handler for service.start, .stop and friends,
+// which take the service name and answer whether the service ends up in
+// the expected state
+func (s *Server) serviceAction(state string) Handler {
+	return func(params []json.RawMessage) (interface{}, error) {
+		var name string
+		if err := decodeParam(params, 0, &name); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		service, ok := s.namespaces["service"].records[name]
+		if !ok {
+			return nil, NotFound("service %s does not exist", name)
+		}
+		service["state"] = state
+		return true, nil
+	}
+}
+
+// SetConfig replaces fields of a singleton config such as "nfs" or "docker"
+func (s *Server) SetConfig(name string, fields map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config, ok := s.configs[name]
+	if !ok {
+		panic(fmt.Sprintf("testserver: unknown config %q", name))
+	}
+	for k, v := range clone(fields) {
+		config[k] = v
+	}
+}
+
+// releasePattern finds the release number in a version string such as
+// "TrueNAS-SCALE-25.04.2"
+var releasePattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// releaseAtLeast reports whether version is major.minor or newer
+func releaseAtLeast(version string, major, minor int) bool {
+	m := releasePattern.FindStringSubmatch(version)
+	if m == nil {
+		return true
+	}
+	gotMajor, _ := strconv.Atoi(m[1])
+	gotMinor, _ := strconv.Atoi(m[2])
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 	"github.com/trueform/terraform-provider-trueform/internal/provider"
 )
 
@@ -18,13 +20,31 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := providerserver.ServeOpts{
-		Address: "registry.terraform.io/trueform/trueform",
-		Debug:   debug,
+	ctx := context.Background()
+	// Tracing is best effort; a broken exporter setup must not fail applies
+	stopTracing, err := provider.StartTracing(ctx, version)
+	if err != nil {
+		log.Printf("tracing disabled: %s", err)
+		stopTracing = func(context.Context) error { return nil }
+	}
+
+	var opts []tf6server.ServeOpt
+	if debug {
+		opts = append(opts, tf6server.WithManagedDebug())
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	err = tf6server.Serve("registry.terraform.io/trueform/trueform", func() tfprotov6.ProviderServer {
+		return provider.NewTracingServer(providerserver.NewProtocol6(provider.New(version)())(), nil)
+	}, opts...)
 
+	// Close the API clients, flushing their wire logs, and the spans
+	// before exiting
+	if shutdownErr := provider.Shutdown(); shutdownErr != nil {
+		log.Printf("failed to close the API clients: %s", shutdownErr)
+	}
+	if stopErr := stopTracing(ctx); stopErr != nil {
+		log.Printf("failed to export traces: %s", stopErr)
+	}
 	if err != nil {
 		log.Fatal(err.Error())
 	}